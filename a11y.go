@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// unlabeledInputTypes are the <input> types that need no label: they are hidden or
+// named by their value or image
+var unlabeledInputTypes = stringSet([]string{"hidden", "submit", "reset", "button", "image"})
+
+// needsLabel reports whether an element is a form control that needs a label
+func needsLabel(n *html.Node) bool {
+	switch n.Data {
+	case "select", "textarea":
+		return true
+	case "input":
+		inputType, _ := attr(n, "type")
+		return !unlabeledInputTypes[strings.ToLower(strings.TrimSpace(inputType))]
+	}
+	return false
+}
+
+// insideLabel reports whether an element is nested in a <label>
+func insideLabel(n *html.Node) bool {
+	for p := n.Parent; p != nil; p = p.Parent {
+		if p.Type == html.ElementNode && p.Data == "label" {
+			return true
+		}
+	}
+	return false
+}
+
+// controlName describes a form control by its id or name for issue messages
+func controlName(n *html.Node) string {
+	for _, key := range []string{"id", "name"} {
+		if value, ok := attr(n, key); ok && value != "" {
+			return fmt.Sprintf("<%s %s=%q>", n.Data, key, value)
+		}
+	}
+	return "<" + n.Data + ">"
+}
+
+// checkA11y runs basic accessibility checks on a page: images need alt text, form
+// controls need a label and the <html> element needs a lang attribute
+func checkA11y(result *Result, page *Page) {
+	add := func(code, msg string) {
+		result.A11yIssues = append(result.A11yIssues, msg)
+		result.addIssue(code, msg)
+	}
+
+	// Controls can be labelled by a <label for>, a wrapping <label> or an ARIA label
+	labelled := make(map[string]bool)
+	walkHTML(page.Doc, func(n *html.Node) {
+		if n.Data == "label" {
+			if id, ok := attr(n, "for"); ok {
+				labelled[id] = true
+			}
+		}
+	})
+
+	walkHTML(page.Doc, func(n *html.Node) {
+		switch {
+		case n.Data == "img":
+			if alt, _ := attr(n, "alt"); strings.TrimSpace(alt) == "" {
+				src, _ := attr(n, "src")
+				add("A11Y_IMG_MISSING_ALT", fmt.Sprintf("<img src=%q> has no alt text", src))
+			}
+		case needsLabel(n):
+			id, _ := attr(n, "id")
+			ariaLabel, _ := attr(n, "aria-label")
+			_, ariaLabelledBy := attr(n, "aria-labelledby")
+			if (id != "" && labelled[id]) || insideLabel(n) || strings.TrimSpace(ariaLabel) != "" || ariaLabelledBy {
+				return
+			}
+			add("A11Y_FORM_NO_LABEL", fmt.Sprintf("%s has no associated <label>", controlName(n)))
+		}
+	})
+
+	if lang, _ := page.htmlLang(); lang == "" {
+		add("A11Y_MISSING_LANG", "<html> element has no lang attribute")
+	}
+}
+
+// countA11yIssues returns the total number of accessibility issues and the number of pages with any
+func countA11yIssues(results []Result) (issues, pages int) {
+	for _, result := range results {
+		issues += len(result.A11yIssues)
+		if len(result.A11yIssues) > 0 {
+			pages++
+		}
+	}
+	return issues, pages
+}
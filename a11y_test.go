@@ -0,0 +1,61 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+// Test for checkA11y function
+func TestCheckA11y(t *testing.T) {
+	page := parseTestPage(t, "https://example.com/", `<html><body>
+		<img src="/logo.png" alt="Logo">
+		<img src="/spacer.gif" alt=" ">
+		<img src="/photo.jpg">
+		<form>
+			<label for="email">Email</label><input id="email" type="email">
+			<label>Name <input name="name"></label>
+			<input type="search" aria-label="Search">
+			<input type="hidden" name="token">
+			<input type="submit">
+			<select name="country"></select>
+			<textarea id="comment"></textarea>
+		</form>
+		</body></html>`)
+
+	var result Result
+	checkA11y(&result, page)
+	want := []string{
+		`<img src="/spacer.gif"> has no alt text`,
+		`<img src="/photo.jpg"> has no alt text`,
+		`<select name="country"> has no associated <label>`,
+		`<textarea id="comment"> has no associated <label>`,
+		"<html> element has no lang attribute",
+	}
+	if !reflect.DeepEqual(result.A11yIssues, want) {
+		t.Errorf("A11yIssues = %q, want %q", result.A11yIssues, want)
+	}
+
+	codes := make(map[string]int)
+	for _, issue := range result.Issues {
+		codes[issue.Code]++
+	}
+	wantCodes := map[string]int{"A11Y_IMG_MISSING_ALT": 2, "A11Y_FORM_NO_LABEL": 2, "A11Y_MISSING_LANG": 1}
+	if !reflect.DeepEqual(codes, wantCodes) {
+		t.Errorf("issue codes = %v, want %v", codes, wantCodes)
+	}
+	if issues, pages := countA11yIssues([]Result{result, {}}); issues != 5 || pages != 1 {
+		t.Errorf("countA11yIssues() = %d, %d, want 5, 1", issues, pages)
+	}
+}
+
+// Test that an accessible page has no issues
+func TestCheckA11yClean(t *testing.T) {
+	page := parseTestPage(t, "https://example.com/", `<html lang="en"><body>
+		<img src="/logo.png" alt="Logo"><input id="q" aria-labelledby="q-label"></body></html>`)
+
+	var result Result
+	checkA11y(&result, page)
+	if len(result.A11yIssues) != 0 || len(result.Issues) != 0 {
+		t.Errorf("A11yIssues = %q, want none", result.A11yIssues)
+	}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"regexp"
+	"time"
+)
+
+// commonLogPattern matches the timestamp and request line of a Common Log Format entry,
+// which is also the prefix of the Combined Log Format used by Apache and Nginx
+var commonLogPattern = regexp.MustCompile(`^\S+ \S+ \S+ \[([^\]]+)\] "\S+ (\S+)[^"]*"`)
+
+// commonLogTimeLayout is the timestamp format of the Common Log Format
+const commonLogTimeLayout = "02/Jan/2006:15:04:05 -0700"
+
+// parseAccessLog returns the set of paths requested in an access log at or after since.
+// A zero since includes every entry. Lines that don't match the log format are skipped.
+func parseAccessLog(r io.Reader, since time.Time) (map[string]bool, error) {
+	paths := make(map[string]bool)
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		match := commonLogPattern.FindStringSubmatch(scanner.Text())
+		if match == nil {
+			continue
+		}
+
+		if !since.IsZero() {
+			timestamp, err := time.Parse(commonLogTimeLayout, match[1])
+			if err != nil || timestamp.Before(since) {
+				continue
+			}
+		}
+
+		if requestURL, err := url.Parse(match[2]); err == nil {
+			paths[requestURL.Path] = true
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read access log: %w", err)
+	}
+	return paths, nil
+}
+
+// findNeverCrawled returns the sitemap URLs whose path does not appear in the crawled set
+func findNeverCrawled(urls []URL, crawled map[string]bool) []string {
+	var neverCrawled []string
+	for _, u := range urls {
+		parsedURL, err := url.Parse(u.Loc)
+		if err != nil {
+			continue
+		}
+		path := parsedURL.Path
+		if path == "" {
+			path = "/"
+		}
+		if !crawled[path] {
+			neverCrawled = append(neverCrawled, u.Loc)
+		}
+	}
+	return neverCrawled
+}
+
+// checkAccessLog reports sitemap URLs never requested in the access log within sinceDays
+func checkAccessLog(logPath string, sinceDays int, urls []URL, logger *Logger) (int, error) {
+	file, err := os.Open(logPath)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open access log: %w", err)
+	}
+	defer file.Close()
+
+	var since time.Time
+	if sinceDays > 0 {
+		since = time.Now().AddDate(0, 0, -sinceDays)
+	}
+
+	crawled, err := parseAccessLog(file, since)
+	if err != nil {
+		return 0, err
+	}
+
+	neverCrawled := findNeverCrawled(urls, crawled)
+	for _, u := range neverCrawled {
+		msg := fmt.Sprintf("NEVER_CRAWLED: %s", u)
+		fmt.Println(msg)
+		if logger != nil {
+			logger.Log(msg)
+		}
+	}
+	return len(neverCrawled), nil
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// Test for parseAccessLog function
+func TestParseAccessLog(t *testing.T) {
+	accessLog := `66.249.66.1 - - [10/Oct/2026:13:55:36 +0000] "GET /recent?page=2 HTTP/1.1" 200 2326 "-" "Googlebot/2.1"
+66.249.66.1 - frank [01/Jan/2026:08:00:00 +0000] "GET /old HTTP/1.1" 200 512
+not a log line
+10.0.0.1 - - [11/Oct/2026:09:00:00 +0000] "HEAD / HTTP/1.1" 200 0
+`
+
+	tests := []struct {
+		name  string
+		since time.Time
+		want  []string
+	}{
+		{name: "all entries", want: []string{"/recent", "/old", "/"}},
+		{name: "since october", since: time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC), want: []string{"/recent", "/"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseAccessLog(strings.NewReader(accessLog), tt.since)
+			if err != nil {
+				t.Fatalf("parseAccessLog() error = %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Errorf("parseAccessLog() = %v, want %v", got, tt.want)
+			}
+			for _, path := range tt.want {
+				if !got[path] {
+					t.Errorf("parseAccessLog() missing path %s", path)
+				}
+			}
+		})
+	}
+}
+
+// Test for findNeverCrawled function
+func TestFindNeverCrawled(t *testing.T) {
+	urls := []URL{
+		{Loc: "https://example.com"},
+		{Loc: "https://example.com/crawled"},
+		{Loc: "https://example.com/orphan"},
+	}
+	crawled := map[string]bool{"/": true, "/crawled": true}
+
+	got := findNeverCrawled(urls, crawled)
+	want := []string{"https://example.com/orphan"}
+	if !equalStringSlices(got, want) {
+		t.Errorf("findNeverCrawled() = %v, want %v", got, want)
+	}
+}
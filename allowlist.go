@@ -0,0 +1,70 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+)
+
+// parseAllowlist reads one URL prefix or glob pattern per line, skipping blank lines and # comments
+func parseAllowlist(r io.Reader) ([]string, error) {
+	var patterns []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if _, err := path.Match(line, ""); err != nil {
+			return nil, fmt.Errorf("invalid allowlist pattern %q: %w", line, err)
+		}
+		patterns = append(patterns, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read URL allowlist: %w", err)
+	}
+	return patterns, nil
+}
+
+// loadAllowlist reads the allowlist file at path
+func loadAllowlist(allowlistPath string) ([]string, error) {
+	file, err := os.Open(allowlistPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open URL allowlist: %w", err)
+	}
+	defer file.Close()
+	return parseAllowlist(file)
+}
+
+// matchesAllowlist reports whether a URL starts with one of the patterns. Patterns with
+// glob characters are matched with path.Match against the URL and each of its prefixes
+// ending before a slash, so https://example.com/*/products allows every URL below it.
+func matchesAllowlist(loc string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if !strings.ContainsAny(pattern, "*?[") {
+			if strings.HasPrefix(loc, pattern) {
+				return true
+			}
+			continue
+		}
+
+		if ok, _ := path.Match(pattern, loc); ok {
+			return true
+		}
+		for i := range loc {
+			if loc[i] != '/' {
+				continue
+			}
+			if ok, _ := path.Match(pattern, loc[:i]); ok {
+				return true
+			}
+			if ok, _ := path.Match(pattern, loc[:i+1]); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
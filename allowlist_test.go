@@ -0,0 +1,50 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// Test for parseAllowlist function
+func TestParseAllowlist(t *testing.T) {
+	input := `# Sections allowed in the sitemap
+https://example.com/en/
+
+https://example.com/*/products/
+`
+	patterns, err := parseAllowlist(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("parseAllowlist() error = %v", err)
+	}
+	want := []string{"https://example.com/en/", "https://example.com/*/products/"}
+	if !equalStringSlices(patterns, want) {
+		t.Errorf("parseAllowlist() = %v, want %v", patterns, want)
+	}
+
+	if _, err := parseAllowlist(strings.NewReader("https://example.com/[a-\n")); err == nil {
+		t.Error("parseAllowlist() accepted a malformed glob pattern")
+	}
+}
+
+// Test for matchesAllowlist function
+func TestMatchesAllowlist(t *testing.T) {
+	patterns := []string{"https://example.com/en/", "https://example.com/*/products/"}
+
+	tests := []struct {
+		url  string
+		want bool
+	}{
+		{url: "https://example.com/en/about", want: true},
+		{url: "https://example.com/en/", want: true},
+		{url: "https://example.com/de/about", want: false},
+		{url: "https://example.com/de/products/shoes/red", want: true},
+		{url: "https://example.com/de/blog/products/", want: false},
+		{url: "https://example.com/", want: false},
+	}
+
+	for _, tt := range tests {
+		if got := matchesAllowlist(tt.url, patterns); got != tt.want {
+			t.Errorf("matchesAllowlist(%q) = %v, want %v", tt.url, got, tt.want)
+		}
+	}
+}
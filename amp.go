@@ -0,0 +1,44 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// checkAMP verifies that the AMP version a page links to with rel=amphtml is
+// reachable and declares the page as its canonical URL
+func checkAMP(client *http.Client, result *Result, page *Page) {
+	ampURL := page.firstRelLink("amphtml")
+	if ampURL == "" {
+		return
+	}
+	result.AMPURL = ampURL
+
+	status, err := headCheck(client, ampURL)
+	if isBroken(status, err) {
+		if err != nil {
+			result.addIssue("AMP_BROKEN", fmt.Sprintf("%s: %v", ampURL, err))
+		} else {
+			result.addIssue("AMP_BROKEN", fmt.Sprintf("%s (Status: %d)", ampURL, status))
+		}
+		return
+	}
+
+	ampPage, err := fetchPage(client, ampURL)
+	if err != nil {
+		result.addIssue("AMP_BROKEN", fmt.Sprintf("%s: %v", ampURL, err))
+		return
+	}
+
+	canonical := ampPage.firstRelLink("canonical")
+	if canonical != result.URL {
+		if canonical == "" {
+			result.addIssue("AMP_CANONICAL_MISMATCH", fmt.Sprintf("%s has no rel=canonical link", ampURL))
+		} else {
+			result.addIssue("AMP_CANONICAL_MISMATCH", fmt.Sprintf("%s has rel=canonical %s", ampURL, canonical))
+		}
+		return
+	}
+
+	result.AMPValid = true
+}
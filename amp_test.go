@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Test for checkAMP function
+func TestCheckAMP(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/amp/good":
+			fmt.Fprint(w, `<html><head><link rel="canonical" href="/good"></head></html>`)
+		case "/amp/other":
+			fmt.Fprint(w, `<html><head><link rel="canonical" href="/elsewhere"></head></html>`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name      string
+		page      string
+		body      string
+		wantCode  string
+		wantValid bool
+	}{
+		{name: "valid pair", page: "/good", body: `<link rel="amphtml" href="/amp/good">`, wantValid: true},
+		{name: "broken amp", page: "/broken", body: `<link rel="amphtml" href="/amp/missing">`, wantCode: "AMP_BROKEN"},
+		{name: "wrong canonical", page: "/other", body: `<link rel="amphtml" href="/amp/other">`, wantCode: "AMP_CANONICAL_MISMATCH"},
+		{name: "no amp", page: "/plain", body: `<title>Plain</title>`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Result{URL: server.URL + tt.page, Status: http.StatusOK}
+			page := parseTestPage(t, result.URL, `<html><head>`+tt.body+`</head></html>`)
+			checkAMP(server.Client(), &result, page)
+
+			if result.AMPValid != tt.wantValid {
+				t.Errorf("AMPValid = %v, want %v", result.AMPValid, tt.wantValid)
+			}
+			var got string
+			if len(result.Issues) > 0 {
+				got = result.Issues[0].Code
+			}
+			if got != tt.wantCode || len(result.Issues) > 1 {
+				t.Errorf("checkAMP() issues = %+v, want %q", result.Issues, tt.wantCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// brokenInPageAnchors returns the fragments of the page's <a href="#..."> links that match
+// no id or name attribute on the page. An empty fragment and "top" scroll to the top of
+// the page, so they always resolve.
+func (p *Page) brokenInPageAnchors() []string {
+	targets := make(map[string]bool)
+	var fragments []string
+	walkHTML(p.Doc, func(n *html.Node) {
+		for _, key := range []string{"id", "name"} {
+			if value, ok := attr(n, key); ok && value != "" {
+				targets[value] = true
+			}
+		}
+		if n.Data != "a" {
+			return
+		}
+		if href, ok := attr(n, "href"); ok && strings.HasPrefix(strings.TrimSpace(href), "#") {
+			fragments = append(fragments, strings.TrimSpace(href)[1:])
+		}
+	})
+
+	seen := make(map[string]bool)
+	var broken []string
+	for _, fragment := range fragments {
+		if fragment == "" || strings.EqualFold(fragment, "top") || seen[fragment] {
+			continue
+		}
+		seen[fragment] = true
+		if targets[fragment] {
+			continue
+		}
+		if decoded, err := url.PathUnescape(fragment); err == nil && targets[decoded] {
+			continue
+		}
+		broken = append(broken, "#"+fragment)
+	}
+	return broken
+}
+
+// checkInPageAnchors flags the in-page anchor links whose target is not on the page
+func checkInPageAnchors(result *Result, page *Page) {
+	result.BrokenAnchors = page.brokenInPageAnchors()
+	for _, anchor := range result.BrokenAnchors {
+		result.addIssue("BROKEN_ANCHOR", fmt.Sprintf("%s matches no id or name on the page", anchor))
+	}
+}
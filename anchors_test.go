@@ -0,0 +1,32 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+// Test for checkInPageAnchors function
+func TestCheckInPageAnchors(t *testing.T) {
+	page := parseTestPage(t, "https://example.com/", `<html><body>
+		<a href="#intro">Intro</a>
+		<a href="#legacy">Legacy</a>
+		<a href="#caf%C3%A9">Café</a>
+		<a href="#missing">Missing</a>
+		<a href="#missing">Missing again</a>
+		<a href="#">Top</a>
+		<a href="#top">Top</a>
+		<a href="/other#nowhere">Other page</a>
+		<h2 id="intro">Intro</h2>
+		<a name="legacy"></a>
+		<h2 id="café">Café</h2>
+		</body></html>`)
+
+	var result Result
+	checkInPageAnchors(&result, page)
+	if want := []string{"#missing"}; !reflect.DeepEqual(result.BrokenAnchors, want) {
+		t.Errorf("BrokenAnchors = %v, want %v", result.BrokenAnchors, want)
+	}
+	if len(result.Issues) != 1 || result.Issues[0].Code != "BROKEN_ANCHOR" {
+		t.Errorf("issues = %v, want one BROKEN_ANCHOR", result.Issues)
+	}
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"golang.org/x/net/html"
+)
+
+// assetStatus is the outcome of checking an asset, filled in once
+type assetStatus struct {
+	once   sync.Once
+	status int
+	err    error
+}
+
+// AssetChecker HEAD-checks assets referenced by pages, each URL only once per run
+// no matter how many pages reference it
+type AssetChecker struct {
+	client *http.Client
+	cache  map[string]*assetStatus
+	mu     sync.Mutex
+}
+
+// NewAssetChecker creates a new asset checker
+func NewAssetChecker(client *http.Client) *AssetChecker {
+	return &AssetChecker{
+		client: client,
+		cache:  make(map[string]*assetStatus),
+	}
+}
+
+// Check returns the status of an asset, requesting it on first use
+func (a *AssetChecker) Check(assetURL string) (int, error) {
+	a.mu.Lock()
+	entry, ok := a.cache[assetURL]
+	if !ok {
+		entry = &assetStatus{}
+		a.cache[assetURL] = entry
+	}
+	a.mu.Unlock()
+
+	// Pages referencing the same asset at the same time wait for a single request
+	entry.once.Do(func() {
+		entry.status, entry.err = headCheck(a.client, assetURL)
+	})
+	return entry.status, entry.err
+}
+
+// Record stores the status of an asset that was already requested some other way,
+// unless it was checked before
+func (a *AssetChecker) Record(assetURL string, status int, err error) {
+	a.mu.Lock()
+	entry, ok := a.cache[assetURL]
+	if !ok {
+		entry = &assetStatus{}
+		a.cache[assetURL] = entry
+	}
+	a.mu.Unlock()
+
+	entry.once.Do(func() {
+		entry.status, entry.err = status, err
+	})
+}
+
+// brokenAssets checks assets and describes the ones that are unreachable
+func (a *AssetChecker) brokenAssets(assets []string) []string {
+	var broken []string
+	for _, asset := range assets {
+		status, err := a.Check(asset)
+		if !isBroken(status, err) {
+			continue
+		}
+		if err != nil {
+			broken = append(broken, fmt.Sprintf("%s: %v", asset, err))
+		} else {
+			broken = append(broken, fmt.Sprintf("%s (Status: %d)", asset, status))
+		}
+	}
+	return broken
+}
+
+// stylesheetURLs returns the resolved href of every <link rel="stylesheet"> of the page, each once
+func (p *Page) stylesheetURLs() []string {
+	seen := make(map[string]bool)
+	var stylesheets []string
+	walkHTML(p.Doc, func(n *html.Node) {
+		if n.Data != "link" || !hasRel(n, "stylesheet") {
+			return
+		}
+		href, ok := attr(n, "href")
+		if !ok || strings.TrimSpace(href) == "" {
+			return
+		}
+		if stylesheet := resolveURL(p.URL, href); !seen[stylesheet] {
+			seen[stylesheet] = true
+			stylesheets = append(stylesheets, stylesheet)
+		}
+	})
+	return stylesheets
+}
+
+// checkCSS flags the stylesheets of an HTML page that fail to load
+func checkCSS(checker *AssetChecker, result *Result, page *Page) {
+	for _, msg := range checker.brokenAssets(page.stylesheetURLs()) {
+		result.CSSIssues = append(result.CSSIssues, msg)
+		result.addIssue("CSS_BROKEN", msg)
+	}
+}
+
+// scriptURLs returns the resolved src of every external <script> of the page, each once
+func (p *Page) scriptURLs() []string {
+	seen := make(map[string]bool)
+	var scripts []string
+	walkHTML(p.Doc, func(n *html.Node) {
+		if n.Data != "script" {
+			return
+		}
+		src, ok := attr(n, "src")
+		if !ok || strings.TrimSpace(src) == "" {
+			return
+		}
+		if script := resolveURL(p.URL, src); !seen[script] {
+			seen[script] = true
+			scripts = append(scripts, script)
+		}
+	})
+	return scripts
+}
+
+// checkJS flags the external scripts of an HTML page that fail to load
+func checkJS(checker *AssetChecker, result *Result, page *Page) {
+	for _, msg := range checker.brokenAssets(page.scriptURLs()) {
+		result.JSIssues = append(result.JSIssues, msg)
+		result.addIssue("JS_BROKEN", msg)
+	}
+}
+
+// maxBrokenJSReported limits how many broken scripts the summary lists
+const maxBrokenJSReported = 10
+
+// formatBrokenJSSummary lists the broken scripts referenced by the most pages. Each
+// script is checked once, so all pages describe it with the same message.
+func formatBrokenJSSummary(results []Result) []string {
+	counts := make(map[string]int)
+	for _, result := range results {
+		for _, msg := range result.JSIssues {
+			counts[msg]++
+		}
+	}
+
+	lines := []string{fmt.Sprintf("Broken scripts: %d files on %d URLs", len(counts), countIssues(results, "JS_BROKEN"))}
+	scripts := sortedKeys(counts)
+	sort.SliceStable(scripts, func(i, j int) bool { return counts[scripts[i]] > counts[scripts[j]] })
+	for i, script := range scripts {
+		if i == maxBrokenJSReported {
+			break
+		}
+		lines = append(lines, fmt.Sprintf("  %s - referenced by %d URLs", script, counts[script]))
+	}
+	return lines
+}
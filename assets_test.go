@@ -0,0 +1,136 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+// Test for AssetChecker
+func TestAssetChecker(t *testing.T) {
+	var mu sync.Mutex
+	requests := make(map[string]int)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests[r.URL.Path]++
+		mu.Unlock()
+		if r.URL.Path == "/missing.css" {
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	checker := NewAssetChecker(server.Client())
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			checker.Check(server.URL + "/style.css")
+		}()
+	}
+	wg.Wait()
+
+	if requests["/style.css"] != 1 {
+		t.Errorf("style.css requested %d times, want 1", requests["/style.css"])
+	}
+	if status, err := checker.Check(server.URL + "/missing.css"); status != http.StatusNotFound || err != nil {
+		t.Errorf("Check(missing.css) = %d, %v, want 404", status, err)
+	}
+}
+
+// Test for checkCSS function
+func TestCheckCSS(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing.css" {
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	page := parseTestPage(t, server.URL+"/blog/post", `<html><head>
+		<link rel="stylesheet" href="/style.css">
+		<link rel="stylesheet" href="../missing.css">
+		<link rel="Preload Stylesheet" href="/missing.css">
+		<link rel="icon" href="/favicon.css">
+		</head></html>`)
+
+	wantURLs := []string{server.URL + "/style.css", server.URL + "/missing.css"}
+	if got := page.stylesheetURLs(); !reflect.DeepEqual(got, wantURLs) {
+		t.Errorf("stylesheetURLs() = %v, want %v", got, wantURLs)
+	}
+
+	result := Result{URL: page.URL, Status: 200}
+	checkCSS(NewAssetChecker(server.Client()), &result, page)
+
+	want := []string{server.URL + "/missing.css (Status: 404)"}
+	if !reflect.DeepEqual(result.CSSIssues, want) {
+		t.Errorf("CSSIssues = %v, want %v", result.CSSIssues, want)
+	}
+	if countIssues([]Result{result}, "CSS_BROKEN") != 1 {
+		t.Errorf("issues = %+v, want CSS_BROKEN", result.Issues)
+	}
+}
+
+// Test for checkJS function
+func TestCheckJS(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing.js" {
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	page := parseTestPage(t, server.URL+"/", `<html><head>
+		<script src="/app.js"></script>
+		<script src="missing.js" defer></script>
+		<script>console.log("inline")</script>
+		<script src="/missing.js"></script>
+		</head></html>`)
+
+	wantURLs := []string{server.URL + "/app.js", server.URL + "/missing.js"}
+	if got := page.scriptURLs(); !reflect.DeepEqual(got, wantURLs) {
+		t.Errorf("scriptURLs() = %v, want %v", got, wantURLs)
+	}
+
+	result := Result{URL: page.URL, Status: 200}
+	checkJS(NewAssetChecker(server.Client()), &result, page)
+
+	want := []string{server.URL + "/missing.js (Status: 404)"}
+	if !reflect.DeepEqual(result.JSIssues, want) {
+		t.Errorf("JSIssues = %v, want %v", result.JSIssues, want)
+	}
+	if countIssues([]Result{result}, "JS_BROKEN") != 1 {
+		t.Errorf("issues = %+v, want JS_BROKEN", result.Issues)
+	}
+}
+
+// Test for formatBrokenJSSummary function
+func TestFormatBrokenJSSummary(t *testing.T) {
+	broken := func(scripts ...string) Result {
+		var result Result
+		for _, script := range scripts {
+			result.JSIssues = append(result.JSIssues, script)
+			result.addIssue("JS_BROKEN", script)
+		}
+		return result
+	}
+	results := []Result{
+		broken("/a.js (Status: 404)", "/b.js (Status: 500)"),
+		broken("/b.js (Status: 500)"),
+		broken("/b.js (Status: 500)", "/c.js (Status: 404)"),
+		{},
+	}
+
+	want := []string{
+		"Broken scripts: 3 files on 3 URLs",
+		"  /b.js (Status: 500) - referenced by 3 URLs",
+		"  /a.js (Status: 404) - referenced by 1 URLs",
+		"  /c.js (Status: 404) - referenced by 1 URLs",
+	}
+	if got := formatBrokenJSSummary(results); !reflect.DeepEqual(got, want) {
+		t.Errorf("formatBrokenJSSummary() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// backoffRecoveryRequests is how many successful requests in a row lower the delay again
+	backoffRecoveryRequests = 10
+	// minBackoffDelay is the first delay used when rate limited without a -t delay
+	minBackoffDelay = 100 * time.Millisecond
+	// maxBackoffDelay caps the delay between requests while rate limited
+	maxBackoffDelay = time.Minute
+)
+
+// BackoffController adapts the delay between requests to rate limiting: each 429 doubles
+// it and every run of successful requests halves it, down to the configured delay
+type BackoffController struct {
+	base  time.Duration
+	delay atomic.Int64
+
+	mu        sync.Mutex
+	successes int
+	logger    *Logger
+}
+
+// NewBackoffController creates a backoff controller starting at the -t delay
+func NewBackoffController(base time.Duration, logger *Logger) *BackoffController {
+	b := &BackoffController{base: base, logger: logger}
+	b.delay.Store(int64(base))
+	return b
+}
+
+// Delay returns the current delay between requests
+func (b *BackoffController) Delay() time.Duration {
+	return time.Duration(b.delay.Load())
+}
+
+// Backing reports whether the delay is above the configured one because of rate limiting
+func (b *BackoffController) Backing() bool {
+	return b != nil && b.Delay() > b.base
+}
+
+// Record adjusts the delay to the outcome of a request
+func (b *BackoffController) Record(result Result) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	delay := b.Delay()
+	switch {
+	case result.Status == http.StatusTooManyRequests:
+		b.successes = 0
+		delay = min(max(2*delay, minBackoffDelay), maxBackoffDelay)
+		b.delay.Store(int64(delay))
+		b.log(fmt.Sprintf("Rate limit detected, backing off to %dms", delay.Milliseconds()))
+	case result.Error == nil && result.Status < 400 && delay > b.base:
+		b.successes++
+		if b.successes < backoffRecoveryRequests {
+			return
+		}
+		b.successes = 0
+		delay = max(delay/2, b.base)
+		b.delay.Store(int64(delay))
+		b.log(fmt.Sprintf("Rate limit cleared, reducing delay to %dms", delay.Milliseconds()))
+	}
+}
+
+// log reports a delay change on the console and in the log file
+func (b *BackoffController) log(msg string) {
+	fmt.Println(msg)
+	if b.logger != nil {
+		b.logger.Log(msg)
+	}
+}
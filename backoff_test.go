@@ -0,0 +1,73 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Test for BackoffController
+func TestBackoffController(t *testing.T) {
+	b := NewBackoffController(200*time.Millisecond, nil)
+	limited := Result{Status: http.StatusTooManyRequests}
+	ok := Result{Status: http.StatusOK}
+
+	b.Record(ok)
+	if b.Delay() != 200*time.Millisecond || b.Backing() {
+		t.Fatalf("Delay() = %v, want the base delay", b.Delay())
+	}
+
+	b.Record(limited)
+	b.Record(limited)
+	if b.Delay() != 800*time.Millisecond || !b.Backing() {
+		t.Fatalf("Delay() after two 429s = %v, want 800ms", b.Delay())
+	}
+
+	// Errors and failures don't count towards recovery, a 429 restarts it
+	for i := 0; i < backoffRecoveryRequests-1; i++ {
+		b.Record(ok)
+	}
+	b.Record(Result{Error: errors.New("timeout")})
+	b.Record(Result{Status: http.StatusNotFound})
+	if b.Delay() != 800*time.Millisecond {
+		t.Fatalf("Delay() before recovery = %v, want 800ms", b.Delay())
+	}
+	b.Record(ok)
+	if b.Delay() != 400*time.Millisecond {
+		t.Fatalf("Delay() after %d successes = %v, want 400ms", backoffRecoveryRequests, b.Delay())
+	}
+
+	for i := 0; i < 3*backoffRecoveryRequests; i++ {
+		b.Record(ok)
+	}
+	if b.Delay() != 200*time.Millisecond || b.Backing() {
+		t.Errorf("Delay() after recovery = %v, want the base delay", b.Delay())
+	}
+}
+
+// Test for BackoffController without a base delay and under concurrent use
+func TestBackoffControllerNoBase(t *testing.T) {
+	b := NewBackoffController(0, nil)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			b.Record(Result{Status: http.StatusTooManyRequests})
+		}()
+	}
+	wg.Wait()
+
+	if b.Delay() != maxBackoffDelay {
+		t.Errorf("Delay() after 20 429s = %v, want the %v cap", b.Delay(), maxBackoffDelay)
+	}
+
+	var nilController *BackoffController
+	nilController.Record(Result{Status: http.StatusTooManyRequests})
+	if nilController.Backing() {
+		t.Error("nil controller reports backing off")
+	}
+}
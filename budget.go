@@ -0,0 +1,90 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// TimeBudget is the longest acceptable response time for URLs matching a pattern
+type TimeBudget struct {
+	Pattern string
+	Budget  time.Duration
+
+	// match is the compiled pattern, where * matches any characters including slashes
+	match *regexp.Regexp
+}
+
+// parseTimeBudgets reads a JSON object mapping URL patterns to durations such as
+// {"*/api/*": "200ms"}. The budgets are returned most specific pattern first.
+func parseTimeBudgets(spec string) ([]TimeBudget, error) {
+	var raw map[string]string
+	if err := json.Unmarshal([]byte(spec), &raw); err != nil {
+		return nil, fmt.Errorf("invalid time budget JSON: %w", err)
+	}
+
+	budgets := make([]TimeBudget, 0, len(raw))
+	for pattern, value := range raw {
+		budget, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid time budget for %q: %w", pattern, err)
+		}
+		expr := "^" + strings.ReplaceAll(regexp.QuoteMeta(pattern), `\*`, ".*")
+		// Patterns without wildcards match as URL prefixes
+		if strings.Contains(pattern, "*") {
+			expr += "$"
+		}
+		budgets = append(budgets, TimeBudget{Pattern: pattern, Budget: budget, match: regexp.MustCompile(expr)})
+	}
+
+	sort.Slice(budgets, func(i, j int) bool {
+		if len(budgets[i].Pattern) != len(budgets[j].Pattern) {
+			return len(budgets[i].Pattern) > len(budgets[j].Pattern)
+		}
+		return budgets[i].Pattern < budgets[j].Pattern
+	})
+	return budgets, nil
+}
+
+// matchTimeBudget returns the budget of the longest pattern matching a URL, the longest
+// being taken as the most specific
+func matchTimeBudget(budgets []TimeBudget, loc string) (TimeBudget, bool) {
+	for _, budget := range budgets {
+		if budget.match.MatchString(loc) {
+			return budget, true
+		}
+	}
+	return TimeBudget{}, false
+}
+
+// checkTimeBudgets records the budget of every answered URL and flags the ones that responded
+// slower, returning how many were over budget
+func checkTimeBudgets(results []Result, budgets []TimeBudget, logger *Logger) int {
+	over := 0
+	for i := range results {
+		result := &results[i]
+		if result.Error != nil {
+			continue
+		}
+		budget, ok := matchTimeBudget(budgets, result.URL)
+		if !ok {
+			continue
+		}
+		result.BudgetMs = int(budget.Budget.Milliseconds())
+		result.BudgetPattern = budget.Pattern
+		if result.ResponseTime <= budget.Budget {
+			continue
+		}
+
+		over++
+		msg := fmt.Sprintf("responded in %dms, budget for %s is %dms", result.ResponseTime.Milliseconds(), budget.Pattern, result.BudgetMs)
+		result.addIssue("RESPONSE_TIME_OVER_BUDGET", msg)
+		if logger != nil {
+			logger.Log(fmt.Sprintf("RESPONSE_TIME_OVER_BUDGET: %s - %s", result.URL, msg) + sourceTag(result.SourceSitemap))
+		}
+	}
+	return over
+}
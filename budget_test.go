@@ -0,0 +1,67 @@
+package main
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// Test for parseTimeBudgets function
+func TestParseTimeBudgets(t *testing.T) {
+	if _, err := parseTimeBudgets(`{"*/api/*": "fast"}`); err == nil {
+		t.Error("parseTimeBudgets() accepted an invalid duration")
+	}
+	if _, err := parseTimeBudgets(`["*/api/*"]`); err == nil {
+		t.Error("parseTimeBudgets() accepted a JSON array")
+	}
+
+	budgets, err := parseTimeBudgets(`{"*/api/*": "200ms", "*/api/reports/*": "2s", "https://example.com/blog/": "1s"}`)
+	if err != nil {
+		t.Fatalf("parseTimeBudgets() error = %v", err)
+	}
+
+	tests := []struct {
+		loc     string
+		pattern string
+		budget  time.Duration
+	}{
+		{"https://example.com/api/users", "*/api/*", 200 * time.Millisecond},
+		{"https://example.com/api/reports/2024", "*/api/reports/*", 2 * time.Second},
+		{"https://example.com/blog/post", "https://example.com/blog/", time.Second},
+		{"https://other.example/blog", "", 0},
+	}
+	for _, tt := range tests {
+		budget, ok := matchTimeBudget(budgets, tt.loc)
+		if ok != (tt.pattern != "") || budget.Pattern != tt.pattern || budget.Budget != tt.budget {
+			t.Errorf("matchTimeBudget(%q) = %q %v, want %q %v", tt.loc, budget.Pattern, budget.Budget, tt.pattern, tt.budget)
+		}
+	}
+}
+
+// Test for checkTimeBudgets function
+func TestCheckTimeBudgets(t *testing.T) {
+	budgets, err := parseTimeBudgets(`{"*/api/*": "200ms"}`)
+	if err != nil {
+		t.Fatalf("parseTimeBudgets() error = %v", err)
+	}
+	results := []Result{
+		{URL: "https://example.com/api/fast", Status: 200, ResponseTime: 150 * time.Millisecond},
+		{URL: "https://example.com/api/slow", Status: 200, ResponseTime: 450 * time.Millisecond},
+		{URL: "https://example.com/api/down", Error: errors.New("timeout"), ResponseTime: time.Minute},
+		{URL: "https://example.com/page", Status: 200, ResponseTime: time.Minute},
+	}
+
+	if over := checkTimeBudgets(results, budgets, nil); over != 1 {
+		t.Errorf("checkTimeBudgets() = %d, want 1", over)
+	}
+	if results[0].BudgetMs != 200 || results[0].BudgetPattern != "*/api/*" || len(results[0].Issues) != 0 {
+		t.Errorf("fast result = %+v, want budget 200ms without issues", results[0])
+	}
+	want := "responded in 450ms, budget for */api/* is 200ms"
+	if len(results[1].Issues) != 1 || results[1].Issues[0].Code != "RESPONSE_TIME_OVER_BUDGET" || results[1].Issues[0].Message != want {
+		t.Errorf("slow result issues = %+v, want %q", results[1].Issues, want)
+	}
+	if results[2].BudgetMs != 0 || results[3].BudgetMs != 0 {
+		t.Errorf("failed or unmatched results got a budget: %+v, %+v", results[2], results[3])
+	}
+}
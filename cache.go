@@ -0,0 +1,61 @@
+package main
+
+import "sync"
+
+// ResultCache remembers the results of checked URLs so a URL listed in several
+// sitemaps is only requested once
+type ResultCache struct {
+	mu      sync.Mutex
+	entries map[string]*cacheEntry
+}
+
+// cacheEntry holds the results produced for one URL. done is closed once the
+// check that produces them has finished.
+type cacheEntry struct {
+	done    chan struct{}
+	results []Result
+}
+
+// NewResultCache creates an empty result cache
+func NewResultCache() *ResultCache {
+	return &ResultCache{entries: make(map[string]*cacheEntry)}
+}
+
+// Lookup returns the results of an earlier check of url, waiting for it to finish
+// if it is still in progress. If url has not been seen, Lookup returns false and
+// the caller must record its results with Add and then call Finish.
+func (c *ResultCache) Lookup(url string) ([]Result, bool) {
+	c.mu.Lock()
+	entry, ok := c.entries[url]
+	if !ok {
+		c.entries[url] = &cacheEntry{done: make(chan struct{})}
+		c.mu.Unlock()
+		return nil, false
+	}
+	c.mu.Unlock()
+
+	<-entry.done
+
+	// Return copies so callers can annotate them independently
+	results := make([]Result, len(entry.results))
+	copy(results, entry.results)
+	return results, true
+}
+
+// Add records a result produced while checking url
+func (c *ResultCache) Add(url string, result Result) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if entry, ok := c.entries[url]; ok {
+		entry.results = append(entry.results, result)
+	}
+}
+
+// Finish marks the check of url as complete and releases waiting lookups
+func (c *ResultCache) Finish(url string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if entry, ok := c.entries[url]; ok {
+		close(entry.done)
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Test for ResultCache lookups
+func TestResultCache(t *testing.T) {
+	cache := NewResultCache()
+	url := "https://example.com/page"
+
+	if _, ok := cache.Lookup(url); ok {
+		t.Fatalf("Lookup() of a new URL returned a cached result")
+	}
+
+	// A second lookup waits until the first check has finished
+	got := make(chan []Result)
+	go func() {
+		results, ok := cache.Lookup(url)
+		if !ok {
+			t.Errorf("Lookup() of a checked URL returned no result")
+		}
+		got <- results
+	}()
+
+	select {
+	case <-got:
+		t.Fatalf("Lookup() returned before the check finished")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	cache.Add(url, Result{URL: url, Status: 200})
+	cache.Finish(url)
+
+	select {
+	case results := <-got:
+		if len(results) != 1 || results[0].Status != 200 {
+			t.Errorf("Lookup() = %+v, want the stored result", results)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Lookup() did not return after Finish()")
+	}
+}
+
+// Test that duplicates answered from the cache are reported like checked URLs
+func TestCheckURLsCacheHitNotifies(t *testing.T) {
+	site := httptest.NewServer(http.NotFoundHandler())
+	defer site.Close()
+
+	var mu sync.Mutex
+	events := 0
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload WebhookPayload
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("invalid webhook payload: %v", err)
+		}
+		mu.Lock()
+		events += len(payload.Events)
+		mu.Unlock()
+	}))
+	defer webhook.Close()
+
+	opts := CheckOptions{UseCache: true, Webhook: NewWebhookNotifier(webhook.URL, "run123", 1, nil, nil)}
+	urls := []URL{{Loc: site.URL + "/gone", Source: "a.xml"}, {Loc: site.URL + "/gone", Source: "b.xml"}}
+	results := checkURLs(site.Client(), urls, 0, 1, nil, opts)
+	opts.Webhook.Close()
+
+	if len(results) != 2 || !(results[0].FromCache || results[1].FromCache) {
+		t.Fatalf("checkURLs() = %+v, want the duplicate answered from the cache", results)
+	}
+	if events != 2 {
+		t.Errorf("webhook received %d events, want 2", events)
+	}
+}
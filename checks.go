@@ -0,0 +1,416 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// pageLoader fetches a checked page at most once so several checks can share it
+type pageLoader struct {
+	client *http.Client
+	url    string
+	page   *Page
+	err    error
+	loaded bool
+}
+
+// load returns the fetched page, retrieving it on first use
+func (l *pageLoader) load() (*Page, error) {
+	if !l.loaded {
+		l.page, l.err = fetchPage(l.client, l.url)
+		l.loaded = true
+	}
+	return l.page, l.err
+}
+
+// prepareRequest sets the headers the enabled checks need on a check request
+func (opts CheckOptions) prepareRequest(req *http.Request) {
+	req.Header.Set("User-Agent", userAgent)
+
+	// Ask for compression explicitly, otherwise Go decompresses transparently and hides Content-Encoding
+	if opts.CheckVary || opts.CheckCompression {
+		req.Header.Set("Accept-Encoding", strings.Join(offeredEncodings, ", "))
+	}
+}
+
+// do sends a check request, retrying timeouts when -backoff-on-timeout is set
+func (opts CheckOptions) do(client *http.Client, req *http.Request) (*http.Response, int, error) {
+	if !opts.BackoffOnTimeout {
+		resp, err := client.Do(req)
+		return resp, 0, err
+	}
+	return doWithBackoff(client, req, opts.MaxRetries)
+}
+
+// requestOnly returns a copy of the options with every content check disabled, keeping
+// the ones that shape how requests are sent and reported, for URLs only checked for reachability
+func (opts CheckOptions) requestOnly() CheckOptions {
+	return CheckOptions{
+		PerHostTimeout:       opts.PerHostTimeout,
+		Verbose:              opts.Verbose,
+		TUI:                  opts.TUI,
+		BackoffOnTimeout:     opts.BackoffOnTimeout,
+		MaxRetries:           opts.MaxRetries,
+		MaxConcurrentDomains: opts.MaxConcurrentDomains,
+
+		Webhook: opts.Webhook,
+		Tracing: opts.Tracing,
+		Backoff: opts.Backoff,
+	}
+}
+
+// addIssue records a problem found by one of the optional checks
+func (r *Result) addIssue(code, message string) {
+	r.Issues = append(r.Issues, Issue{Code: code, Message: message})
+}
+
+// runChecks performs the optional checks enabled in opts on a checked URL
+func runChecks(client *http.Client, result *Result, opts CheckOptions, logger *Logger) {
+	if result.Error != nil {
+		return
+	}
+
+	loader := &pageLoader{client: client, url: result.URL}
+
+	// withPage runs a content check on the page fetched with GET. A failed fetch
+	// is logged once and skips every check that needs the page.
+	pageErrorLogged := false
+	withPage := func(check func(page *Page)) {
+		page, err := loader.load()
+		if err != nil {
+			if !pageErrorLogged {
+				logPageError(logger, result, err)
+				pageErrorLogged = true
+			}
+			return
+		}
+		check(page)
+	}
+
+	// Some servers answer HEAD differently from GET, e.g. a misconfigured CDN or WAF
+	if opts.VerifyGet && result.GetStatus == 0 && result.HeadStatus >= 200 && result.HeadStatus < 300 {
+		withPage(func(page *Page) {
+			result.GetStatus = page.Status
+			if result.GetStatus != result.HeadStatus {
+				result.addIssue("HEAD_GET_MISMATCH", fmt.Sprintf("HEAD returned %d, GET returned %d", result.HeadStatus, result.GetStatus))
+			}
+		})
+	}
+
+	if opts.CheckHTTPToHTTPS {
+		checkHTTPSRedirects(client, result)
+	}
+
+	if opts.FollowRedirects && result.IsRedirect {
+		if err := checkRedirectChain(client, result, opts.MaxRedirectChain); err != nil {
+			logPageError(logger, result, err)
+		}
+	}
+
+	// The loader's client doesn't follow redirects, so the page is the redirect response itself
+	if opts.CheckCanonRedirect && result.IsRedirect {
+		withPage(func(page *Page) {
+			if err := checkCanonicalRedirect(client, result, page); err != nil {
+				logPageError(logger, result, err)
+			}
+		})
+	}
+
+	if opts.CheckVary {
+		checkVary(result)
+	}
+
+	if opts.CheckCompression && result.Status == http.StatusOK {
+		checkCompression(result)
+	}
+
+	if opts.CheckHTTP10 {
+		checkProtocol(result)
+	}
+
+	if opts.CheckTLSVersion {
+		checkTLSVersion(result)
+	}
+
+	if opts.CheckServerHeader || opts.FlagServerDisclosure {
+		checkServerHeader(result, opts.FlagServerDisclosure)
+	}
+
+	if opts.CheckCache && result.Status == http.StatusOK {
+		checkCacheHeaders(result)
+	}
+
+	if opts.CheckMaxAge && result.Status == http.StatusOK {
+		checkHTMLMaxAge(result, opts.MaxHTMLAge)
+	}
+
+	if opts.CheckExpires && result.Status == http.StatusOK {
+		checkExpires(result)
+	}
+
+	if opts.CheckHTMLSize && result.Status == http.StatusOK {
+		if err := checkHTMLSize(client, result, opts.MaxHTMLBytes); err != nil {
+			logPageError(logger, result, err)
+		}
+	}
+
+	if opts.CheckXCTO && result.Status == http.StatusOK {
+		checkXContentTypeOptions(result)
+	}
+
+	if opts.CheckNosniff && result.Status == http.StatusOK {
+		checkAssetNosniff(result)
+	}
+
+	if opts.CheckCSP && result.Status == http.StatusOK {
+		checkCSP(result)
+	}
+
+	if opts.CheckLinkHeader && result.Status == http.StatusOK {
+		checkPreloadLinks(client, result)
+	}
+
+	if opts.CheckETag && result.Status == http.StatusOK {
+		if err := checkConditionalGet(client, result); err != nil {
+			logPageError(logger, result, err)
+		}
+	}
+
+	if opts.CheckXPoweredBy {
+		checkXPoweredBy(result, opts.AllowedPoweredBy)
+	}
+
+	if opts.CloakingCheck {
+		if err := checkCloaking(client, result, loader, opts.BodyCheck); err != nil {
+			logPageError(logger, result, err)
+		}
+	}
+
+	if opts.CheckCustom404 && result.Status == http.StatusNotFound {
+		withPage(func(page *Page) { checkCustom404(result, page) })
+	}
+
+	if opts.CheckUTF8 && result.Status == http.StatusOK {
+		withPage(func(page *Page) { checkUTF8(result, page) })
+	}
+
+	if opts.CheckOGImage && result.Status == http.StatusOK {
+		withPage(func(page *Page) { checkOGImage(client, result, page) })
+	}
+
+	if opts.CheckAMP && result.Status == http.StatusOK {
+		withPage(func(page *Page) { checkAMP(client, result, page) })
+	}
+
+	if opts.Renderer != nil && result.Status == http.StatusOK {
+		if err := checkRendering(opts.Renderer, result); err != nil {
+			logPageError(logger, result, err)
+		}
+	}
+
+	if opts.MobileFriendly != nil && result.Status == http.StatusOK {
+		if err := checkMobileFriendly(opts.MobileFriendly, result); err != nil {
+			logPageError(logger, result, err)
+		}
+	}
+
+	if opts.CheckTitle && result.Status == http.StatusOK {
+		withPage(func(page *Page) {
+			checkTitle(result, page, opts.CheckTitleLength, opts.TitleMin, opts.TitleMax)
+		})
+	}
+
+	if opts.CheckMetaDescription && result.Status == http.StatusOK {
+		withPage(func(page *Page) { checkMetaDescription(result, page) })
+	}
+
+	if opts.CheckResourceHints && result.Status == http.StatusOK {
+		withPage(func(page *Page) { checkResourceHints(result, page) })
+	}
+
+	if opts.CheckJSONLD && result.Status == http.StatusOK {
+		withPage(func(page *Page) { checkJSONLD(result, page, opts.RequireJSONLDType) })
+	}
+
+	robotsChecked := false
+	if opts.Robots != nil {
+		if err := checkRobots(opts.Robots, result); err != nil {
+			logPageError(logger, result, err)
+		} else {
+			robotsChecked = true
+		}
+	}
+
+	if opts.CheckNoindex && result.Status == http.StatusOK {
+		withPage(func(page *Page) { checkNoindex(result, page) })
+	}
+
+	if opts.CheckCanonical && result.Status == http.StatusOK {
+		withPage(func(page *Page) { checkCanonical(result, page) })
+	}
+
+	// Only a page whose signals were all read can be called indexable
+	if opts.CheckIndexability && robotsChecked && result.Status == http.StatusOK {
+		withPage(func(page *Page) { result.IsIndexable = indexable(result) })
+	}
+
+	if opts.CheckSchema && result.Status == http.StatusOK {
+		withPage(func(page *Page) { checkSchema(result, page, opts.RequiredSchemaType) })
+	}
+
+	if (opts.HreflangTargets || opts.HreflangComplete) && result.Status == http.StatusOK {
+		withPage(func(page *Page) { result.HreflangTargets = page.hreflangLinks() })
+	}
+
+	if opts.CheckFonts && result.Status == http.StatusOK && isCSS(result.Header.Get("Content-Type")) {
+		withPage(func(page *Page) { checkFonts(client, result, page) })
+	}
+
+	if opts.CheckCSS && result.Status == http.StatusOK && isHTML(result.Header.Get("Content-Type")) {
+		withPage(func(page *Page) { checkCSS(opts.Assets, result, page) })
+	}
+
+	if opts.CheckJS && result.Status == http.StatusOK && isHTML(result.Header.Get("Content-Type")) {
+		withPage(func(page *Page) { checkJS(opts.Assets, result, page) })
+	}
+
+	if opts.CheckHTTPVersion && result.Status == http.StatusOK && result.Protocol == "HTTP/2.0" && isHTML(result.Header.Get("Content-Type")) {
+		withPage(func(page *Page) { checkHTTPVersionConsistency(client, result, page) })
+	}
+
+	if opts.CheckMixedContent && result.Status == http.StatusOK && strings.HasPrefix(result.URL, "https://") && isHTML(result.Header.Get("Content-Type")) {
+		withPage(func(page *Page) { checkMixedContent(result, page) })
+	}
+
+	if opts.CheckFavicon && result.Status == http.StatusOK && isHTML(result.Header.Get("Content-Type")) {
+		withPage(func(page *Page) { result.IconLinks = page.iconLinks() })
+	}
+
+	if opts.CheckLangTag && result.Status == http.StatusOK && isHTML(result.Header.Get("Content-Type")) {
+		withPage(func(page *Page) { checkLangTag(result, page) })
+	}
+
+	if (opts.CheckInternalLinks || opts.CheckExternalLinks) && result.Status == http.StatusOK && isHTML(result.Header.Get("Content-Type")) {
+		withPage(func(page *Page) {
+			internal, external := splitLinks(result.URL, page.anchorLinks())
+			if opts.CheckInternalLinks {
+				result.InternalLinks = internal
+			}
+			if opts.CheckExternalLinks {
+				result.ExternalLinks = external
+			}
+		})
+	}
+
+	if opts.CheckWordCount && result.Status == http.StatusOK && isHTML(result.Header.Get("Content-Type")) {
+		withPage(func(page *Page) { checkWordCount(result, page, opts.MinWords) })
+	}
+
+	if opts.CheckA11y && result.Status == http.StatusOK && isHTML(result.Header.Get("Content-Type")) {
+		withPage(func(page *Page) { checkA11y(result, page) })
+	}
+
+	if opts.CheckInPageAnchors && result.Status == http.StatusOK && isHTML(result.Header.Get("Content-Type")) {
+		withPage(func(page *Page) { checkInPageAnchors(result, page) })
+	}
+
+	if opts.CheckViewport && result.Status == http.StatusOK && isHTML(result.Header.Get("Content-Type")) {
+		withPage(func(page *Page) { checkViewport(result, page) })
+	}
+
+	if opts.CheckImages && result.Status == http.StatusOK {
+		checkImages(client, result)
+	}
+
+	if (opts.CheckPagination || opts.CheckPaginationFull || opts.PaginationCanonical) && result.Status == http.StatusOK {
+		withPage(func(page *Page) {
+			chain, issues := opts.Pagination.Walk(client, page)
+			for _, issue := range opts.Pagination.Unreported(issues) {
+				result.PaginationIssues = append(result.PaginationIssues, issue)
+				result.addIssue("PAGINATION_BROKEN", issue)
+			}
+			if opts.CheckPaginationFull {
+				for _, issue := range opts.Pagination.Unreported(checkPaginationIntegrity(chain)) {
+					result.PaginationIssues = append(result.PaginationIssues, issue)
+					result.addIssue("PAGINATION_INTEGRITY_FAIL", issue)
+				}
+			}
+			if opts.PaginationCanonical {
+				if issues := opts.Pagination.Unreported(checkPaginationCanonical(chain)); len(issues) > 0 {
+					result.PaginationCanonicalIssue = strings.Join(issues, "; ")
+					result.addIssue("PAGINATION_CANONICAL_ISSUE", result.PaginationCanonicalIssue)
+				}
+			}
+		})
+	}
+
+	// Log issues immediately
+	if logger != nil {
+		for _, issue := range result.Issues {
+			logger.Log(fmt.Sprintf("%s: %s - %s", issue.Code, result.URL, issue.Message) + sourceTag(result.SourceSitemap))
+		}
+	}
+}
+
+// logPageError logs a failure to fetch a page for the content checks
+func logPageError(logger *Logger, result *Result, err error) {
+	if logger != nil {
+		logger.Log(fmt.Sprintf("ERROR (page fetch): %s - %v", result.URL, err) + sourceTag(result.SourceSitemap))
+	}
+}
+
+// countIssues returns how many results have at least one issue with the given code
+func countIssues(results []Result, code string) int {
+	count := 0
+	for _, result := range results {
+		for _, issue := range result.Issues {
+			if issue.Code == code {
+				count++
+				break
+			}
+		}
+	}
+	return count
+}
+
+// formatValueCounts summarises how often each non-empty value occurs, most common first
+func formatValueCounts(results []Result, value func(Result) string) string {
+	counts := make(map[string]int)
+	for _, result := range results {
+		if v := value(result); v != "" {
+			counts[v]++
+		}
+	}
+	if len(counts) == 0 {
+		return "none"
+	}
+
+	values := make([]string, 0, len(counts))
+	for v := range counts {
+		values = append(values, v)
+	}
+	sort.Slice(values, func(i, j int) bool {
+		if counts[values[i]] != counts[values[j]] {
+			return counts[values[i]] > counts[values[j]]
+		}
+		return values[i] < values[j]
+	})
+
+	parts := make([]string, 0, len(values))
+	for _, v := range values {
+		parts = append(parts, fmt.Sprintf("%s (%d URLs)", v, counts[v]))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// sortedKeys returns the keys of a map in sorted order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
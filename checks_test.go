@@ -0,0 +1,87 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// Test that -verify-get flags servers answering HEAD and GET differently
+func TestRunChecksVerifyGet(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "HEAD" || r.URL.Path == "/consistent" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name      string
+		path      string
+		wantIssue bool
+	}{
+		{name: "mismatch", path: "/lying", wantIssue: true},
+		{name: "consistent", path: "/consistent", wantIssue: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Result{URL: server.URL + tt.path, Status: http.StatusOK, HeadStatus: http.StatusOK}
+			runChecks(server.Client(), &result, CheckOptions{VerifyGet: true}, nil)
+
+			gotIssue := len(result.Issues) == 1 && result.Issues[0].Code == "HEAD_GET_MISMATCH"
+			if gotIssue != tt.wantIssue {
+				t.Errorf("runChecks() issues = %+v, want mismatch %v", result.Issues, tt.wantIssue)
+			}
+			if result.GetStatus == 0 {
+				t.Errorf("runChecks() did not record GetStatus")
+			}
+		})
+	}
+}
+
+// Test that requestOnly keeps how requests are sent and drops the content checks
+func TestCheckOptionsRequestOnly(t *testing.T) {
+	backoff := NewBackoffController(time.Second, nil)
+	opts := CheckOptions{
+		PerHostTimeout:       time.Minute,
+		BackoffOnTimeout:     true,
+		MaxRetries:           3,
+		MaxConcurrentDomains: 2,
+		Backoff:              backoff,
+		CheckTitle:           true,
+		CheckPagination:      true,
+		CheckCSS:             true,
+		Assets:               NewAssetChecker(http.DefaultClient),
+	}
+
+	got := opts.requestOnly()
+	want := CheckOptions{PerHostTimeout: time.Minute, BackoffOnTimeout: true, MaxRetries: 3, MaxConcurrentDomains: 2, Backoff: backoff}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("requestOnly() = %+v, want %+v", got, want)
+	}
+}
+
+// Test for formatValueCounts function
+func TestFormatValueCounts(t *testing.T) {
+	results := []Result{
+		{ServerHeader: "nginx"},
+		{ServerHeader: "Apache/2.4"},
+		{ServerHeader: "nginx"},
+		{ServerHeader: ""},
+	}
+
+	got := formatValueCounts(results, func(r Result) string { return r.ServerHeader })
+	want := "nginx (2 URLs), Apache/2.4 (1 URLs)"
+	if got != want {
+		t.Errorf("formatValueCounts() = %q, want %q", got, want)
+	}
+
+	if got := formatValueCounts(nil, func(r Result) string { return r.ServerHeader }); got != "none" {
+		t.Errorf("formatValueCounts(nil) = %q, want %q", got, "none")
+	}
+}
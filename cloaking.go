@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// googlebotUserAgent is the user agent used to detect content served differently to crawlers
+const googlebotUserAgent = "Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)"
+
+// cloakingMinSimilarity is the share of words the visible text served to the checker
+// and to Googlebot must have in common. Dynamic parts such as timestamps or CSRF tokens only
+// change a few words, while content cloaked for crawlers differs far more.
+const cloakingMinSimilarity = 0.9
+
+// checkCloaking requests a URL again as Googlebot and compares the response with the
+// one served to the checker. The visible text of the bodies is only compared when
+// bodyCheck is set.
+func checkCloaking(client *http.Client, result *Result, loader *pageLoader, bodyCheck bool) error {
+	if bodyCheck && result.Status == http.StatusOK {
+		page, err := loader.load()
+		if err != nil {
+			return err
+		}
+		botPage, err := fetchPageAs(client, result.URL, googlebotUserAgent)
+		if err != nil {
+			return err
+		}
+		result.BotStatus = botPage.Status
+
+		if page.Status == botPage.Status && page.Status == http.StatusOK {
+			if similarity := wordSimilarity(page.words(), botPage.words()); similarity < cloakingMinSimilarity {
+				result.CloakingDetected = true
+				result.addIssue("POSSIBLE_CLOAKING", fmt.Sprintf("text served to Googlebot differs (%.0f%% similar, %d bytes vs %d bytes)", similarity*100, len(botPage.Body), len(page.Body)))
+			}
+			return nil
+		}
+	} else {
+		status, err := headCheckAs(client, result.URL, googlebotUserAgent)
+		if err != nil {
+			return err
+		}
+		result.BotStatus = status
+	}
+
+	if result.BotStatus != result.Status {
+		result.CloakingDetected = true
+		result.addIssue("POSSIBLE_CLOAKING", fmt.Sprintf("Googlebot received status %d, checker received %d", result.BotStatus, result.Status))
+	}
+	return nil
+}
+
+// wordSimilarity returns the Jaccard similarity of the words of a and b counted as
+// multisets, 1 when both are empty
+func wordSimilarity(a, b []string) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 1
+	}
+	counts := make(map[string]int, len(a))
+	for _, word := range a {
+		counts[word]++
+	}
+	shared := 0
+	for _, word := range b {
+		if counts[word] > 0 {
+			counts[word]--
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
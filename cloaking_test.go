@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Test for checkCloaking function
+func TestCheckCloaking(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		isBot := strings.Contains(r.UserAgent(), "Googlebot")
+		switch r.URL.Path {
+		case "/blocked":
+			if isBot {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+		case "/content":
+			if isBot {
+				fmt.Fprint(w, "<p>keyword keyword keyword</p>")
+				return
+			}
+		case "/dynamic":
+			// The token and the time differ on every request, the content does not
+			token := time.Now().UnixNano()
+			fmt.Fprintf(w, `<script>var csrf = "%d";</script><input type="hidden" value="%d">`, token, token)
+			fmt.Fprintf(w, "<p>%s Generated at %d.</p>", strings.Repeat("Our shop sells hand made furniture from local oak and walnut. ", 3), token)
+			return
+		}
+		fmt.Fprint(w, "<p>Regular content</p>")
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name      string
+		path      string
+		bodyCheck bool
+		want      bool
+	}{
+		{name: "same response", path: "/same", bodyCheck: true, want: false},
+		{name: "different status", path: "/blocked", want: true},
+		{name: "different body without body check", path: "/content", want: false},
+		{name: "different body with body check", path: "/content", bodyCheck: true, want: true},
+		{name: "dynamic tokens with body check", path: "/dynamic", bodyCheck: true, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Result{URL: server.URL + tt.path, Status: http.StatusOK}
+			loader := &pageLoader{client: server.Client(), url: result.URL}
+
+			if err := checkCloaking(server.Client(), &result, loader, tt.bodyCheck); err != nil {
+				t.Fatalf("checkCloaking() error = %v", err)
+			}
+			if result.CloakingDetected != tt.want {
+				t.Errorf("CloakingDetected = %v, want %v (issues: %+v)", result.CloakingDetected, tt.want, result.Issues)
+			}
+		})
+	}
+}
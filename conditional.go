@@ -0,0 +1,44 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// checkConditionalGet repeats a request with the validators of a 200 response and
+// flags servers that answer with the full page again instead of 304 Not Modified
+func checkConditionalGet(client *http.Client, result *Result) error {
+	etag := result.Header.Get("ETag")
+	lastModified := result.Header.Get("Last-Modified")
+	if etag == "" && lastModified == "" {
+		return nil
+	}
+
+	req, err := http.NewRequest("GET", result.URL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", userAgent)
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+	if lastModified != "" {
+		req.Header.Set("If-Modified-Since", lastModified)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	result.ETagSupported = resp.StatusCode == http.StatusNotModified
+	if resp.StatusCode == http.StatusOK {
+		validator := "If-None-Match: " + etag
+		if etag == "" {
+			validator = "If-Modified-Since: " + lastModified
+		}
+		result.addIssue("CONDITIONAL_GET_BROKEN", fmt.Sprintf("conditional GET with %s returned 200 instead of 304", validator))
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Test for checkConditionalGet function
+func TestCheckConditionalGet(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/etag":
+			if r.Header.Get("If-None-Match") == `"v1"` {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("ETag", `"v1"`)
+		case "/ignores-validators":
+			w.Header().Set("Last-Modified", "Mon, 02 Jan 2006 15:04:05 GMT")
+		}
+		w.Write([]byte("page"))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name          string
+		path          string
+		header        http.Header
+		wantSupported bool
+		wantIssue     bool
+	}{
+		{name: "etag honoured", path: "/etag", header: http.Header{"Etag": {`"v1"`}}, wantSupported: true},
+		{name: "validators ignored", path: "/ignores-validators", header: http.Header{"Last-Modified": {"Mon, 02 Jan 2006 15:04:05 GMT"}}, wantIssue: true},
+		{name: "no validators", path: "/plain", header: http.Header{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Result{URL: server.URL + tt.path, Status: http.StatusOK, Header: tt.header}
+			if err := checkConditionalGet(server.Client(), &result); err != nil {
+				t.Fatalf("checkConditionalGet() error = %v", err)
+			}
+			if result.ETagSupported != tt.wantSupported {
+				t.Errorf("ETagSupported = %v, want %v", result.ETagSupported, tt.wantSupported)
+			}
+			if got := len(result.Issues) > 0; got != tt.wantIssue {
+				t.Errorf("checkConditionalGet() issues = %+v, want issue %v", result.Issues, tt.wantIssue)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+)
+
+// redactedFlags hold credentials that are never printed by -print-config
+var redactedFlags = map[string]bool{
+	"ftp-pass":             true,
+	"oauth2-client-secret": true,
+	"google-api-key":       true,
+}
+
+// urlFlags hold URLs that may carry credentials in their userinfo or query string.
+// The path is redacted too where it is set, webhook URLs usually embed a token there.
+var urlFlags = map[string]bool{
+	"u":                false,
+	"proxy":            false,
+	"otel-endpoint":    false,
+	"oauth2-token-url": false,
+	"webhook-url":      true,
+}
+
+// redactURL replaces the userinfo, the query values and optionally the path of a URL with ***
+func redactURL(rawURL string, redactPath bool) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "***"
+	}
+	if u.RawQuery != "" {
+		query := u.Query()
+		var params []string
+		for _, key := range sortedKeys(query) {
+			params = append(params, url.QueryEscape(key)+"=***")
+		}
+		u.RawQuery = strings.Join(params, "&")
+	}
+	if redactPath && u.Path != "" && u.Path != "/" {
+		u.Path, u.RawPath = "/***", "/***"
+	}
+
+	// url.User would percent-encode the asterisks
+	hasUser := u.User != nil
+	u.User = nil
+	redacted := u.String()
+	if hasUser {
+		redacted = strings.Replace(redacted, "//", "//***@", 1)
+	}
+	return redacted
+}
+
+// effectiveConfig returns the value of every flag in the set, with credentials redacted
+func effectiveConfig(fs *flag.FlagSet) map[string]interface{} {
+	config := make(map[string]interface{})
+	fs.VisitAll(func(f *flag.Flag) {
+		// Keep booleans and numbers typed, durations read better as strings like "5m0s"
+		var value interface{} = f.Value.String()
+		if getter, ok := f.Value.(flag.Getter); ok {
+			switch v := getter.Get().(type) {
+			case bool, int, int64, uint, uint64, float64:
+				value = v
+			}
+		}
+		if redactedFlags[f.Name] && f.Value.String() != "" {
+			value = "***"
+		}
+		if redactPath, ok := urlFlags[f.Name]; ok && f.Value.String() != "" {
+			value = redactURL(f.Value.String(), redactPath)
+		}
+		config[f.Name] = value
+	})
+	return config
+}
+
+// printConfig writes the effective configuration as an indented JSON object
+func printConfig(w io.Writer, fs *flag.FlagSet) error {
+	data, err := json.MarshalIndent(effectiveConfig(fs), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode configuration: %w", err)
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// parseCSVURLs reads the URLs in the given column of a CSV file, skipping the first
+// row when it is a header and rows where the column is empty
+func parseCSVURLs(r io.Reader, column int, header bool, source string) ([]URL, error) {
+	if column < 0 {
+		return nil, fmt.Errorf("invalid URL column %d", column)
+	}
+
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	var urls []URL
+	for row := 1; ; row++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CSV: %w", err)
+		}
+		if header && row == 1 {
+			continue
+		}
+		if column >= len(record) {
+			return nil, fmt.Errorf("CSV row %d has no column %d", row, column)
+		}
+		if loc := strings.TrimSpace(record[column]); loc != "" {
+			urls = append(urls, URL{Loc: loc, Source: source})
+		}
+	}
+	return urls, nil
+}
+
+// loadCSVURLs reads the URLs to check from a CSV file instead of a sitemap
+func loadCSVURLs(path string, column int, header bool) ([]URL, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open CSV input: %w", err)
+	}
+	defer file.Close()
+	return parseCSVURLs(file, column, header, path)
+}
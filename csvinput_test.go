@@ -0,0 +1,45 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// Test for parseCSVURLs function
+func TestParseCSVURLs(t *testing.T) {
+	input := `Title,URL,Clicks
+"Home, sweet home",https://example.com/,120
+Blog,  https://example.com/blog ,45
+Empty,,0
+`
+
+	tests := []struct {
+		name    string
+		column  int
+		header  bool
+		want    []string
+		wantErr bool
+	}{
+		{name: "url column with header", column: 1, header: true, want: []string{"https://example.com/", "https://example.com/blog"}},
+		{name: "header read as url", column: 1, want: []string{"URL", "https://example.com/", "https://example.com/blog"}},
+		{name: "column out of range", column: 5, header: true, wantErr: true},
+		{name: "negative column", column: -1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			urls, err := parseCSVURLs(strings.NewReader(input), tt.column, tt.header, "urls.csv")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseCSVURLs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !equalStringSlices(urlLocs(urls), tt.want) {
+				t.Errorf("parseCSVURLs() = %v, want %v", urlLocs(urls), tt.want)
+			}
+			for _, u := range urls {
+				if u.Source != "urls.csv" {
+					t.Errorf("Source = %q, want urls.csv", u.Source)
+				}
+			}
+		})
+	}
+}
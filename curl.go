@@ -0,0 +1,88 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// curlMaxTime matches the checker's HTTP client timeout in seconds
+const curlMaxTime = 30
+
+// shellQuote quotes a string for a POSIX shell, escaping $ for make
+func shellQuote(s string) string {
+	quoted := "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+	return strings.ReplaceAll(quoted, "$", "$$")
+}
+
+// makeSafe percent-encodes backslashes and control bytes, which would otherwise end a
+// recipe or comment line of the Makefile early or join the next line onto it
+func makeSafe(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c < 0x20 || c == 0x7f || c == '\\' {
+			fmt.Fprintf(&b, "%%%02X", c)
+			continue
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
+// curlCommand returns a curl command that repeats the check of a result: a HEAD
+// request as the checker sends it, following the chain for redirects
+func curlCommand(result Result, insecure bool) string {
+	args := []string{"curl", "-sS", "-I"}
+	if result.IsRedirect {
+		args = append(args, "-L")
+	}
+	if insecure {
+		args = append(args, "-k")
+	}
+	args = append(args, "-A", shellQuote(userAgent), "--max-time", fmt.Sprint(curlMaxTime), shellQuote(makeSafe(result.URL)))
+	return strings.Join(args, " ")
+}
+
+// writeCurlMakefile writes a make target with a curl command for every problematic result
+func writeCurlMakefile(w io.Writer, results []Result, insecure bool) (int, error) {
+	var targets []string
+	var body strings.Builder
+	for _, result := range results {
+		if result.Error == nil && result.Status >= 200 && result.Status < 300 {
+			continue
+		}
+		target := fmt.Sprintf("check-url-%d", len(targets)+1)
+		targets = append(targets, target)
+
+		switch {
+		case result.Error != nil:
+			fmt.Fprintf(&body, "# ERROR: %s\n", makeSafe(result.Error.Error()))
+		case result.IsRedirect:
+			fmt.Fprintf(&body, "# REDIRECT: %d -> %s\n", result.Status, makeSafe(result.RedirectURL))
+		default:
+			fmt.Fprintf(&body, "# INVALID STATUS: %d\n", result.Status)
+		}
+		fmt.Fprintf(&body, "%s:\n\t%s\n\n", target, curlCommand(result, insecure))
+	}
+
+	if _, err := fmt.Fprintf(w, "# Generated by sitemap_checker: run `make -f <file> <target>` to repeat a check\n\n"+
+		".PHONY: all %s\n\nall: %s\n\n%s", strings.Join(targets, " "), strings.Join(targets, " "), body.String()); err != nil {
+		return 0, err
+	}
+	return len(targets), nil
+}
+
+// saveCurlMakefile writes the curl Makefile to path
+func saveCurlMakefile(path string, results []Result, insecure bool) (int, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create curl Makefile: %w", err)
+	}
+	count, err := writeCurlMakefile(file, results, insecure)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	return count, err
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// Test for writeCurlMakefile function
+func TestWriteCurlMakefile(t *testing.T) {
+	results := []Result{
+		{URL: "https://example.com/ok", Status: 200},
+		{URL: "https://example.com/missing?q=it's", Status: 404},
+		{URL: "https://example.com/old", Status: 301, IsRedirect: true, RedirectURL: "https://example.com/new"},
+		{URL: "https://example.com/$down", Error: errors.New("connection refused")},
+	}
+
+	var out strings.Builder
+	count, err := writeCurlMakefile(&out, results, true)
+	if err != nil {
+		t.Fatalf("writeCurlMakefile() error = %v", err)
+	}
+	if count != 3 {
+		t.Errorf("writeCurlMakefile() wrote %d targets, want 3", count)
+	}
+
+	makefile := out.String()
+	for _, want := range []string{
+		"all: check-url-1 check-url-2 check-url-3\n",
+		"# INVALID STATUS: 404\ncheck-url-1:\n\tcurl -sS -I -k -A 'SitemapChecker/1.0' --max-time 30 'https://example.com/missing?q=it'\\''s'\n",
+		"# REDIRECT: 301 -> https://example.com/new\ncheck-url-2:\n\tcurl -sS -I -L -k",
+		"# ERROR: connection refused\ncheck-url-3:\n\tcurl -sS -I -k -A 'SitemapChecker/1.0' --max-time 30 'https://example.com/$$down'\n",
+	} {
+		if !strings.Contains(makefile, want) {
+			t.Errorf("Makefile is missing %q, got:\n%s", want, makefile)
+		}
+	}
+	if strings.Contains(makefile, "/ok") {
+		t.Errorf("Makefile contains the successful URL:\n%s", makefile)
+	}
+}
+
+// Test that control bytes and backslashes can't add recipe lines or join lines
+func TestWriteCurlMakefileControlBytes(t *testing.T) {
+	results := []Result{
+		{URL: "https://x/'\n\techo PWNED #", Error: errors.New("parse error:\r\nbad\x7f")},
+		{URL: "https://x/a\\", Status: 301, IsRedirect: true, RedirectURL: "https://x/b\\"},
+		{URL: "https://x/\x00\x1b", Status: 404},
+	}
+
+	var out strings.Builder
+	if _, err := writeCurlMakefile(&out, results, false); err != nil {
+		t.Fatalf("writeCurlMakefile() error = %v", err)
+	}
+	makefile := out.String()
+
+	for _, want := range []string{
+		"# ERROR: parse error:%0D%0Abad%7F\ncheck-url-1:\n\tcurl -sS -I -A 'SitemapChecker/1.0' --max-time 30 'https://x/'\\''%0A%09echo PWNED #'\n",
+		"# REDIRECT: 301 -> https://x/b%5C\ncheck-url-2:\n\tcurl -sS -I -L -A 'SitemapChecker/1.0' --max-time 30 'https://x/a%5C'\n",
+		"'https://x/%00%1B'\n",
+	} {
+		if !strings.Contains(makefile, want) {
+			t.Errorf("Makefile is missing %q, got:\n%s", want, makefile)
+		}
+	}
+	if strings.Contains(makefile, "\techo") || strings.Contains(makefile, "\\\n") {
+		t.Errorf("Makefile contains an injected or joined line:\n%s", makefile)
+	}
+}
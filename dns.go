@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// verifyDNS resolves every unique hostname of the URL list and returns the ones that failed
+func verifyDNS(urls []URL) ([]string, map[string]error) {
+	seen := make(map[string]bool)
+	var hosts []string
+	for _, u := range urls {
+		parsedURL, err := url.Parse(u.Loc)
+		if err != nil || parsedURL.Hostname() == "" {
+			continue
+		}
+		host := strings.ToLower(parsedURL.Hostname())
+		if !seen[host] {
+			seen[host] = true
+			hosts = append(hosts, host)
+		}
+	}
+
+	failed := make(map[string]error)
+	for _, host := range hosts {
+		if _, err := lookupHost(host); err != nil {
+			failed[host] = err
+		}
+	}
+	return hosts, failed
+}
+
+// skipDNSFailures removes the URLs on unresolvable hosts from the list and returns a
+// DNS_FAIL result for each of them instead
+func skipDNSFailures(urls []URL, failed map[string]error) ([]URL, []Result) {
+	if len(failed) == 0 {
+		return urls, nil
+	}
+
+	remaining := make([]URL, 0, len(urls))
+	var results []Result
+	for _, u := range urls {
+		parsedURL, err := url.Parse(u.Loc)
+		if err == nil {
+			if dnsErr, ok := failed[strings.ToLower(parsedURL.Hostname())]; ok {
+				result := Result{
+					URL:           u.Loc,
+					Error:         fmt.Errorf("DNS lookup failed: %w", dnsErr),
+					SourceSitemap: u.Source,
+					OriginalURL:   u.Original,
+				}
+				result.Issues = append(append([]Issue{}, u.Issues...), Issue{Code: "DNS_FAIL", Message: dnsErr.Error()})
+				results = append(results, result)
+				continue
+			}
+		}
+		remaining = append(remaining, u)
+	}
+	return remaining, results
+}
+
+// formatDNSReport describes the outcome of the DNS pre-check, one line per failed host
+func formatDNSReport(hosts []string, failed map[string]error) []string {
+	lines := []string{fmt.Sprintf("DNS pre-check: %d of %d hosts resolved", len(hosts)-len(failed), len(hosts))}
+	for _, host := range sortedKeys(failed) {
+		lines = append(lines, fmt.Sprintf("DNS_FAIL: %s - %v", host, failed[host]))
+	}
+	return lines
+}
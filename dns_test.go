@@ -0,0 +1,52 @@
+package main
+
+import (
+	"errors"
+	"testing"
+)
+
+// Test for verifyDNS and skipDNSFailures functions
+func TestDNSPreCheck(t *testing.T) {
+	originalLookup := lookupHost
+	defer func() { lookupHost = originalLookup }()
+
+	lookups := 0
+	lookupHost = func(host string) ([]string, error) {
+		lookups++
+		if host == "gone.example.net" {
+			return nil, errors.New("no such host")
+		}
+		return []string{"192.0.2.1"}, nil
+	}
+
+	urls := []URL{
+		{Loc: "https://example.com/a"},
+		{Loc: "https://GONE.example.net/b", Source: "https://example.com/sitemap.xml"},
+		{Loc: "https://example.com/c"},
+		{Loc: "https://gone.example.net/d"},
+	}
+
+	hosts, failed := verifyDNS(urls)
+	if !equalStringSlices(hosts, []string{"example.com", "gone.example.net"}) || lookups != 2 {
+		t.Errorf("verifyDNS() hosts = %v after %d lookups, want each host resolved once", hosts, lookups)
+	}
+	if len(failed) != 1 || failed["gone.example.net"] == nil {
+		t.Fatalf("verifyDNS() failed = %v, want gone.example.net", failed)
+	}
+
+	remaining, results := skipDNSFailures(urls, failed)
+	if !equalStringSlices(urlLocs(remaining), []string{"https://example.com/a", "https://example.com/c"}) {
+		t.Errorf("skipDNSFailures() remaining = %v", urlLocs(remaining))
+	}
+	if len(results) != 2 || results[0].Error == nil || results[0].SourceSitemap != urls[1].Source {
+		t.Fatalf("skipDNSFailures() results = %+v, want two errors", results)
+	}
+	if countIssues(results, "DNS_FAIL") != 2 {
+		t.Errorf("skipDNSFailures() results = %+v, want DNS_FAIL issues", results)
+	}
+
+	report := formatDNSReport(hosts, failed)
+	if len(report) != 2 || report[0] != "DNS pre-check: 1 of 2 hosts resolved" {
+		t.Errorf("formatDNSReport() = %v", report)
+	}
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// DomainLimiter caps how many different domains have requests in flight at once. A domain
+// holds one slot from its first in-flight request until all of its requests have completed.
+type DomainLimiter struct {
+	slots  chan struct{}
+	active sync.Map // domain -> *atomic.Int64 in-flight requests
+
+	// mu serializes a domain becoming active or inactive with the requests joining it
+	mu sync.Mutex
+}
+
+// NewDomainLimiter creates a limiter allowing maxDomains domains at once
+func NewDomainLimiter(maxDomains int) *DomainLimiter {
+	return &DomainLimiter{slots: make(chan struct{}, maxDomains)}
+}
+
+// requestDomain returns the lowercase hostname of a URL
+func requestDomain(rawURL string) string {
+	parsedURL, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(parsedURL.Hostname())
+}
+
+// join counts one more in-flight request to domain if it is already active
+func (d *DomainLimiter) join(domain string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if inFlight, ok := d.active.Load(domain); ok {
+		inFlight.(*atomic.Int64).Add(1)
+		return true
+	}
+	return false
+}
+
+// Acquire waits until a request to rawURL may be sent and returns its domain,
+// which must be passed to Release once the request has completed
+func (d *DomainLimiter) Acquire(rawURL string) string {
+	domain := requestDomain(rawURL)
+	if d == nil || d.join(domain) {
+		return domain
+	}
+
+	// A new domain needs a free slot, which may be taken while waiting
+	d.slots <- struct{}{}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if inFlight, ok := d.active.Load(domain); ok {
+		// Another request to the domain activated it meanwhile
+		inFlight.(*atomic.Int64).Add(1)
+		<-d.slots
+		return domain
+	}
+	inFlight := &atomic.Int64{}
+	inFlight.Store(1)
+	d.active.Store(domain, inFlight)
+	return domain
+}
+
+// Release marks a request to domain as completed, freeing the domain's slot after its last one
+func (d *DomainLimiter) Release(domain string) {
+	if d == nil {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	inFlight, ok := d.active.Load(domain)
+	if !ok {
+		return
+	}
+	if inFlight.(*atomic.Int64).Add(-1) == 0 {
+		d.active.Delete(domain)
+		<-d.slots
+	}
+}
+
+// Active returns how many domains currently have requests in flight
+func (d *DomainLimiter) Active() int {
+	count := 0
+	d.active.Range(func(_, _ any) bool {
+		count++
+		return true
+	})
+	return count
+}
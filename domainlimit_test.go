@@ -0,0 +1,76 @@
+package main
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// Test for DomainLimiter
+func TestDomainLimiter(t *testing.T) {
+	limiter := NewDomainLimiter(2)
+
+	a1 := limiter.Acquire("https://a.example/1")
+	a2 := limiter.Acquire("https://A.example/2")
+	b := limiter.Acquire("https://b.example/")
+	if a1 != "a.example" || a2 != "a.example" || b != "b.example" {
+		t.Fatalf("Acquire() domains = %q, %q, %q", a1, a2, b)
+	}
+	if got := limiter.Active(); got != 2 {
+		t.Errorf("Active() = %d, want 2", got)
+	}
+
+	// A third domain waits until every request to a domain has completed
+	var acquired atomic.Bool
+	done := make(chan string)
+	go func() {
+		domain := limiter.Acquire("https://c.example/")
+		acquired.Store(true)
+		done <- domain
+	}()
+
+	limiter.Release(a1)
+	time.Sleep(20 * time.Millisecond)
+	if acquired.Load() {
+		t.Fatal("third domain acquired while a.example still had a request in flight")
+	}
+	limiter.Release(a2)
+	if domain := <-done; domain != "c.example" {
+		t.Errorf("Acquire() = %q, want c.example", domain)
+	}
+	limiter.Release("c.example")
+	limiter.Release(b)
+	if got := limiter.Active(); got != 0 {
+		t.Errorf("Active() = %d, want 0", got)
+	}
+}
+
+// Test that DomainLimiter never lets more domains than allowed run at once
+func TestDomainLimiterConcurrent(t *testing.T) {
+	limiter := NewDomainLimiter(2)
+	domains := []string{"a", "b", "c", "d"}
+
+	var wg sync.WaitGroup
+	var overLimit atomic.Bool
+	for i := 0; i < 40; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			domain := limiter.Acquire("https://" + domains[i%len(domains)] + ".example/")
+			if limiter.Active() > 2 {
+				overLimit.Store(true)
+			}
+			time.Sleep(time.Millisecond)
+			limiter.Release(domain)
+		}(i)
+	}
+	wg.Wait()
+
+	if overLimit.Load() {
+		t.Error("more than 2 domains were active at once")
+	}
+	if got := limiter.Active(); got != 0 {
+		t.Errorf("Active() = %d, want 0", got)
+	}
+}
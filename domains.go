@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// DomainCount is the number of distinct URLs seen on a hostname
+type DomainCount struct {
+	Host string `json:"host"`
+	URLs int    `json:"urls"`
+}
+
+// countDomains counts the distinct URLs per hostname among the checked URLs and, with
+// includeRedirects, the URLs of their followed redirect chains. The busiest hosts come first.
+func countDomains(results []Result, includeRedirects bool) []DomainCount {
+	seen := make(map[string]bool)
+	counts := make(map[string]int)
+	add := func(rawURL string) {
+		if seen[rawURL] {
+			return
+		}
+		seen[rawURL] = true
+		if parsedURL, err := url.Parse(rawURL); err == nil && parsedURL.Hostname() != "" {
+			counts[strings.ToLower(parsedURL.Hostname())]++
+		}
+	}
+
+	for _, result := range results {
+		add(result.URL)
+		if includeRedirects {
+			for _, u := range result.RedirectChain {
+				add(u)
+			}
+		}
+	}
+
+	domains := make([]DomainCount, 0, len(counts))
+	for _, host := range sortedKeys(counts) {
+		domains = append(domains, DomainCount{Host: host, URLs: counts[host]})
+	}
+	sort.SliceStable(domains, func(i, j int) bool { return domains[i].URLs > domains[j].URLs })
+	return domains
+}
+
+// formatDomainCounts returns one summary line per hostname
+func formatDomainCounts(domains []DomainCount) []string {
+	lines := []string{fmt.Sprintf("Domains: %d unique", len(domains))}
+	for _, domain := range domains {
+		lines = append(lines, fmt.Sprintf("  %s: %d URLs", domain.Host, domain.URLs))
+	}
+	return lines
+}
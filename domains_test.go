@@ -0,0 +1,40 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+// Test for countDomains function
+func TestCountDomains(t *testing.T) {
+	results := []Result{
+		{URL: "https://example.com/a"},
+		{URL: "https://example.com/b", RedirectChain: []string{"https://example.com/b", "https://cdn.example.net/b"}},
+		{URL: "https://shop.example.com/c", RedirectChain: []string{"https://shop.example.com/c", "https://cdn.example.net/c", "https://EXAMPLE.com/a"}},
+		{URL: "https://example.com/a"},
+	}
+
+	tests := []struct {
+		name             string
+		includeRedirects bool
+		want             []DomainCount
+	}{
+		{
+			name: "checked URLs only",
+			want: []DomainCount{{Host: "example.com", URLs: 2}, {Host: "shop.example.com", URLs: 1}},
+		},
+		{
+			name:             "with redirect targets",
+			includeRedirects: true,
+			want:             []DomainCount{{Host: "example.com", URLs: 3}, {Host: "cdn.example.net", URLs: 2}, {Host: "shop.example.com", URLs: 1}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := countDomains(results, tt.includeRedirects); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("countDomains() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+	"mime"
+	"strings"
+	"unicode/utf8"
+)
+
+// checkUTF8 verifies that an HTML page body is valid UTF-8 and that its
+// Content-Type header declares the UTF-8 charset
+func checkUTF8(result *Result, page *Page) {
+	contentType := page.Header.Get("Content-Type")
+	if !isHTML(contentType) {
+		return
+	}
+
+	body := page.Body
+	if page.Truncated {
+		body = trimPartialRune(body)
+	}
+
+	result.UTF8Valid = utf8.Valid(body)
+	if !result.UTF8Valid {
+		result.addIssue("ENCODING_INVALID", "response body is not valid UTF-8")
+	}
+
+	_, params, _ := mime.ParseMediaType(contentType)
+	charset := strings.ToLower(params["charset"])
+	if charset != "utf-8" && charset != "utf8" {
+		result.addIssue("MISSING_CHARSET", fmt.Sprintf("Content-Type %q does not declare charset=utf-8", contentType))
+	}
+}
+
+// trimPartialRune removes an incomplete UTF-8 sequence left at the end of a truncated body
+func trimPartialRune(body []byte) []byte {
+	for n := 1; n <= utf8.UTFMax && n <= len(body); n++ {
+		if utf8.RuneStart(body[len(body)-n]) {
+			if !utf8.FullRune(body[len(body)-n:]) {
+				return body[:len(body)-n]
+			}
+			break
+		}
+	}
+	return body
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+)
+
+// Test for checkUTF8 function
+func TestCheckUTF8(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        []byte
+		truncated   bool
+		wantCodes   []string
+	}{
+		{name: "valid", contentType: "text/html; charset=UTF-8", body: []byte("<p>Привет</p>")},
+		{name: "invalid bytes", contentType: "text/html; charset=utf-8", body: []byte{'<', 'p', '>', 0xff, 0xfe}, wantCodes: []string{"ENCODING_INVALID"}},
+		{name: "missing charset", contentType: "text/html", body: []byte("<p>Hello</p>"), wantCodes: []string{"MISSING_CHARSET"}},
+		{name: "truncated in rune", contentType: "text/html; charset=utf-8", body: []byte("<p>Пр")[:6], truncated: true},
+		{name: "not html", contentType: "application/json", body: []byte{0xff}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page := &Page{
+				Header:    http.Header{"Content-Type": {tt.contentType}},
+				Body:      tt.body,
+				Truncated: tt.truncated,
+			}
+			result := Result{URL: "https://example.com/", Status: http.StatusOK}
+			checkUTF8(&result, page)
+
+			var codes []string
+			for _, issue := range result.Issues {
+				codes = append(codes, issue.Code)
+			}
+			if !equalStringSlices(codes, tt.wantCodes) {
+				t.Errorf("checkUTF8() issues = %v, want %v", codes, tt.wantCodes)
+			}
+		})
+	}
+}
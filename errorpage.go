@@ -0,0 +1,50 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// defaultErrorSignature identifies the built-in error page of a web server
+type defaultErrorSignature struct {
+	Server string
+	Marker string
+}
+
+// defaultErrorSignatures are text fragments found only in the stock error pages of common servers
+var defaultErrorSignatures = []defaultErrorSignature{
+	{Server: "nginx", Marker: "<center>nginx"},
+	{Server: "Apache", Marker: "<address>Apache/"},
+	{Server: "Apache", Marker: "The requested URL was not found on this server."},
+	{Server: "Tomcat", Marker: "Apache Tomcat/"},
+	{Server: "IIS", Marker: "404 - File or directory not found."},
+	{Server: "IIS", Marker: "The resource you are looking for has been removed, had its name changed, or is temporarily unavailable."},
+	{Server: "LiteSpeed", Marker: "Proudly powered by LiteSpeed Web Server"},
+	{Server: "Go net/http", Marker: "404 page not found"},
+	{Server: "Express", Marker: "<pre>Cannot GET /"},
+}
+
+// defaultErrorServer returns the server whose stock error page a body is, or an empty
+// string for a custom page. An empty body counts as the server's default.
+func defaultErrorServer(body []byte) string {
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) == 0 {
+		return "empty body"
+	}
+	for _, signature := range defaultErrorSignatures {
+		if bytes.Contains(trimmed, []byte(signature.Marker)) {
+			return signature.Server
+		}
+	}
+	return ""
+}
+
+// checkCustom404 flags 404 responses that serve the web server's default error page
+// instead of a custom one
+func checkCustom404(result *Result, page *Page) {
+	server := defaultErrorServer(page.Body)
+	result.CustomErrorPage = server == ""
+	if !result.CustomErrorPage {
+		result.addIssue("DEFAULT_404_PAGE", fmt.Sprintf("404 response is the default error page (%s)", server))
+	}
+}
@@ -0,0 +1,32 @@
+package main
+
+import "testing"
+
+// Test for checkCustom404 function
+func TestCheckCustom404(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want bool
+	}{
+		{name: "nginx", body: "<html>\r\n<head><title>404 Not Found</title></head>\r\n<body>\r\n<center><h1>404 Not Found</h1></center>\r\n<hr><center>nginx/1.25.3</center>\r\n</body>\r\n</html>"},
+		{name: "apache", body: "<h1>Not Found</h1>\n<p>The requested URL was not found on this server.</p>\n<hr>\n<address>Apache/2.4.57 (Debian) Server at example.com Port 80</address>"},
+		{name: "go", body: "404 page not found\n"},
+		{name: "empty", body: "  \n"},
+		{name: "custom", body: `<html><head><title>Page not found | Example</title></head><body><h1>Sorry, we couldn't find that page</h1><a href="/">Home</a></body></html>`, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Result{URL: "https://example.com/missing", Status: 404}
+			checkCustom404(&result, &Page{URL: result.URL, Status: 404, Body: []byte(tt.body)})
+
+			if result.CustomErrorPage != tt.want {
+				t.Errorf("CustomErrorPage = %v, want %v", result.CustomErrorPage, tt.want)
+			}
+			if got := countIssues([]Result{result}, "DEFAULT_404_PAGE") == 1; got == tt.want {
+				t.Errorf("DEFAULT_404_PAGE flagged = %v, want %v", got, !tt.want)
+			}
+		})
+	}
+}
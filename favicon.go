@@ -0,0 +1,117 @@
+package main
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// FaviconStatus is the outcome of the favicon check of a host
+type FaviconStatus struct {
+	Host string
+	// FaviconICO describes the response to /favicon.ico, empty when it is a valid image
+	FaviconICO string
+	// Icons are the rel=icon links declared by the host's checked pages
+	Icons       []string
+	BrokenIcons []string
+	FaviconOK   bool
+}
+
+// isImage reports whether a Content-Type header value denotes an image
+func isImage(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && strings.HasPrefix(mediaType, "image/")
+}
+
+// fetchFaviconICO requests /favicon.ico of a host and describes why it isn't a usable
+// icon, or returns an empty string when it is. Browsers follow a redirected /favicon.ico,
+// so the icon is judged where it ends up even when client doesn't follow redirects.
+func fetchFaviconICO(client *http.Client, scheme, host string) string {
+	follow := *client
+	follow.CheckRedirect = nil
+	page, err := fetchPage(&follow, fmt.Sprintf("%s://%s/favicon.ico", scheme, host))
+	switch {
+	case err != nil:
+		return err.Error()
+	case page.Status != http.StatusOK:
+		return fmt.Sprintf("status %d", page.Status)
+	case !isImage(page.Header.Get("Content-Type")):
+		return fmt.Sprintf("Content-Type %q is not an image", page.Header.Get("Content-Type"))
+	}
+	return ""
+}
+
+// checkFavicons verifies the favicon of every host with checked URLs: /favicon.ico must be
+// an image unless a declared rel=icon works, and every declared icon must be reachable
+func checkFavicons(client *http.Client, results []Result, logger *Logger) []FaviconStatus {
+	schemes := make(map[string]string)
+	icons := make(map[string][]string)
+	seenIcon := make(map[string]bool)
+	for _, result := range results {
+		parsedURL, err := url.Parse(result.URL)
+		if err != nil || parsedURL.Host == "" {
+			continue
+		}
+		host := strings.ToLower(parsedURL.Host)
+		if _, ok := schemes[host]; !ok {
+			schemes[host] = parsedURL.Scheme
+		}
+		for _, icon := range result.IconLinks {
+			if !seenIcon[host+" "+icon] {
+				seenIcon[host+" "+icon] = true
+				icons[host] = append(icons[host], icon)
+			}
+		}
+	}
+
+	var statuses []FaviconStatus
+	for _, host := range sortedKeys(schemes) {
+		status := FaviconStatus{Host: host, Icons: icons[host]}
+		status.FaviconICO = fetchFaviconICO(client, schemes[host], host)
+
+		for _, icon := range status.Icons {
+			if code, err := headCheck(client, icon); isBroken(code, err) {
+				status.BrokenIcons = append(status.BrokenIcons, icon)
+			}
+		}
+		workingIcon := len(status.Icons) > len(status.BrokenIcons)
+		status.FaviconOK = (status.FaviconICO == "" || workingIcon) && len(status.BrokenIcons) == 0
+
+		if !status.FaviconOK {
+			msg := fmt.Sprintf("FAVICON_MISSING: %s - %s", host, status.problems())
+			fmt.Println(msg)
+			if logger != nil {
+				logger.Log(msg)
+			}
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// problems describes what is wrong with a host's favicon
+func (s FaviconStatus) problems() string {
+	var problems []string
+	if s.FaviconICO != "" {
+		problems = append(problems, "/favicon.ico: "+s.FaviconICO)
+	}
+	if len(s.BrokenIcons) > 0 {
+		problems = append(problems, "broken rel=icon: "+strings.Join(s.BrokenIcons, ", "))
+	}
+	return strings.Join(problems, "; ")
+}
+
+// formatFaviconSummary returns one line per host with its favicon status
+func formatFaviconSummary(statuses []FaviconStatus) []string {
+	var lines []string
+	for _, status := range statuses {
+		state := "OK"
+		if !status.FaviconOK {
+			state = "FAVICON_MISSING (" + status.problems() + ")"
+		}
+		lines = append(lines, fmt.Sprintf("Favicon %s: %s", status.Host, state))
+	}
+	return lines
+}
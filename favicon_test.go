@@ -0,0 +1,97 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// Test for checkFavicons function
+func TestCheckFavicons(t *testing.T) {
+	serveIcon := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/x-icon")
+		w.Write([]byte{0, 0, 1, 0})
+	}
+	withICO := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/favicon.ico" || r.URL.Path == "/icon.png" {
+			serveIcon(w, r)
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer withICO.Close()
+
+	withoutICO := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/favicon.ico":
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte("<html>Not here</html>"))
+		case "/static/icon.svg":
+			serveIcon(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer withoutICO.Close()
+
+	results := []Result{
+		{URL: withICO.URL + "/", Status: 200, IconLinks: []string{withICO.URL + "/icon.png"}},
+		{URL: withICO.URL + "/about", Status: 200, IconLinks: []string{withICO.URL + "/icon.png", withICO.URL + "/missing.png"}},
+		{URL: withoutICO.URL + "/", Status: 200, IconLinks: []string{withoutICO.URL + "/static/icon.svg"}},
+	}
+
+	statuses := checkFavicons(withICO.Client(), results, nil)
+	if len(statuses) != 2 {
+		t.Fatalf("got %d hosts, want 2: %+v", len(statuses), statuses)
+	}
+	byHost := make(map[string]FaviconStatus)
+	for _, status := range statuses {
+		byHost["http://"+status.Host] = status
+	}
+
+	broken := byHost[withICO.URL]
+	if broken.FaviconOK || broken.FaviconICO != "" || !reflect.DeepEqual(broken.BrokenIcons, []string{withICO.URL + "/missing.png"}) {
+		t.Errorf("host with a broken icon = %+v, want FAVICON_MISSING for missing.png", broken)
+	}
+
+	declared := byHost[withoutICO.URL]
+	if !declared.FaviconOK || !strings.Contains(declared.FaviconICO, "not an image") {
+		t.Errorf("host with a declared icon = %+v, want OK despite /favicon.ico", declared)
+	}
+
+	lines := formatFaviconSummary(statuses)
+	if len(lines) != 2 || !strings.Contains(strings.Join(lines, "\n"), "FAVICON_MISSING (broken rel=icon: "+withICO.URL+"/missing.png)") {
+		t.Errorf("formatFaviconSummary() = %q", lines)
+	}
+}
+
+// Test for fetchFaviconICO function
+func TestFetchFaviconICO(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	host := strings.TrimPrefix(server.URL, "http://")
+	if got := fetchFaviconICO(server.Client(), "http", host); got != "status 404" {
+		t.Errorf("fetchFaviconICO() = %q, want status 404", got)
+	}
+
+	// A redirected favicon is judged where it ends up, even with a client that doesn't follow redirects
+	redirecting := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/favicon.ico" {
+			http.Redirect(w, r, "/static/favicon.ico", http.StatusMovedPermanently)
+			return
+		}
+		w.Header().Set("Content-Type", "image/x-icon")
+		w.Write([]byte{0, 0, 1, 0})
+	}))
+	defer redirecting.Close()
+
+	client := redirecting.Client()
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error { return http.ErrUseLastResponse }
+	host = strings.TrimPrefix(redirecting.URL, "http://")
+	if got := fetchFaviconICO(client, "http", host); got != "" {
+		t.Errorf("fetchFaviconICO() for a 301 favicon = %q, want usable", got)
+	}
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"mime"
+	"strings"
+)
+
+// AtomFeed represents an Atom feed
+type AtomFeed struct {
+	XMLName xml.Name    `xml:"feed"`
+	Entries []AtomEntry `xml:"entry"`
+}
+
+// AtomEntry represents an entry in an Atom feed
+type AtomEntry struct {
+	Links []AtomLink `xml:"link"`
+}
+
+// AtomLink represents a link of an Atom entry
+type AtomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+}
+
+// RSSFeed represents an RSS 2.0 feed
+type RSSFeed struct {
+	XMLName xml.Name `xml:"rss"`
+	Items   []struct {
+		Link string `xml:"link"`
+	} `xml:"channel>item"`
+}
+
+// feedFormat returns "atom" or "rss" if a document is a news feed, or an empty string.
+// The Content-Type is checked first; feeds are often served as text/xml, so the root
+// element decides otherwise.
+func feedFormat(contentType string, body []byte) string {
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		switch mediaType {
+		case "application/atom+xml":
+			return "atom"
+		case "application/rss+xml":
+			return "rss"
+		}
+	}
+
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return ""
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			switch start.Name.Local {
+			case "feed":
+				return "atom"
+			case "rss":
+				return "rss"
+			}
+			return ""
+		}
+	}
+}
+
+// parseFeed extracts the entry or item links of an Atom or RSS feed
+func parseFeed(format string, body []byte) ([]URL, error) {
+	var urls []URL
+	switch format {
+	case "atom":
+		var feed AtomFeed
+		if err := xml.Unmarshal(body, &feed); err != nil {
+			return nil, fmt.Errorf("error parsing Atom feed: %w", err)
+		}
+		for _, entry := range feed.Entries {
+			// An entry links to its content with rel="alternate", which is also the default
+			for _, link := range entry.Links {
+				if link.Rel == "" || link.Rel == "alternate" {
+					urls = append(urls, URL{Loc: strings.TrimSpace(link.Href)})
+					break
+				}
+			}
+		}
+	case "rss":
+		var feed RSSFeed
+		if err := xml.Unmarshal(body, &feed); err != nil {
+			return nil, fmt.Errorf("error parsing RSS feed: %w", err)
+		}
+		for _, item := range feed.Items {
+			if link := strings.TrimSpace(item.Link); link != "" {
+				urls = append(urls, URL{Loc: link})
+			}
+		}
+	default:
+		return nil, fmt.Errorf("unknown feed format %q", format)
+	}
+	return urls, nil
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+const testAtomFeed = `<?xml version="1.0" encoding="utf-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <title>Example</title>
+  <link href="https://example.com/" rel="alternate"/>
+  <entry>
+    <link rel="edit" href="https://example.com/edit/1"/>
+    <link href="https://example.com/post1"/>
+  </entry>
+  <entry>
+    <link rel="alternate" href="https://example.com/post2"/>
+  </entry>
+</feed>`
+
+const testRSSFeed = `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0">
+  <channel>
+    <title>Example</title>
+    <link>https://example.com/</link>
+    <item><link>https://example.com/news1</link></item>
+    <item><link> https://example.com/news2 </link></item>
+  </channel>
+</rss>`
+
+// Test for feedFormat function
+func TestFeedFormat(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		want        string
+	}{
+		{name: "atom content type", contentType: "application/atom+xml; charset=utf-8", body: testAtomFeed, want: "atom"},
+		{name: "rss content type", contentType: "application/rss+xml", body: testRSSFeed, want: "rss"},
+		{name: "atom served as xml", contentType: "text/xml", body: testAtomFeed, want: "atom"},
+		{name: "rss without content type", body: testRSSFeed, want: "rss"},
+		{name: "sitemap", contentType: "application/xml", body: `<?xml version="1.0"?><urlset></urlset>`, want: ""},
+		{name: "not xml", contentType: "text/plain", body: "hello", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := feedFormat(tt.contentType, []byte(tt.body)); got != tt.want {
+				t.Errorf("feedFormat() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// Test for parseFeed function
+func TestParseFeed(t *testing.T) {
+	tests := []struct {
+		format string
+		body   string
+		want   []string
+	}{
+		{format: "atom", body: testAtomFeed, want: []string{"https://example.com/post1", "https://example.com/post2"}},
+		{format: "rss", body: testRSSFeed, want: []string{"https://example.com/news1", "https://example.com/news2"}},
+	}
+
+	for _, tt := range tests {
+		urls, err := parseFeed(tt.format, []byte(tt.body))
+		if err != nil {
+			t.Fatalf("parseFeed(%q) error = %v", tt.format, err)
+		}
+		if got := urlLocs(urls); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseFeed(%q) = %v, want %v", tt.format, got, tt.want)
+		}
+	}
+
+	if _, err := parseFeed("rss", []byte("<rss><channel>")); err == nil {
+		t.Error("parseFeed() with truncated feed error = nil, want error")
+	}
+}
+
+// Test that retrieveAllURLs reads feeds listed in a sitemap index
+func TestRetrieveAllURLsFeed(t *testing.T) {
+	responses := map[string]string{
+		"https://example.com/sitemapindex.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <sitemap><loc>https://example.com/feed.rss</loc></sitemap>
+</sitemapindex>`,
+		"https://example.com/feed.rss": testRSSFeed,
+	}
+
+	client := &http.Client{Transport: &mockTransport{responses: responses}}
+
+	got, err := retrieveAllURLs(client, "https://example.com/sitemapindex.xml", false)
+	if err != nil {
+		t.Fatalf("retrieveAllURLs() error = %v", err)
+	}
+
+	want := []string{"https://example.com/news1", "https://example.com/news2"}
+	if !reflect.DeepEqual(urlLocs(got), want) {
+		t.Errorf("retrieveAllURLs() = %v, want %v", urlLocs(got), want)
+	}
+	for _, u := range got {
+		if u.Source != "https://example.com/feed.rss" {
+			t.Errorf("Source for %s = %q, want the feed URL", u.Loc, u.Source)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"regexp"
+)
+
+// fontURLPattern matches url(...) references to web font files in a stylesheet
+var fontURLPattern = regexp.MustCompile(`(?i)url\(\s*['"]?([^'")]+?\.(?:woff2?|ttf|eot)(?:[?#][^'")]*)?)['"]?\s*\)`)
+
+// isCSS reports whether a Content-Type header value denotes a stylesheet
+func isCSS(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && mediaType == "text/css"
+}
+
+// fontURLs returns the unique font URLs referenced by a stylesheet, resolved against its URL
+func fontURLs(cssURL string, css []byte) []string {
+	seen := make(map[string]bool)
+	var fonts []string
+	for _, match := range fontURLPattern.FindAllSubmatch(css, -1) {
+		font := resolveURL(cssURL, string(match[1]))
+		if !seen[font] {
+			seen[font] = true
+			fonts = append(fonts, font)
+		}
+	}
+	return fonts
+}
+
+// checkFonts HEAD-checks the web fonts a stylesheet references
+func checkFonts(client *http.Client, result *Result, css *Page) {
+	for _, font := range fontURLs(result.URL, css.Body) {
+		status, err := headCheck(client, font)
+		if !isBroken(status, err) {
+			continue
+		}
+
+		msg := fmt.Sprintf("%s (Status: %d)", font, status)
+		if err != nil {
+			msg = fmt.Sprintf("%s: %v", font, err)
+		}
+		result.FontIssues = append(result.FontIssues, msg)
+		result.addIssue("FONT_BROKEN", msg)
+	}
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Test for fontURLs function
+func TestFontURLs(t *testing.T) {
+	css := []byte(`
+@font-face {
+  font-family: "Inter";
+  src: url("../fonts/inter.woff2") format("woff2"),
+       url('../fonts/inter.woff') format("woff"),
+       url(/fonts/inter.ttf?v=3) format("truetype"),
+       url(../fonts/inter.eot#iefix);
+}
+body { background: url(/img/bg.png); }
+.again { src: url("../fonts/inter.woff2"); }
+`)
+
+	got := fontURLs("https://example.com/css/site.css", css)
+	want := []string{
+		"https://example.com/fonts/inter.woff2",
+		"https://example.com/fonts/inter.woff",
+		"https://example.com/fonts/inter.ttf?v=3",
+		"https://example.com/fonts/inter.eot#iefix",
+	}
+	if !equalStringSlices(got, want) {
+		t.Errorf("fontURLs() = %v, want %v", got, want)
+	}
+}
+
+// Test for checkFonts function
+func TestCheckFonts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/fonts/ok.woff2" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	css := &Page{URL: server.URL + "/site.css", Body: []byte(`src: url(/fonts/ok.woff2), url(/fonts/missing.woff);`)}
+	result := Result{URL: css.URL, Status: http.StatusOK}
+	checkFonts(server.Client(), &result, css)
+
+	if len(result.FontIssues) != 1 || len(result.Issues) != 1 || result.Issues[0].Code != "FONT_BROKEN" {
+		t.Errorf("FontIssues = %v, Issues = %v, want one FONT_BROKEN for missing.woff", result.FontIssues, result.Issues)
+	}
+}
@@ -0,0 +1,45 @@
+//go:build ftp
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"time"
+
+	"github.com/jlaffaye/ftp"
+)
+
+// fetchFTP retrieves a file over FTP. Credentials in the URL take precedence over
+// -ftp-user and -ftp-pass; without either the anonymous login is used.
+func fetchFTP(rawURL string) ([]byte, error) {
+	parsedURL, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	host := parsedURL.Host
+	if parsedURL.Port() == "" {
+		host += ":21"
+	}
+
+	conn, err := ftp.Dial(host, ftp.DialWithTimeout(30*time.Second))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to FTP server %s: %w", host, err)
+	}
+	defer conn.Quit()
+
+	user, pass := ftpCredentials(parsedURL)
+	if err := conn.Login(user, pass); err != nil {
+		return nil, fmt.Errorf("FTP login failed: %w", err)
+	}
+
+	resp, err := conn.Retr(parsedURL.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve %s: %w", parsedURL.Path, err)
+	}
+	defer resp.Close()
+
+	return io.ReadAll(resp)
+}
@@ -0,0 +1,10 @@
+//go:build !ftp
+
+package main
+
+import "errors"
+
+// fetchFTP reports that FTP support was left out of this build
+func fetchFTP(rawURL string) ([]byte, error) {
+	return nil, errors.New("FTP support is not compiled in, rebuild with -tags ftp")
+}
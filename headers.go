@@ -0,0 +1,352 @@
+package main
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// versionPattern matches version numbers disclosed in headers such as Server: Apache/2.2.3
+var versionPattern = regexp.MustCompile(`\d+\.\d+`)
+
+// headerValues returns the comma-separated values of a header, across all its lines
+func headerValues(header http.Header, name string) []string {
+	var values []string
+	for _, line := range header.Values(name) {
+		for _, value := range strings.Split(line, ",") {
+			if value = strings.TrimSpace(value); value != "" {
+				values = append(values, value)
+			}
+		}
+	}
+	return values
+}
+
+// hasHeaderValue reports whether a comma-separated header contains the value, ignoring case
+func hasHeaderValue(header http.Header, name, value string) bool {
+	for _, v := range headerValues(header, name) {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkVary verifies that a compressed response tells caches it varies by Accept-Encoding
+func checkVary(result *Result) {
+	encoding := result.Header.Get("Content-Encoding")
+	if encoding == "" || strings.EqualFold(encoding, "identity") {
+		return
+	}
+
+	if hasHeaderValue(result.Header, "Vary", "Accept-Encoding") || hasHeaderValue(result.Header, "Vary", "*") {
+		return
+	}
+
+	msg := fmt.Sprintf("Content-Encoding: %s without Vary: Accept-Encoding", encoding)
+	result.VaryIssues = append(result.VaryIssues, msg)
+	result.addIssue("MISSING_VARY", msg)
+}
+
+// checkServerHeader records the Server header and optionally flags disclosed version numbers
+func checkServerHeader(result *Result, flagDisclosure bool) {
+	result.ServerHeader = result.Header.Get("Server")
+	if flagDisclosure && versionPattern.MatchString(result.ServerHeader) {
+		result.addIssue("SERVER_DISCLOSURE", fmt.Sprintf("Server header reveals version: %s", result.ServerHeader))
+	}
+}
+
+// checkXPoweredBy records the X-Powered-By header and flags values revealing a version
+// number, unless they match the allowed pattern
+func checkXPoweredBy(result *Result, allowed *regexp.Regexp) {
+	result.XPoweredBy = result.Header.Get("X-Powered-By")
+	if !versionPattern.MatchString(result.XPoweredBy) {
+		return
+	}
+	if allowed != nil && allowed.MatchString(result.XPoweredBy) {
+		return
+	}
+	result.addIssue("VERSION_DISCLOSURE", fmt.Sprintf("X-Powered-By header reveals version: %s", result.XPoweredBy))
+}
+
+// isStaticAsset reports whether a Content-Type header value denotes an image, script or stylesheet
+func isStaticAsset(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	switch mediaType {
+	case "application/javascript", "text/javascript", "application/x-javascript", "text/css":
+		return true
+	}
+	return strings.HasPrefix(mediaType, "image/")
+}
+
+// assetType returns the kind of static asset a Content-Type header value denotes, or an empty string
+func assetType(contentType string) string {
+	if !isStaticAsset(contentType) {
+		return ""
+	}
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch {
+	case mediaType == "text/css":
+		return "stylesheet"
+	case strings.HasPrefix(mediaType, "image/"):
+		return "image"
+	}
+	return "script"
+}
+
+// parseMaxAge returns the max-age directive of a Cache-Control header value
+func parseMaxAge(cacheControl string) (int, bool) {
+	for _, directive := range strings.Split(cacheControl, ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(directive), "=")
+		if !ok || !strings.EqualFold(name, "max-age") {
+			continue
+		}
+		maxAge, err := strconv.Atoi(strings.Trim(value, `"`))
+		if err != nil {
+			return 0, false
+		}
+		return maxAge, true
+	}
+	return 0, false
+}
+
+// checkCacheHeaders verifies static assets can be cached, either with a positive
+// Cache-Control max-age or an Expires date in the future
+func checkCacheHeaders(result *Result) {
+	if !isStaticAsset(result.Header.Get("Content-Type")) {
+		return
+	}
+
+	result.CacheControl = result.Header.Get("Cache-Control")
+	if maxAge, ok := parseMaxAge(result.CacheControl); ok && maxAge > 0 {
+		return
+	}
+	if expires, err := http.ParseTime(result.Header.Get("Expires")); err == nil && expires.After(time.Now()) {
+		return
+	}
+
+	result.addIssue("NO_CACHE_HEADERS", fmt.Sprintf("%s asset has no max-age or future Expires (Cache-Control: %q)",
+		result.Header.Get("Content-Type"), result.CacheControl))
+}
+
+// checkHTMLMaxAge flags HTML pages a cache may keep for longer than limit seconds,
+// so visitors keep seeing a stale page after it changes
+func checkHTMLMaxAge(result *Result, limit int) {
+	if !isHTML(result.Header.Get("Content-Type")) {
+		return
+	}
+	maxAge, ok := parseMaxAge(result.Header.Get("Cache-Control"))
+	if !ok {
+		return
+	}
+	result.MaxAge = maxAge
+	if maxAge > limit {
+		result.addIssue("HTML_CACHE_TOO_LONG", fmt.Sprintf("Cache-Control max-age=%d exceeds %d seconds", maxAge, limit))
+	}
+}
+
+// checkExpires records the Expires date of a response, flagging dates in the past and
+// responses that set neither Expires nor Cache-Control. An invalid date such as "0"
+// means the response has already expired.
+func checkExpires(result *Result) {
+	value := result.Header.Get("Expires")
+	if value == "" {
+		if result.Header.Get("Cache-Control") == "" {
+			result.addIssue("NO_EXPIRY_HEADERS", "response has no Expires or Cache-Control header")
+		}
+		return
+	}
+
+	expires, err := http.ParseTime(value)
+	if err != nil {
+		result.addIssue("ALREADY_EXPIRED", fmt.Sprintf("Expires %q is not a valid HTTP date", value))
+		return
+	}
+	result.Expires = &expires
+	if expires.Before(time.Now()) {
+		result.addIssue("ALREADY_EXPIRED", fmt.Sprintf("Expires %s is in the past", expires.Format(http.TimeFormat)))
+	}
+}
+
+// formatHTMLMaxAgeSummary reports the average max-age of the HTML pages that declare one
+func formatHTMLMaxAgeSummary(results []Result) string {
+	count, total := 0, 0
+	for _, result := range results {
+		if result.Header == nil || !isHTML(result.Header.Get("Content-Type")) {
+			continue
+		}
+		if _, ok := parseMaxAge(result.Header.Get("Cache-Control")); ok {
+			count++
+			total += result.MaxAge
+		}
+	}
+	if count == 0 {
+		return "HTML max-age: no pages declare one"
+	}
+	return fmt.Sprintf("HTML max-age: average %d seconds over %d pages, %d too long",
+		total/count, count, countIssues(results, "HTML_CACHE_TOO_LONG"))
+}
+
+// checkProtocol flags responses served over HTTP/1.0, which has no persistent connections.
+// A reverse proxy may answer with a different version than the backend behind it.
+func checkProtocol(result *Result) {
+	if result.Protocol == "HTTP/1.0" {
+		result.addIssue("OLD_PROTOCOL", "response was served over HTTP/1.0")
+	}
+}
+
+// checkXContentTypeOptions flags responses that let browsers MIME-sniff the content type
+func checkXContentTypeOptions(result *Result) {
+	if strings.EqualFold(strings.TrimSpace(result.Header.Get("X-Content-Type-Options")), "nosniff") {
+		return
+	}
+	result.addIssue("MISSING_XCTO", "X-Content-Type-Options: nosniff is missing; add it to every response "+
+		"(e.g. nginx: add_header X-Content-Type-Options nosniff always; Apache: Header always set X-Content-Type-Options nosniff)")
+}
+
+// formatXCTOSummary reports the share of 200 responses with and without X-Content-Type-Options: nosniff
+func formatXCTOSummary(results []Result) string {
+	checked := 0
+	for _, result := range results {
+		if result.Error == nil && result.Status == http.StatusOK {
+			checked++
+		}
+	}
+	if checked == 0 {
+		return "X-Content-Type-Options: no 200 responses checked"
+	}
+
+	missing := countIssues(results, "MISSING_XCTO")
+	withPct := float64(checked-missing) / float64(checked) * 100
+	return fmt.Sprintf("X-Content-Type-Options: nosniff on %d URLs (%.1f%%), missing on %d URLs (%.1f%%)",
+		checked-missing, withPct, missing, 100-withPct)
+}
+
+// checkAssetNosniff flags scripts, stylesheets and images served without
+// X-Content-Type-Options: nosniff, which browsers may then MIME-sniff
+func checkAssetNosniff(result *Result) {
+	kind := assetType(result.Header.Get("Content-Type"))
+	if kind == "" {
+		return
+	}
+	if strings.EqualFold(strings.TrimSpace(result.Header.Get("X-Content-Type-Options")), "nosniff") {
+		return
+	}
+	result.addIssue("NOSNIFF_MISSING", fmt.Sprintf("%s served without X-Content-Type-Options: nosniff", kind))
+}
+
+// formatNosniffSummary counts the assets missing nosniff by asset type
+func formatNosniffSummary(results []Result) string {
+	missing := make(map[string]int)
+	for _, result := range results {
+		for _, issue := range result.Issues {
+			if issue.Code == "NOSNIFF_MISSING" {
+				missing[assetType(result.Header.Get("Content-Type"))]++
+				break
+			}
+		}
+	}
+	if len(missing) == 0 {
+		return "Assets without nosniff: none"
+	}
+
+	parts := make([]string, 0, len(missing))
+	for _, kind := range sortedKeys(missing) {
+		parts = append(parts, fmt.Sprintf("%s %d", kind, missing[kind]))
+	}
+	return "Assets without nosniff: " + strings.Join(parts, ", ")
+}
+
+// unsafeCSPSources are CSP source expressions that defeat the protection against XSS
+var unsafeCSPSources = []string{"'unsafe-inline'", "'unsafe-eval'"}
+
+// checkCSP flags responses without a Content-Security-Policy or with one allowing inline or eval'd scripts
+func checkCSP(result *Result) {
+	result.CSPHeader = strings.TrimSpace(strings.Join(result.Header.Values("Content-Security-Policy"), ", "))
+	if result.CSPHeader == "" {
+		result.addIssue("MISSING_CSP", "no Content-Security-Policy header")
+		return
+	}
+
+	var unsafe []string
+	policy := strings.ToLower(result.CSPHeader)
+	for _, source := range unsafeCSPSources {
+		if strings.Contains(policy, source) {
+			unsafe = append(unsafe, source)
+		}
+	}
+	if len(unsafe) > 0 {
+		result.addIssue("WEAK_CSP", fmt.Sprintf("Content-Security-Policy allows %s", strings.Join(unsafe, " and ")))
+	}
+}
+
+// formatCSPSummary counts the missing, weak and strong policies among checked 200 responses
+func formatCSPSummary(results []Result) string {
+	checked := 0
+	for _, result := range results {
+		if result.Error == nil && result.Status == http.StatusOK {
+			checked++
+		}
+	}
+	missing := countIssues(results, "MISSING_CSP")
+	weak := countIssues(results, "WEAK_CSP")
+	return fmt.Sprintf("Content-Security-Policy: %d missing, %d weak, %d strong", missing, weak, checked-missing-weak)
+}
+
+// offeredEncodings are the content codings sent in Accept-Encoding by the compression checks
+var offeredEncodings = []string{"gzip", "br"}
+
+// isTextContent reports whether a Content-Type header value denotes a text/* document
+func isTextContent(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && strings.HasPrefix(mediaType, "text/")
+}
+
+// checkCompression records the Content-Encoding of a text response and flags responses
+// sent uncompressed or with a coding that was not offered
+func checkCompression(result *Result) {
+	if !isTextContent(result.Header.Get("Content-Type")) {
+		return
+	}
+
+	encoding := strings.ToLower(strings.TrimSpace(result.Header.Get("Content-Encoding")))
+	if encoding == "" || encoding == "identity" {
+		result.CompressionEncoding = "identity"
+		result.addIssue("NO_COMPRESSION", fmt.Sprintf("text response served uncompressed although %s was offered", strings.Join(offeredEncodings, " and ")))
+		return
+	}
+
+	result.CompressionEncoding = encoding
+	for _, offered := range offeredEncodings {
+		if encoding == offered {
+			return
+		}
+	}
+	result.addIssue("UNOFFERED_ENCODING", fmt.Sprintf("Content-Encoding: %s was not offered in Accept-Encoding", encoding))
+}
+
+// formatCompressionSummary reports the share of checked text responses served compressed
+func formatCompressionSummary(results []Result) string {
+	checked, compressed := 0, 0
+	for _, result := range results {
+		if result.CompressionEncoding == "" {
+			continue
+		}
+		checked++
+		if result.CompressionEncoding != "identity" {
+			compressed++
+		}
+	}
+	if checked == 0 {
+		return "Compression: no text responses checked"
+	}
+	return fmt.Sprintf("Compression: %d of %d text responses compressed (%.1f%%)",
+		compressed, checked, float64(compressed)/float64(checked)*100)
+}
@@ -0,0 +1,378 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"regexp"
+	"testing"
+	"time"
+)
+
+// Test for checkVary function
+func TestCheckVary(t *testing.T) {
+	tests := []struct {
+		name      string
+		header    http.Header
+		wantIssue bool
+	}{
+		{name: "uncompressed", header: http.Header{}},
+		{name: "compressed with vary", header: http.Header{"Content-Encoding": {"gzip"}, "Vary": {"Cookie, Accept-Encoding"}}},
+		{name: "compressed with vary on separate lines", header: http.Header{"Content-Encoding": {"br"}, "Vary": {"Cookie", "accept-encoding"}}},
+		{name: "compressed with vary star", header: http.Header{"Content-Encoding": {"gzip"}, "Vary": {"*"}}},
+		{name: "compressed without vary", header: http.Header{"Content-Encoding": {"gzip"}}, wantIssue: true},
+		{name: "compressed with other vary", header: http.Header{"Content-Encoding": {"gzip"}, "Vary": {"Cookie"}}, wantIssue: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Result{URL: "https://example.com/", Status: http.StatusOK, Header: tt.header}
+			checkVary(&result)
+			if got := len(result.VaryIssues) > 0; got != tt.wantIssue {
+				t.Errorf("checkVary() issues = %v, want issue %v", result.VaryIssues, tt.wantIssue)
+			}
+		})
+	}
+}
+
+// Test for checkServerHeader function
+func TestCheckServerHeader(t *testing.T) {
+	tests := []struct {
+		name      string
+		server    string
+		wantIssue bool
+	}{
+		{name: "version disclosed", server: "Apache/2.2.3 (CentOS)", wantIssue: true},
+		{name: "no version", server: "nginx", wantIssue: false},
+		{name: "no header", server: "", wantIssue: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			if tt.server != "" {
+				header.Set("Server", tt.server)
+			}
+			result := Result{URL: "https://example.com/", Status: http.StatusOK, Header: header}
+			checkServerHeader(&result, true)
+
+			if result.ServerHeader != tt.server {
+				t.Errorf("ServerHeader = %q, want %q", result.ServerHeader, tt.server)
+			}
+			if got := len(result.Issues) > 0; got != tt.wantIssue {
+				t.Errorf("checkServerHeader() issues = %+v, want issue %v", result.Issues, tt.wantIssue)
+			}
+		})
+	}
+}
+
+// Test for checkXPoweredBy function
+func TestCheckXPoweredBy(t *testing.T) {
+	allowed := regexp.MustCompile(`^Next\.js`)
+
+	tests := []struct {
+		name      string
+		poweredBy string
+		allowed   *regexp.Regexp
+		wantIssue bool
+	}{
+		{name: "version disclosed", poweredBy: "PHP/7.2.1", wantIssue: true},
+		{name: "no version", poweredBy: "Express"},
+		{name: "allowed value", poweredBy: "Next.js 14.1", allowed: allowed},
+		{name: "not allowed value", poweredBy: "PHP/8.1", allowed: allowed, wantIssue: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Result{URL: "https://example.com/", Header: http.Header{"X-Powered-By": {tt.poweredBy}}}
+			checkXPoweredBy(&result, tt.allowed)
+
+			if result.XPoweredBy != tt.poweredBy {
+				t.Errorf("XPoweredBy = %q, want %q", result.XPoweredBy, tt.poweredBy)
+			}
+			if got := len(result.Issues) > 0; got != tt.wantIssue {
+				t.Errorf("checkXPoweredBy() issues = %+v, want issue %v", result.Issues, tt.wantIssue)
+			}
+		})
+	}
+}
+
+// Test for checkCacheHeaders function
+func TestCheckCacheHeaders(t *testing.T) {
+	future := time.Now().Add(24 * time.Hour).UTC().Format(http.TimeFormat)
+	past := time.Now().Add(-24 * time.Hour).UTC().Format(http.TimeFormat)
+
+	tests := []struct {
+		name      string
+		header    http.Header
+		wantIssue bool
+	}{
+		{name: "html page ignored", header: http.Header{"Content-Type": {"text/html"}}},
+		{name: "asset with max-age", header: http.Header{"Content-Type": {"text/css"}, "Cache-Control": {"public, max-age=31536000"}}},
+		{name: "asset with future expires", header: http.Header{"Content-Type": {"image/png"}, "Expires": {future}}},
+		{name: "asset without headers", header: http.Header{"Content-Type": {"application/javascript"}}, wantIssue: true},
+		{name: "asset with zero max-age", header: http.Header{"Content-Type": {"image/webp"}, "Cache-Control": {"max-age=0"}}, wantIssue: true},
+		{name: "asset with past expires", header: http.Header{"Content-Type": {"image/jpeg"}, "Expires": {past}}, wantIssue: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Result{URL: "https://example.com/asset", Status: http.StatusOK, Header: tt.header}
+			checkCacheHeaders(&result)
+			if got := len(result.Issues) > 0; got != tt.wantIssue {
+				t.Errorf("checkCacheHeaders() issues = %+v, want issue %v", result.Issues, tt.wantIssue)
+			}
+		})
+	}
+}
+
+// Test for checkHTMLMaxAge function
+func TestCheckHTMLMaxAge(t *testing.T) {
+	tests := []struct {
+		name       string
+		header     http.Header
+		wantMaxAge int
+		wantIssue  bool
+	}{
+		{name: "asset ignored", header: http.Header{"Content-Type": {"image/png"}, "Cache-Control": {"max-age=31536000"}}},
+		{name: "no max-age", header: http.Header{"Content-Type": {"text/html"}, "Cache-Control": {"no-cache"}}},
+		{name: "short max-age", header: http.Header{"Content-Type": {"text/html; charset=utf-8"}, "Cache-Control": {"public, max-age=600"}}, wantMaxAge: 600},
+		{name: "long max-age", header: http.Header{"Content-Type": {"text/html"}, "Cache-Control": {"max-age=86400"}}, wantMaxAge: 86400, wantIssue: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Result{URL: "https://example.com/", Status: http.StatusOK, Header: tt.header}
+			checkHTMLMaxAge(&result, 3600)
+			if result.MaxAge != tt.wantMaxAge {
+				t.Errorf("MaxAge = %d, want %d", result.MaxAge, tt.wantMaxAge)
+			}
+			if got := len(result.Issues) > 0; got != tt.wantIssue {
+				t.Errorf("checkHTMLMaxAge() issues = %+v, want issue %v", result.Issues, tt.wantIssue)
+			}
+		})
+	}
+}
+
+// Test for formatHTMLMaxAgeSummary function
+func TestFormatHTMLMaxAgeSummary(t *testing.T) {
+	html := func(cacheControl string) http.Header {
+		return http.Header{"Content-Type": {"text/html"}, "Cache-Control": {cacheControl}}
+	}
+	results := []Result{
+		{Header: html("max-age=600"), MaxAge: 600},
+		{Header: html("max-age=7200"), MaxAge: 7200, Issues: []Issue{{Code: "HTML_CACHE_TOO_LONG"}}},
+		{Header: html("no-store")},
+		{Error: errors.New("timeout")},
+	}
+	want := "HTML max-age: average 3900 seconds over 2 pages, 1 too long"
+	if got := formatHTMLMaxAgeSummary(results); got != want {
+		t.Errorf("formatHTMLMaxAgeSummary() = %q, want %q", got, want)
+	}
+}
+
+// Test for checkAssetNosniff function
+func TestCheckAssetNosniff(t *testing.T) {
+	tests := []struct {
+		name      string
+		header    http.Header
+		wantIssue bool
+	}{
+		{name: "html page ignored", header: http.Header{"Content-Type": {"text/html"}}},
+		{name: "script with nosniff", header: http.Header{"Content-Type": {"application/javascript"}, "X-Content-Type-Options": {"nosniff"}}},
+		{name: "stylesheet without nosniff", header: http.Header{"Content-Type": {"text/css; charset=utf-8"}}, wantIssue: true},
+		{name: "image with other value", header: http.Header{"Content-Type": {"image/svg+xml"}, "X-Content-Type-Options": {"sniff"}}, wantIssue: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Result{URL: "https://example.com/asset", Status: http.StatusOK, Header: tt.header}
+			checkAssetNosniff(&result)
+			if got := len(result.Issues) > 0; got != tt.wantIssue {
+				t.Errorf("checkAssetNosniff() issues = %+v, want issue %v", result.Issues, tt.wantIssue)
+			}
+		})
+	}
+}
+
+// Test for formatNosniffSummary function
+func TestFormatNosniffSummary(t *testing.T) {
+	missing := []Issue{{Code: "NOSNIFF_MISSING"}}
+	results := []Result{
+		{Header: http.Header{"Content-Type": {"image/png"}}, Issues: missing},
+		{Header: http.Header{"Content-Type": {"image/jpeg"}}, Issues: missing},
+		{Header: http.Header{"Content-Type": {"text/javascript"}}, Issues: missing},
+		{Header: http.Header{"Content-Type": {"text/css"}}},
+	}
+	want := "Assets without nosniff: image 2, script 1"
+	if got := formatNosniffSummary(results); got != want {
+		t.Errorf("formatNosniffSummary() = %q, want %q", got, want)
+	}
+}
+
+// Test for parseMaxAge function
+func TestParseMaxAge(t *testing.T) {
+	tests := []struct {
+		cacheControl string
+		want         int
+		wantOK       bool
+	}{
+		{cacheControl: "max-age=3600", want: 3600, wantOK: true},
+		{cacheControl: "public, s-maxage=60, max-age=120", want: 120, wantOK: true},
+		{cacheControl: `max-age="30"`, want: 30, wantOK: true},
+		{cacheControl: "no-store", wantOK: false},
+		{cacheControl: "", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		got, ok := parseMaxAge(tt.cacheControl)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("parseMaxAge(%q) = %d, %v, want %d, %v", tt.cacheControl, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+// Test for checkProtocol function
+func TestCheckProtocol(t *testing.T) {
+	for _, tt := range []struct {
+		protocol  string
+		wantIssue bool
+	}{
+		{protocol: "HTTP/1.0", wantIssue: true},
+		{protocol: "HTTP/1.1"},
+		{protocol: "HTTP/2.0"},
+	} {
+		result := Result{URL: "https://example.com/", Protocol: tt.protocol}
+		checkProtocol(&result)
+		if got := len(result.Issues) > 0; got != tt.wantIssue {
+			t.Errorf("checkProtocol(%q) issues = %+v, want issue %v", tt.protocol, result.Issues, tt.wantIssue)
+		}
+	}
+}
+
+// Test for checkXContentTypeOptions function
+func TestCheckXContentTypeOptions(t *testing.T) {
+	results := []Result{
+		{URL: "https://example.com/a", Status: http.StatusOK, Header: http.Header{"X-Content-Type-Options": {"nosniff"}}},
+		{URL: "https://example.com/b", Status: http.StatusOK, Header: http.Header{"X-Content-Type-Options": {"NoSniff "}}},
+		{URL: "https://example.com/c", Status: http.StatusOK, Header: http.Header{}},
+		{URL: "https://example.com/d", Status: http.StatusOK, Header: http.Header{"X-Content-Type-Options": {"sniff"}}},
+	}
+	for i := range results {
+		checkXContentTypeOptions(&results[i])
+	}
+
+	for i, wantIssue := range []bool{false, false, true, true} {
+		if got := len(results[i].Issues) > 0; got != wantIssue {
+			t.Errorf("checkXContentTypeOptions(%s) issues = %+v, want issue %v", results[i].URL, results[i].Issues, wantIssue)
+		}
+	}
+
+	want := "X-Content-Type-Options: nosniff on 2 URLs (50.0%), missing on 2 URLs (50.0%)"
+	if got := formatXCTOSummary(results); got != want {
+		t.Errorf("formatXCTOSummary() = %q, want %q", got, want)
+	}
+}
+
+// Test for checkCSP function
+func TestCheckCSP(t *testing.T) {
+	results := []Result{
+		{URL: "https://example.com/strong", Status: http.StatusOK, Header: http.Header{"Content-Security-Policy": {"default-src 'self'; script-src 'self' 'nonce-abc'"}}},
+		{URL: "https://example.com/weak", Status: http.StatusOK, Header: http.Header{"Content-Security-Policy": {"default-src 'self'; script-src 'self' 'Unsafe-Inline' 'unsafe-eval'"}}},
+		{URL: "https://example.com/missing", Status: http.StatusOK, Header: http.Header{}},
+	}
+	for i := range results {
+		checkCSP(&results[i])
+	}
+
+	for i, wantCode := range []string{"", "WEAK_CSP", "MISSING_CSP"} {
+		var got string
+		if len(results[i].Issues) > 0 {
+			got = results[i].Issues[0].Code
+		}
+		if got != wantCode {
+			t.Errorf("checkCSP(%s) issues = %+v, want %q", results[i].URL, results[i].Issues, wantCode)
+		}
+	}
+	if results[1].CSPHeader == "" {
+		t.Error("checkCSP() did not record the CSP header")
+	}
+
+	want := "Content-Security-Policy: 1 missing, 1 weak, 1 strong"
+	if got := formatCSPSummary(results); got != want {
+		t.Errorf("formatCSPSummary() = %q, want %q", got, want)
+	}
+}
+
+// Test for checkCompression function
+func TestCheckCompression(t *testing.T) {
+	results := []Result{
+		{URL: "https://example.com/gzip", Status: http.StatusOK, Header: http.Header{"Content-Type": {"text/html; charset=utf-8"}, "Content-Encoding": {"gzip"}}},
+		{URL: "https://example.com/br", Status: http.StatusOK, Header: http.Header{"Content-Type": {"text/css"}, "Content-Encoding": {"BR"}}},
+		{URL: "https://example.com/plain", Status: http.StatusOK, Header: http.Header{"Content-Type": {"text/plain"}}},
+		{URL: "https://example.com/deflate", Status: http.StatusOK, Header: http.Header{"Content-Type": {"text/html"}, "Content-Encoding": {"deflate"}}},
+		{URL: "https://example.com/image.png", Status: http.StatusOK, Header: http.Header{"Content-Type": {"image/png"}}},
+	}
+	for i := range results {
+		checkCompression(&results[i])
+	}
+
+	tests := []struct {
+		encoding string
+		code     string
+	}{
+		{"gzip", ""},
+		{"br", ""},
+		{"identity", "NO_COMPRESSION"},
+		{"deflate", "UNOFFERED_ENCODING"},
+		{"", ""},
+	}
+	for i, tt := range tests {
+		if results[i].CompressionEncoding != tt.encoding {
+			t.Errorf("checkCompression(%s) encoding = %q, want %q", results[i].URL, results[i].CompressionEncoding, tt.encoding)
+		}
+		var got string
+		if len(results[i].Issues) > 0 {
+			got = results[i].Issues[0].Code
+		}
+		if got != tt.code {
+			t.Errorf("checkCompression(%s) issues = %+v, want %q", results[i].URL, results[i].Issues, tt.code)
+		}
+	}
+
+	want := "Compression: 3 of 4 text responses compressed (75.0%)"
+	if got := formatCompressionSummary(results); got != want {
+		t.Errorf("formatCompressionSummary() = %q, want %q", got, want)
+	}
+}
+
+// Test for checkExpires function
+func TestCheckExpires(t *testing.T) {
+	tests := []struct {
+		name        string
+		header      http.Header
+		wantCode    string
+		wantExpires bool
+	}{
+		{"future", http.Header{"Expires": {"Thu, 01 Jan 2099 00:00:00 GMT"}}, "", true},
+		{"past", http.Header{"Expires": {"Mon, 01 Jan 2001 00:00:00 GMT"}}, "ALREADY_EXPIRED", true},
+		{"invalid", http.Header{"Expires": {"0"}}, "ALREADY_EXPIRED", false},
+		{"cache-control only", http.Header{"Cache-Control": {"max-age=600"}}, "", false},
+		{"neither", http.Header{}, "NO_EXPIRY_HEADERS", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Result{Status: 200, Header: tt.header}
+			checkExpires(&result)
+			if (result.Expires != nil) != tt.wantExpires {
+				t.Errorf("Expires = %v, want set: %v", result.Expires, tt.wantExpires)
+			}
+			if tt.wantCode == "" {
+				if len(result.Issues) != 0 {
+					t.Errorf("issues = %v, want none", result.Issues)
+				}
+			} else if len(result.Issues) != 1 || result.Issues[0].Code != tt.wantCode {
+				t.Errorf("issues = %v, want %s", result.Issues, tt.wantCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+//go:build headless
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/chromedp/chromedp"
+)
+
+// renderTimeout limits how long a page may take to load in the browser
+const renderTimeout = 30 * time.Second
+
+// Renderer loads pages in a shared headless Chrome instance, one tab per page
+type Renderer struct {
+	browser     context.Context
+	cancelAlloc context.CancelFunc
+	cancel      context.CancelFunc
+}
+
+// NewRenderer starts headless Chrome
+func NewRenderer() (*Renderer, error) {
+	allocCtx, cancelAlloc := chromedp.NewExecAllocator(context.Background(),
+		append(chromedp.DefaultExecAllocatorOptions[:], chromedp.UserAgent(userAgent))...)
+	browser, cancel := chromedp.NewContext(allocCtx)
+
+	// Running an empty task list launches the browser so start-up errors surface here
+	if err := chromedp.Run(browser); err != nil {
+		cancel()
+		cancelAlloc()
+		return nil, fmt.Errorf("failed to start headless Chrome: %w", err)
+	}
+	return &Renderer{browser: browser, cancelAlloc: cancelAlloc, cancel: cancel}, nil
+}
+
+// Measure loads a page and returns the time until its DOMContentLoaded and load
+// events, taken from the Navigation Timing API
+func (r *Renderer) Measure(pageURL string) (time.Duration, time.Duration, error) {
+	tab, cancelTab := chromedp.NewContext(r.browser)
+	defer cancelTab()
+	ctx, cancel := context.WithTimeout(tab, renderTimeout)
+	defer cancel()
+
+	var timing []float64
+	err := chromedp.Run(ctx,
+		chromedp.Navigate(pageURL),
+		chromedp.Evaluate(`(() => {
+			const nav = performance.getEntriesByType("navigation")[0];
+			return [nav.domContentLoadedEventEnd, nav.loadEventEnd];
+		})()`, &timing),
+	)
+	if err != nil {
+		return 0, 0, err
+	}
+	if len(timing) != 2 {
+		return 0, 0, fmt.Errorf("no navigation timing for %s", pageURL)
+	}
+
+	ms := func(v float64) time.Duration { return time.Duration(v * float64(time.Millisecond)) }
+	return ms(timing[0]), ms(timing[1]), nil
+}
+
+// Close shuts the browser down
+func (r *Renderer) Close() {
+	if r == nil {
+		return
+	}
+	r.cancel()
+	r.cancelAlloc()
+}
@@ -0,0 +1,24 @@
+//go:build !headless
+
+package main
+
+import (
+	"errors"
+	"time"
+)
+
+// Renderer stands in for the headless Chrome renderer left out of this build
+type Renderer struct{}
+
+// NewRenderer reports that browser rendering was left out of this build
+func NewRenderer() (*Renderer, error) {
+	return nil, errors.New("page rendering is not compiled in, rebuild with -tags headless")
+}
+
+// Measure is never called as NewRenderer always fails
+func (r *Renderer) Measure(pageURL string) (time.Duration, time.Duration, error) {
+	return 0, 0, errors.New("page rendering is not compiled in")
+}
+
+// Close does nothing
+func (r *Renderer) Close() {}
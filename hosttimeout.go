@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// HostTimeouts gives every host a deadline measured from its first request and
+// cancels the remaining requests to a host once the deadline has passed
+type HostTimeouts struct {
+	timeout time.Duration
+	hosts   sync.Map // host -> *hostDeadline
+}
+
+// hostDeadline holds the context shared by all requests to one host
+type hostDeadline struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// NewHostTimeouts creates per-host deadlines of the given duration
+func NewHostTimeouts(timeout time.Duration) *HostTimeouts {
+	return &HostTimeouts{timeout: timeout}
+}
+
+// Context returns the context for requests to host, starting its deadline on first use
+func (h *HostTimeouts) Context(host string) context.Context {
+	if deadline, ok := h.hosts.Load(host); ok {
+		return deadline.(*hostDeadline).ctx
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.timeout)
+	deadline, loaded := h.hosts.LoadOrStore(host, &hostDeadline{ctx: ctx, cancel: cancel})
+	if loaded {
+		// Another request to the same host got there first
+		cancel()
+	}
+	return deadline.(*hostDeadline).ctx
+}
+
+// Exceeded reports whether the deadline of host has passed
+func (h *HostTimeouts) Exceeded(host string) bool {
+	deadline, ok := h.hosts.Load(host)
+	return ok && deadline.(*hostDeadline).ctx.Err() != nil
+}
+
+// Error returns the error recorded for requests cancelled by the deadline of host
+func (h *HostTimeouts) Error(host string) error {
+	return fmt.Errorf("HOST_TIMEOUT: per-host timeout of %s exceeded for %s", h.timeout, host)
+}
+
+// Stop releases the resources of all host deadlines
+func (h *HostTimeouts) Stop() {
+	h.hosts.Range(func(_, deadline any) bool {
+		deadline.(*hostDeadline).cancel()
+		return true
+	})
+}
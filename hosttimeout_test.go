@@ -0,0 +1,35 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+// Test for HostTimeouts deadlines
+func TestHostTimeouts(t *testing.T) {
+	timeouts := NewHostTimeouts(50 * time.Millisecond)
+	defer timeouts.Stop()
+
+	slowCtx := timeouts.Context("slow.example.com")
+	if timeouts.Exceeded("slow.example.com") {
+		t.Fatalf("Exceeded() = true right after the first request")
+	}
+	if timeouts.Context("slow.example.com") != slowCtx {
+		t.Errorf("Context() returned a different context for the same host")
+	}
+
+	time.Sleep(60 * time.Millisecond)
+
+	// A host seen for the first time starts its own deadline
+	timeouts.Context("fast.example.com")
+
+	if !timeouts.Exceeded("slow.example.com") {
+		t.Errorf("Exceeded(slow) = false after the timeout elapsed")
+	}
+	if timeouts.Exceeded("fast.example.com") {
+		t.Errorf("Exceeded(fast) = true for a host first seen after the other host's deadline")
+	}
+	if timeouts.Exceeded("unknown.example.com") {
+		t.Errorf("Exceeded(unknown) = true for a host never requested")
+	}
+}
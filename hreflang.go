@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// hreflangTargetURLs returns the alternate language URLs found on checked pages that
+// are not in the sitemap themselves, each once, with the page that links to it as source
+func hreflangTargetURLs(results []Result) []URL {
+	seen := make(map[string]bool, len(results))
+	for _, result := range results {
+		seen[result.URL] = true
+	}
+
+	var targets []URL
+	for _, result := range results {
+		for _, target := range result.HreflangTargets {
+			if seen[target] {
+				continue
+			}
+			seen[target] = true
+			targets = append(targets, URL{Loc: target, Source: result.URL})
+		}
+	}
+	return targets
+}
+
+// markHreflangTargets tags the results of checked hreflang targets and flags the broken ones
+func markHreflangTargets(results []Result, logger *Logger) int {
+	broken := 0
+	for i := range results {
+		result := &results[i]
+		result.HreflangTarget = true
+		if !isBroken(result.Status, result.Error) {
+			continue
+		}
+
+		broken++
+		msg := fmt.Sprintf("hreflang target linked from %s", result.SourceSitemap)
+		if result.Error != nil {
+			msg += fmt.Sprintf(" failed: %v", result.Error)
+		} else {
+			msg += fmt.Sprintf(" returned %d", result.Status)
+		}
+		result.addIssue("HREFLANG_TARGET_BROKEN", msg)
+		if logger != nil {
+			logger.Log(fmt.Sprintf("HREFLANG_TARGET_BROKEN: %s - %s", result.URL, msg))
+		}
+	}
+	return broken
+}
+
+// checkHreflangReturnLinks cross-validates the hreflang links collected from all checked
+// pages: every sitemap page an alternate language link points to must link back. The
+// pages missing a return link are flagged and their count is returned.
+func checkHreflangReturnLinks(results []Result, logger *Logger) int {
+	index := make(map[string]int, len(results))
+	for i, result := range results {
+		if result.Error == nil && result.Status == 200 && !result.HreflangTarget {
+			index[urlKey(result.URL)] = i
+		}
+	}
+
+	// The second pass needs each page's alternates as a set
+	links := make(map[int]map[string]bool, len(index))
+	for _, i := range index {
+		links[i] = make(map[string]bool, len(results[i].HreflangTargets))
+		for _, target := range results[i].HreflangTargets {
+			links[i][urlKey(target)] = true
+		}
+	}
+
+	// A page j linking to page i expects i to link back to j
+	missing := make(map[int][]string)
+	for _, j := range sortedIndexes(index) {
+		for _, target := range results[j].HreflangTargets {
+			i, ok := index[urlKey(target)]
+			if !ok || i == j || links[i][urlKey(results[j].URL)] {
+				continue
+			}
+			missing[i] = append(missing[i], results[j].URL)
+		}
+	}
+
+	for _, i := range sortedIndexes(index) {
+		if len(missing[i]) == 0 {
+			continue
+		}
+		msg := fmt.Sprintf("no hreflang link back to %s", strings.Join(missing[i], ", "))
+		results[i].addIssue("HREFLANG_MISSING_RETURN", msg)
+		if logger != nil {
+			logger.Log(fmt.Sprintf("HREFLANG_MISSING_RETURN: %s - %s", results[i].URL, msg) + sourceTag(results[i].SourceSitemap))
+		}
+	}
+	return len(missing)
+}
+
+// sortedIndexes returns the values of an index map in ascending order
+func sortedIndexes(index map[string]int) []int {
+	indexes := make([]int, 0, len(index))
+	for _, i := range index {
+		indexes = append(indexes, i)
+	}
+	sort.Ints(indexes)
+	return indexes
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// Test for hreflangTargetURLs function
+func TestHreflangTargetURLs(t *testing.T) {
+	results := []Result{
+		{URL: "https://example.com/en/", HreflangTargets: []string{"https://example.com/en/", "https://example.com/de/", "https://example.com/fr/"}},
+		{URL: "https://example.com/de/", HreflangTargets: []string{"https://example.com/en/", "https://example.com/fr/", "https://example.com/es/"}},
+	}
+
+	got := hreflangTargetURLs(results)
+	want := []URL{
+		{Loc: "https://example.com/fr/", Source: "https://example.com/en/"},
+		{Loc: "https://example.com/es/", Source: "https://example.com/de/"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("hreflangTargetURLs() = %+v, want %+v", got, want)
+	}
+}
+
+// Test for markHreflangTargets function
+func TestMarkHreflangTargets(t *testing.T) {
+	results := []Result{
+		{URL: "https://example.com/fr/", Status: 200, SourceSitemap: "https://example.com/en/"},
+		{URL: "https://example.com/es/", Status: 404, SourceSitemap: "https://example.com/de/"},
+		{URL: "https://example.com/it/", Error: errors.New("timeout"), SourceSitemap: "https://example.com/de/"},
+	}
+
+	if got := markHreflangTargets(results, nil); got != 2 {
+		t.Errorf("markHreflangTargets() = %d, want 2", got)
+	}
+	for _, result := range results {
+		if !result.HreflangTarget {
+			t.Errorf("%s not marked as hreflang target", result.URL)
+		}
+	}
+	if countIssues(results, "HREFLANG_TARGET_BROKEN") != 2 || len(results[0].Issues) != 0 {
+		t.Errorf("HREFLANG_TARGET_BROKEN issues = %+v", results)
+	}
+}
+
+// Test for checkHreflangReturnLinks function
+func TestCheckHreflangReturnLinks(t *testing.T) {
+	en, de, fr := "https://example.com/en/", "https://example.com/de/", "https://example.com/fr/"
+	results := []Result{
+		{URL: en, Status: 200, HreflangTargets: []string{en, de, fr}},
+		{URL: de, Status: 200, HreflangTargets: []string{"https://EXAMPLE.com/en/", de, fr}},
+		{URL: fr, Status: 200, HreflangTargets: []string{fr}},
+		{URL: "https://example.com/es/", Status: 404},
+		{URL: "https://example.com/it/", Status: 200, HreflangTargets: []string{"https://example.com/es/", "https://example.com/external/"}},
+	}
+
+	if got := checkHreflangReturnLinks(results, nil); got != 1 {
+		t.Errorf("checkHreflangReturnLinks() = %d, want 1", got)
+	}
+	for i, result := range results {
+		want := 0
+		if result.URL == fr {
+			want = 1
+		}
+		if got := countIssues(results[i:i+1], "HREFLANG_MISSING_RETURN"); got != want {
+			t.Errorf("%s HREFLANG_MISSING_RETURN = %d, want %d (issues %+v)", result.URL, got, want, result.Issues)
+		}
+	}
+	if msg := results[2].Issues[0].Message; msg != "no hreflang link back to "+en+", "+de {
+		t.Errorf("message = %q", msg)
+	}
+}
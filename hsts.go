@@ -0,0 +1,133 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// hstsPreloadMinAge is the minimum max-age in seconds hstspreload.org accepts
+const hstsPreloadMinAge = 31536000
+
+// HSTSPolicy is a parsed Strict-Transport-Security header
+type HSTSPolicy struct {
+	MaxAge            int64
+	IncludeSubDomains bool
+	Preload           bool
+}
+
+// parseHSTS parses a Strict-Transport-Security header value. It reports false when
+// the header has no valid max-age directive, which browsers ignore.
+func parseHSTS(value string) (HSTSPolicy, bool) {
+	var policy HSTSPolicy
+	hasMaxAge := false
+	for _, directive := range strings.Split(value, ";") {
+		name, arg, _ := strings.Cut(strings.TrimSpace(directive), "=")
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "max-age":
+			maxAge, err := strconv.ParseInt(strings.Trim(strings.TrimSpace(arg), `"`), 10, 64)
+			if err != nil {
+				return policy, false
+			}
+			policy.MaxAge = maxAge
+			hasMaxAge = true
+		case "includesubdomains":
+			policy.IncludeSubDomains = true
+		case "preload":
+			policy.Preload = true
+		}
+	}
+	return policy, hasMaxAge
+}
+
+// hstsProblems returns why a Strict-Transport-Security header fails the HSTS check
+// and, with preload set, the requirements of the browser preload lists
+func hstsProblems(value string, preload bool) (string, []string) {
+	policy, ok := parseHSTS(value)
+	if value == "" || !ok || policy.MaxAge <= 0 {
+		if value == "" {
+			return "MISSING_HSTS", []string{"no Strict-Transport-Security header"}
+		}
+		return "MISSING_HSTS", []string{fmt.Sprintf("Strict-Transport-Security %q has no positive max-age", value)}
+	}
+	if !preload {
+		return "", nil
+	}
+
+	var problems []string
+	if policy.MaxAge < hstsPreloadMinAge {
+		problems = append(problems, fmt.Sprintf("max-age %d is below %d", policy.MaxAge, hstsPreloadMinAge))
+	}
+	if !policy.IncludeSubDomains {
+		problems = append(problems, "includeSubDomains missing")
+	}
+	if !policy.Preload {
+		problems = append(problems, "preload missing")
+	}
+	if len(problems) > 0 {
+		return "HSTS_NOT_PRELOAD_ELIGIBLE", problems
+	}
+	return "", nil
+}
+
+// uniqueHosts returns the unique hosts of a list of URLs, sorted
+func uniqueHosts(urls []string) []string {
+	seen := make(map[string]bool)
+	for _, u := range urls {
+		parsedURL, err := url.Parse(u)
+		if err != nil || parsedURL.Host == "" {
+			continue
+		}
+		seen[strings.ToLower(parsedURL.Host)] = true
+	}
+	return sortedKeys(seen)
+}
+
+// fetchHSTS requests the https root of a host and returns its Strict-Transport-Security header
+func fetchHSTS(client *http.Client, host string) (string, error) {
+	req, err := http.NewRequest("HEAD", "https://"+host+"/", nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("User-Agent", userAgent)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	resp.Body.Close()
+	return resp.Header.Get("Strict-Transport-Security"), nil
+}
+
+// checkHSTSDomains checks the HSTS header of the root URL of every host in the URL list,
+// as HSTS and preload eligibility apply to a whole domain, and returns one summary line per host
+func checkHSTSDomains(client *http.Client, urls []string, preload bool, logger *Logger) []string {
+	var summary []string
+	for _, host := range uniqueHosts(urls) {
+		value, err := fetchHSTS(client, host)
+
+		var msg, status string
+		if err != nil {
+			status = "ERROR"
+			msg = fmt.Sprintf("HSTS ERROR: %s - %v", host, err)
+		} else if code, problems := hstsProblems(value, preload); code != "" {
+			status = code
+			msg = fmt.Sprintf("%s: %s - %s", code, host, strings.Join(problems, "; "))
+		} else if preload {
+			status = "preload eligible"
+		} else {
+			status = "OK"
+		}
+
+		if msg != "" {
+			fmt.Println(msg)
+			if logger != nil {
+				logger.Log(msg)
+			}
+		}
+		summary = append(summary, fmt.Sprintf("HSTS %s: %s", host, status))
+	}
+	return summary
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// Test for hstsProblems function
+func TestHSTSProblems(t *testing.T) {
+	tests := []struct {
+		name         string
+		value        string
+		preload      bool
+		wantCode     string
+		wantProblems int
+	}{
+		{name: "missing", value: "", wantCode: "MISSING_HSTS", wantProblems: 1},
+		{name: "zero max-age", value: "max-age=0", wantCode: "MISSING_HSTS", wantProblems: 1},
+		{name: "invalid max-age", value: "max-age=forever", wantCode: "MISSING_HSTS", wantProblems: 1},
+		{name: "basic policy", value: "max-age=86400"},
+		{name: "basic policy for preload", value: "max-age=86400", preload: true, wantCode: "HSTS_NOT_PRELOAD_ELIGIBLE", wantProblems: 3},
+		{name: "missing preload directive", value: "max-age=63072000; includeSubDomains", preload: true, wantCode: "HSTS_NOT_PRELOAD_ELIGIBLE", wantProblems: 1},
+		{name: "eligible", value: `max-age="63072000"; includeSubDomains; preload`, preload: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, problems := hstsProblems(tt.value, tt.preload)
+			if code != tt.wantCode || len(problems) != tt.wantProblems {
+				t.Errorf("hstsProblems(%q) = %q, %v, want %q with %d problems", tt.value, code, problems, tt.wantCode, tt.wantProblems)
+			}
+		})
+	}
+}
+
+// Test for checkHSTSDomains function
+func TestCheckHSTSDomains(t *testing.T) {
+	var paths []string
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		w.Header().Set("Strict-Transport-Security", "max-age=31536000; includeSubDomains")
+	}))
+	defer server.Close()
+
+	host := strings.TrimPrefix(server.URL, "https://")
+	urls := []string{server.URL + "/a", server.URL + "/b/c", "http://" + host + "/d"}
+
+	summary := checkHSTSDomains(server.Client(), urls, true, nil)
+
+	want := []string{"HSTS " + host + ": HSTS_NOT_PRELOAD_ELIGIBLE"}
+	if !equalStringSlices(summary, want) {
+		t.Errorf("checkHSTSDomains() = %v, want %v", summary, want)
+	}
+	if !equalStringSlices(paths, []string{"/"}) {
+		t.Errorf("requested paths = %v, want only the root", paths)
+	}
+}
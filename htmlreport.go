@@ -0,0 +1,233 @@
+package main
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Heatmap layout in pixels
+const (
+	heatmapCellWidth  = 180
+	heatmapCellHeight = 48
+	heatmapLabelWidth = 80
+)
+
+// HeatmapCell is a URL prefix in the response time heatmap
+type HeatmapCell struct {
+	Prefix string
+	Depth  int
+	Count  int
+	Avg    time.Duration
+	X, Y   int
+}
+
+// Color returns the cell colour for its average response time
+func (c HeatmapCell) Color() string {
+	switch {
+	case c.Avg < 500*time.Millisecond:
+		return "#4caf50"
+	case c.Avg <= 2*time.Second:
+		return "#ffc107"
+	default:
+		return "#f44336"
+	}
+}
+
+// Heatmap is the response time heatmap of an HTML report, one row per path depth
+type Heatmap struct {
+	Cells  []HeatmapCell
+	Rows   []int
+	Width  int
+	Height int
+}
+
+// pathPrefix returns the first depth path segments of a URL and how many there are
+func pathPrefix(rawURL string, depth int) (string, int) {
+	parsedURL, err := url.Parse(rawURL)
+	if err != nil {
+		return "/", 0
+	}
+	var segments []string
+	for _, segment := range strings.Split(parsedURL.Path, "/") {
+		if segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+	if len(segments) > depth {
+		segments = segments[:depth]
+	}
+	return "/" + strings.Join(segments, "/"), len(segments)
+}
+
+// buildHeatmap averages the response times of the checked URLs per prefix and lays
+// the prefixes out in rows by path depth
+func buildHeatmap(results []Result) Heatmap {
+	type total struct {
+		depth int
+		count int
+		sum   time.Duration
+	}
+	totals := make(map[string]*total)
+	for _, result := range results {
+		if result.Error != nil || result.ResponseTime == 0 {
+			continue
+		}
+		prefix, depth := pathPrefix(result.URL, 2)
+		t, ok := totals[prefix]
+		if !ok {
+			t = &total{depth: depth}
+			totals[prefix] = t
+		}
+		t.count++
+		t.sum += result.ResponseTime
+	}
+
+	var heatmap Heatmap
+	columns := make(map[int]int)
+	for _, prefix := range sortedKeys(totals) {
+		t := totals[prefix]
+		heatmap.Cells = append(heatmap.Cells, HeatmapCell{
+			Prefix: prefix,
+			Depth:  t.depth,
+			Count:  t.count,
+			Avg:    t.sum / time.Duration(t.count),
+		})
+		columns[t.depth]++
+	}
+	sort.SliceStable(heatmap.Cells, func(i, j int) bool { return heatmap.Cells[i].Depth < heatmap.Cells[j].Depth })
+
+	rowIndex := make(map[int]int)
+	for depth := 0; depth <= 2; depth++ {
+		if columns[depth] > 0 {
+			rowIndex[depth] = len(heatmap.Rows)
+			heatmap.Rows = append(heatmap.Rows, depth)
+			heatmap.Width = max(heatmap.Width, heatmapLabelWidth+columns[depth]*heatmapCellWidth)
+		}
+	}
+	heatmap.Height = len(heatmap.Rows) * heatmapCellHeight
+
+	placed := make(map[int]int)
+	for i := range heatmap.Cells {
+		cell := &heatmap.Cells[i]
+		cell.X = heatmapLabelWidth + placed[cell.Depth]*heatmapCellWidth
+		cell.Y = rowIndex[cell.Depth] * heatmapCellHeight
+		placed[cell.Depth]++
+	}
+	return heatmap
+}
+
+// htmlReport holds the data rendered into the HTML report
+type htmlReport struct {
+	RunID       string
+	SitemapURL  string
+	GeneratedAt time.Time
+	Summary     []string
+	Heatmap     Heatmap
+	Results     []Result
+	Domains     []DomainCount
+}
+
+var htmlReportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"ms":     func(d time.Duration) string { return fmt.Sprintf("%d ms", d.Milliseconds()) },
+	"rowY":   func(i int) int { return i*heatmapCellHeight + heatmapCellHeight/2 + 4 },
+	"add":    func(a, b int) int { return a + b },
+	"width":  func() int { return heatmapCellWidth - 4 },
+	"height": func() int { return heatmapCellHeight - 4 },
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Sitemap check: {{.SitemapURL}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; }
+td, th { border: 1px solid #ccc; padding: 4px 8px; text-align: left; }
+svg text { font-size: 12px; }
+</style>
+</head>
+<body>
+<h1>Sitemap check: {{.SitemapURL}}</h1>
+<p>Run ID: {{.RunID}}<br>Generated at: {{.GeneratedAt.Format "2006-01-02 15:04:05 MST"}}</p>
+
+<h2>Summary</h2>
+<ul>
+{{- range .Summary}}
+<li>{{.}}</li>
+{{- end}}
+</ul>
+
+<h2>Response time heatmap</h2>
+<p>Average response time per URL prefix: green below 500 ms, yellow up to 2 s, red above 2 s.</p>
+<svg xmlns="http://www.w3.org/2000/svg" width="{{.Heatmap.Width}}" height="{{.Heatmap.Height}}">
+{{- range $i, $depth := .Heatmap.Rows}}
+<text x="0" y="{{rowY $i}}">depth {{$depth}}</text>
+{{- end}}
+{{- range .Heatmap.Cells}}
+<g>
+<title>{{.Prefix}}: {{ms .Avg}} average over {{.Count}} URLs</title>
+<rect x="{{.X}}" y="{{.Y}}" width="{{width}}" height="{{height}}" fill="{{.Color}}"></rect>
+<text x="{{add .X 6}}" y="{{add .Y 18}}">{{.Prefix}}</text>
+<text x="{{add .X 6}}" y="{{add .Y 34}}">{{ms .Avg}} ({{.Count}})</text>
+</g>
+{{- end}}
+</svg>
+
+{{- if .Domains}}
+<h2>Domains</h2>
+<table>
+<tr><th>Host</th><th>URLs</th></tr>
+{{- range .Domains}}
+<tr><td>{{.Host}}</td><td>{{.URLs}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+
+<h2>Results</h2>
+<table>
+<tr><th>URL</th><th>Source sitemap</th><th>Status</th><th>Response time</th><th>Issues</th></tr>
+{{- range .Results}}
+<tr>
+<td>{{.URL}}</td>
+<td>{{.SourceSitemap}}</td>
+<td>{{if .Error}}{{.Error}}{{else}}{{.Status}}{{if .IsRedirect}} &rarr; {{.RedirectURL}}{{end}}{{end}}</td>
+<td>{{ms .ResponseTime}}</td>
+<td>{{range .Issues}}{{.Code}}: {{.Message}}<br>{{end}}</td>
+</tr>
+{{- end}}
+</table>
+</body>
+</html>
+`))
+
+// writeHTMLReport renders the results, summary and response time heatmap as an HTML page,
+// along with the URL count per hostname when domains is not empty
+func writeHTMLReport(w io.Writer, runID, sitemapURL string, summary []string, results []Result, domains []DomainCount) error {
+	return htmlReportTemplate.Execute(w, htmlReport{
+		RunID:       runID,
+		SitemapURL:  sitemapURL,
+		GeneratedAt: time.Now().UTC(),
+		Summary:     summary,
+		Heatmap:     buildHeatmap(results),
+		Results:     results,
+		Domains:     domains,
+	})
+}
+
+// saveHTMLReport writes the HTML report to path
+func saveHTMLReport(path, runID, sitemapURL string, summary []string, results []Result, domains []DomainCount) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create HTML report: %w", err)
+	}
+	err = writeHTMLReport(file, runID, sitemapURL, summary, results, domains)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
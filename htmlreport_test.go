@@ -0,0 +1,84 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Test for pathPrefix function
+func TestPathPrefix(t *testing.T) {
+	tests := []struct {
+		url       string
+		want      string
+		wantDepth int
+	}{
+		{url: "https://example.com/", want: "/", wantDepth: 0},
+		{url: "https://example.com/blog", want: "/blog", wantDepth: 1},
+		{url: "https://example.com/blog/2024/post", want: "/blog/2024", wantDepth: 2},
+		{url: "https://example.com/shop/shoes/", want: "/shop/shoes", wantDepth: 2},
+	}
+
+	for _, tt := range tests {
+		if got, depth := pathPrefix(tt.url, 2); got != tt.want || depth != tt.wantDepth {
+			t.Errorf("pathPrefix(%q, 2) = %q, %d, want %q, %d", tt.url, got, depth, tt.want, tt.wantDepth)
+		}
+	}
+}
+
+// Test for buildHeatmap function
+func TestBuildHeatmap(t *testing.T) {
+	results := []Result{
+		{URL: "https://example.com/", ResponseTime: 100 * time.Millisecond},
+		{URL: "https://example.com/blog/a/1", ResponseTime: 1 * time.Second},
+		{URL: "https://example.com/blog/a/2", ResponseTime: 2 * time.Second},
+		{URL: "https://example.com/shop/b", ResponseTime: 3 * time.Second},
+		{URL: "https://example.com/broken", Error: errors.New("timeout")},
+	}
+
+	heatmap := buildHeatmap(results)
+
+	want := []struct {
+		prefix string
+		avg    time.Duration
+		color  string
+		x, y   int
+	}{
+		{prefix: "/", avg: 100 * time.Millisecond, color: "#4caf50", x: heatmapLabelWidth, y: 0},
+		{prefix: "/blog/a", avg: 1500 * time.Millisecond, color: "#ffc107", x: heatmapLabelWidth, y: heatmapCellHeight},
+		{prefix: "/shop/b", avg: 3 * time.Second, color: "#f44336", x: heatmapLabelWidth + heatmapCellWidth, y: heatmapCellHeight},
+	}
+	if len(heatmap.Cells) != len(want) {
+		t.Fatalf("got %d cells, want %d: %+v", len(heatmap.Cells), len(want), heatmap.Cells)
+	}
+	for i, w := range want {
+		cell := heatmap.Cells[i]
+		if cell.Prefix != w.prefix || cell.Avg != w.avg || cell.Color() != w.color || cell.X != w.x || cell.Y != w.y {
+			t.Errorf("cell %d = %+v (color %s), want %+v", i, cell, cell.Color(), w)
+		}
+	}
+	if heatmap.Width != heatmapLabelWidth+2*heatmapCellWidth || heatmap.Height != 2*heatmapCellHeight {
+		t.Errorf("heatmap size = %dx%d", heatmap.Width, heatmap.Height)
+	}
+}
+
+// Test for writeHTMLReport function
+func TestWriteHTMLReport(t *testing.T) {
+	results := []Result{
+		{URL: "https://example.com/<script>", Status: 404, ResponseTime: 50 * time.Millisecond, SourceSitemap: "https://example.com/sitemap-pages.xml"},
+	}
+	var buf bytes.Buffer
+	if err := writeHTMLReport(&buf, "abc123", "https://example.com/sitemap.xml", []string{"Broken URLs: 1"}, results,
+		[]DomainCount{{Host: "cdn.example.net", URLs: 3}}); err != nil {
+		t.Fatalf("writeHTMLReport() error = %v", err)
+	}
+
+	out := buf.String()
+	for _, want := range []string{"<svg", "<rect", "#4caf50", "Broken URLs: 1", "abc123", "&lt;script&gt;", "cdn.example.net", "<th>Source sitemap</th>", "<td>https://example.com/sitemap-pages.xml</td>"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("report does not contain %q", want)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+)
+
+// maxLargestPages is how many of the largest HTML pages the summary lists
+const maxLargestPages = 10
+
+// measureHTMLSize downloads a page and returns the number of bytes in its body,
+// without the bodyReadLimit the content checks apply
+func measureHTMLSize(client *http.Client, pageURL string) (int64, error) {
+	req, err := http.NewRequest("GET", pageURL, nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("User-Agent", userAgent)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	size, err := io.Copy(io.Discard, resp.Body)
+	if err != nil {
+		return size, fmt.Errorf("failed to read body: %w", err)
+	}
+	return size, nil
+}
+
+// checkHTMLSize records the size of an HTML page and flags it when it exceeds maxBytes
+func checkHTMLSize(client *http.Client, result *Result, maxBytes int64) error {
+	if !isHTML(result.Header.Get("Content-Type")) {
+		return nil
+	}
+
+	size, err := measureHTMLSize(client, result.URL)
+	if err != nil {
+		return err
+	}
+	result.HTMLSize = size
+	if size > maxBytes {
+		result.addIssue("HTML_TOO_LARGE", fmt.Sprintf("HTML is %d bytes, limit is %d", size, maxBytes))
+	}
+	return nil
+}
+
+// formatHTMLSizeSummary reports the average and largest HTML size and lists the largest pages
+func formatHTMLSizeSummary(results []Result) []string {
+	var pages []Result
+	var total int64
+	for _, result := range results {
+		if result.HTMLSize > 0 {
+			pages = append(pages, result)
+			total += result.HTMLSize
+		}
+	}
+	if len(pages) == 0 {
+		return []string{"HTML size: no pages measured"}
+	}
+
+	sort.SliceStable(pages, func(i, j int) bool { return pages[i].HTMLSize > pages[j].HTMLSize })
+	lines := []string{fmt.Sprintf("HTML size: average %d bytes, largest %d bytes, %d pages too large",
+		total/int64(len(pages)), pages[0].HTMLSize, countIssues(results, "HTML_TOO_LARGE"))}
+	for _, page := range pages[:min(len(pages), maxLargestPages)] {
+		lines = append(lines, fmt.Sprintf("  %d bytes: %s", page.HTMLSize, page.URL))
+	}
+	return lines
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// Test for checkHTMLSize function
+func TestCheckHTMLSize(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/large":
+			fmt.Fprint(w, strings.Repeat("x", 2048))
+		default:
+			fmt.Fprint(w, "<html></html>")
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name        string
+		path        string
+		contentType string
+		wantSize    int64
+		wantIssue   bool
+	}{
+		{name: "small page", path: "/small", contentType: "text/html", wantSize: 13},
+		{name: "large page", path: "/large", contentType: "text/html; charset=utf-8", wantSize: 2048, wantIssue: true},
+		{name: "not html", path: "/large", contentType: "application/pdf"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Result{URL: server.URL + tt.path, Status: http.StatusOK, Header: http.Header{"Content-Type": {tt.contentType}}}
+			if err := checkHTMLSize(server.Client(), &result, 1024); err != nil {
+				t.Fatalf("checkHTMLSize() error = %v", err)
+			}
+			if result.HTMLSize != tt.wantSize {
+				t.Errorf("HTMLSize = %d, want %d", result.HTMLSize, tt.wantSize)
+			}
+			if got := len(result.Issues) > 0; got != tt.wantIssue {
+				t.Errorf("issues = %v, want issue %v", result.Issues, tt.wantIssue)
+			}
+		})
+	}
+}
+
+// Test for formatHTMLSizeSummary function
+func TestFormatHTMLSizeSummary(t *testing.T) {
+	var results []Result
+	for i := 1; i <= 12; i++ {
+		results = append(results, Result{URL: fmt.Sprintf("https://example.com/%d", i), HTMLSize: int64(i * 100)})
+	}
+	results = append(results, Result{URL: "https://example.com/pdf"})
+
+	lines := formatHTMLSizeSummary(results)
+	if len(lines) != 1+maxLargestPages {
+		t.Fatalf("formatHTMLSizeSummary() returned %d lines, want %d", len(lines), 1+maxLargestPages)
+	}
+	if lines[0] != "HTML size: average 650 bytes, largest 1200 bytes, 0 pages too large" {
+		t.Errorf("summary line = %q", lines[0])
+	}
+	if lines[1] != "  1200 bytes: https://example.com/12" {
+		t.Errorf("largest page line = %q", lines[1])
+	}
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// checkHTTPToHTTPS classifies how an http:// URL redirects to its https:// equivalent.
+// It returns an empty code when the URL correctly answers with a 301 to the https URL.
+func checkHTTPToHTTPS(rawURL string, status int, location string) (string, string) {
+	parsedURL, err := url.Parse(rawURL)
+	if err != nil || parsedURL.Scheme != "http" {
+		return "", ""
+	}
+
+	expected := *parsedURL
+	expected.Scheme = "https"
+
+	if status < 300 || status >= 400 || location == "" {
+		return "HTTP_NO_HTTPS_REDIRECT", fmt.Sprintf("expected 301 to %s, got status %d", expected.String(), status)
+	}
+
+	target := resolveURL(rawURL, location)
+	if target != expected.String() {
+		return "HTTP_REDIRECT_WRONG_TARGET", fmt.Sprintf("redirects to %s instead of %s", target, expected.String())
+	}
+
+	if status != http.StatusMovedPermanently {
+		return "HTTP_REDIRECT_NOT_PERMANENT", fmt.Sprintf("redirects to %s with status %d instead of 301", target, status)
+	}
+
+	return "", ""
+}
+
+// headNoFollow sends a HEAD request and returns the status and Location header without following redirects
+func headNoFollow(client *http.Client, target string) (int, string, error) {
+	req, err := http.NewRequest("HEAD", target, nil)
+	if err != nil {
+		return 0, "", err
+	}
+	req.Header.Set("User-Agent", userAgent)
+
+	noFollow := *client
+	noFollow.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+
+	resp, err := noFollow.Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	resp.Body.Close()
+	return resp.StatusCode, resp.Header.Get("Location"), nil
+}
+
+// checkHTTPSRedirects verifies the checked URL, and any http:// redirect target, 301 to https
+func checkHTTPSRedirects(client *http.Client, result *Result) {
+	if strings.HasPrefix(result.URL, "http://") {
+		if code, msg := checkHTTPToHTTPS(result.URL, result.Status, result.RedirectURL); code != "" {
+			result.addIssue(code, msg)
+		}
+	}
+
+	// A redirect target on plain http must itself redirect to https
+	target := resolveURL(result.URL, result.RedirectURL)
+	if !result.IsRedirect || !strings.HasPrefix(target, "http://") || target == result.URL {
+		return
+	}
+
+	status, location, err := headNoFollow(client, target)
+	if err != nil {
+		result.addIssue("HTTP_NO_HTTPS_REDIRECT", fmt.Sprintf("redirect target %s: %v", target, err))
+		return
+	}
+	if code, msg := checkHTTPToHTTPS(target, status, location); code != "" {
+		result.addIssue(code, fmt.Sprintf("redirect target %s %s", target, msg))
+	}
+}
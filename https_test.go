@@ -0,0 +1,30 @@
+package main
+
+import "testing"
+
+// Test for checkHTTPToHTTPS function
+func TestCheckHTTPToHTTPS(t *testing.T) {
+	tests := []struct {
+		name     string
+		url      string
+		status   int
+		location string
+		wantCode string
+	}{
+		{name: "permanent redirect", url: "http://example.com/page", status: 301, location: "https://example.com/page"},
+		{name: "https url ignored", url: "https://example.com/page", status: 200},
+		{name: "no redirect", url: "http://example.com/page", status: 200, wantCode: "HTTP_NO_HTTPS_REDIRECT"},
+		{name: "temporary redirect", url: "http://example.com/page", status: 302, location: "https://example.com/page", wantCode: "HTTP_REDIRECT_NOT_PERMANENT"},
+		{name: "different path", url: "http://example.com/page", status: 301, location: "https://example.com/", wantCode: "HTTP_REDIRECT_WRONG_TARGET"},
+		{name: "stays on http", url: "http://example.com/page", status: 301, location: "/other", wantCode: "HTTP_REDIRECT_WRONG_TARGET"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, _ := checkHTTPToHTTPS(tt.url, tt.status, tt.location)
+			if code != tt.wantCode {
+				t.Errorf("checkHTTPToHTTPS() code = %q, want %q", code, tt.wantCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// maxHTTPVersionSamples limits how many assets of a page are checked for their HTTP version
+const maxHTTPVersionSamples = 10
+
+// assetURLs returns the stylesheets, scripts and images of the page, each once
+func (p *Page) assetURLs() []string {
+	seen := make(map[string]bool)
+	var assets []string
+	add := func(asset string) {
+		if !seen[asset] {
+			seen[asset] = true
+			assets = append(assets, asset)
+		}
+	}
+	for _, asset := range p.stylesheetURLs() {
+		add(asset)
+	}
+	for _, asset := range p.scriptURLs() {
+		add(asset)
+	}
+	walkHTML(p.Doc, func(n *html.Node) {
+		if n.Data != "img" {
+			return
+		}
+		if src, ok := attr(n, "src"); ok && strings.TrimSpace(src) != "" && !strings.HasPrefix(strings.TrimSpace(src), "data:") {
+			add(resolveURL(p.URL, src))
+		}
+	})
+	return assets
+}
+
+// headProtocol requests a URL with HEAD and returns the response, with its body
+// already closed, so the protocol it was served over can be read
+func headProtocol(client *http.Client, target string) (*http.Response, error) {
+	req, err := http.NewRequest("HEAD", target, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", userAgent)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+	return resp, nil
+}
+
+// checkHTTPVersionConsistency flags assets of an HTTP/2 page that are served over
+// HTTP/1.x, which keeps them from being multiplexed on the page's connection
+func checkHTTPVersionConsistency(client *http.Client, result *Result, page *Page) {
+	assets := page.assetURLs()
+	if len(assets) > maxHTTPVersionSamples {
+		assets = assets[:maxHTTPVersionSamples]
+	}
+
+	for _, asset := range assets {
+		resp, err := headProtocol(client, asset)
+		if err != nil || resp.ProtoMajor >= 2 {
+			continue
+		}
+		msg := fmt.Sprintf("%s served over %s", asset, resp.Proto)
+		result.HTTPVersionIssues = append(result.HTTPVersionIssues, msg)
+		result.addIssue("HTTP_VERSION_MISMATCH", msg)
+	}
+}
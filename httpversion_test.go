@@ -0,0 +1,43 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+// Test for checkHTTPVersionConsistency function
+func TestCheckHTTPVersionConsistency(t *testing.T) {
+	h2 := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	h2.EnableHTTP2 = true
+	h2.StartTLS()
+	defer h2.Close()
+
+	h1 := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer h1.Close()
+
+	page := parseTestPage(t, h2.URL+"/", fmt.Sprintf(`<html><head>
+		<link rel="stylesheet" href="/style.css">
+		<script src="%s/app.js"></script>
+		</head><body>
+		<img src="/logo.png"><img src="data:image/gif;base64,R0lGOD"><img src="%s/photo.jpg">
+		</body></html>`, h1.URL, h1.URL))
+
+	wantAssets := []string{h2.URL + "/style.css", h1.URL + "/app.js", h2.URL + "/logo.png", h1.URL + "/photo.jpg"}
+	if got := page.assetURLs(); !reflect.DeepEqual(got, wantAssets) {
+		t.Errorf("assetURLs() = %v, want %v", got, wantAssets)
+	}
+
+	result := Result{URL: page.URL, Status: 200, Protocol: "HTTP/2.0"}
+	checkHTTPVersionConsistency(h2.Client(), &result, page)
+
+	want := []string{h1.URL + "/app.js served over HTTP/1.1", h1.URL + "/photo.jpg served over HTTP/1.1"}
+	if !reflect.DeepEqual(result.HTTPVersionIssues, want) {
+		t.Errorf("HTTPVersionIssues = %v, want %v", result.HTTPVersionIssues, want)
+	}
+	if got := countIssues([]Result{result}, "HTTP_VERSION_MISMATCH"); got != 1 {
+		t.Errorf("HTTP_VERSION_MISMATCH count = %d, want 1", got)
+	}
+}
@@ -0,0 +1,23 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// checkImages HEAD-checks the image:loc URLs listed for a page in an image sitemap
+func checkImages(client *http.Client, result *Result) {
+	for _, image := range result.Images {
+		status, err := headCheck(client, image)
+		if !isBroken(status, err) {
+			continue
+		}
+
+		msg := fmt.Sprintf("%s (Status: %d)", image, status)
+		if err != nil {
+			msg = fmt.Sprintf("%s: %v", image, err)
+		}
+		result.BrokenImages = append(result.BrokenImages, image)
+		result.addIssue("IMAGE_BROKEN", msg)
+	}
+}
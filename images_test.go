@@ -0,0 +1,34 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Test for checkImages function
+func TestCheckImages(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing.jpg" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "image/jpeg")
+	}))
+	defer server.Close()
+
+	result := Result{
+		URL:    server.URL + "/product",
+		Status: http.StatusOK,
+		Images: []string{server.URL + "/image.jpg", server.URL + "/missing.jpg"},
+	}
+	checkImages(server.Client(), &result)
+
+	want := []string{server.URL + "/missing.jpg"}
+	if !equalStringSlices(result.BrokenImages, want) {
+		t.Errorf("BrokenImages = %v, want %v", result.BrokenImages, want)
+	}
+	if len(result.Issues) != 1 || result.Issues[0].Code != "IMAGE_BROKEN" {
+		t.Errorf("Issues = %+v, want one IMAGE_BROKEN issue", result.Issues)
+	}
+}
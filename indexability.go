@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// hasNoindex reports whether a robots directive value contains noindex or none
+func hasNoindex(directives string) bool {
+	for _, directive := range strings.Split(strings.ToLower(directives), ",") {
+		// X-Robots-Tag may prefix directives with a user agent, e.g. "googlebot: noindex"
+		if _, value, ok := strings.Cut(directive, ":"); ok {
+			directive = value
+		}
+		if d := strings.TrimSpace(directive); d == "noindex" || d == "none" {
+			return true
+		}
+	}
+	return false
+}
+
+// checkNoindex flags pages excluded from indexing by an X-Robots-Tag header or a meta robots tag
+func checkNoindex(result *Result, page *Page) {
+	for _, value := range page.Header.Values("X-Robots-Tag") {
+		if hasNoindex(value) {
+			result.Noindex = true
+			result.addIssue("NOINDEX", "X-Robots-Tag: "+value)
+			return
+		}
+	}
+	if robots, ok := page.metaContent("name", "robots"); ok && hasNoindex(robots) {
+		result.Noindex = true
+		result.addIssue("NOINDEX", fmt.Sprintf(`<meta name="robots" content="%s">`, robots))
+	}
+}
+
+// urlKey normalizes an absolute URL for comparison, ignoring the case of the scheme
+// and host, the fragment and an empty path
+func urlKey(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	u.Scheme, u.Host, u.Fragment = strings.ToLower(u.Scheme), strings.ToLower(u.Host), ""
+	if u.Path == "" {
+		u.Path = "/"
+	}
+	return u.String()
+}
+
+// sameURL reports whether two absolute URLs are equal once normalized with urlKey
+func sameURL(a, b string) bool {
+	return urlKey(a) == urlKey(b)
+}
+
+// canonicalURL returns the canonical URL a page declares with a rel=canonical link or
+// Link header, or an empty string
+func (p *Page) canonicalURL() string {
+	if canonical := p.firstRelLink("canonical"); canonical != "" {
+		return canonical
+	}
+	if targets := parseLinkHeader(p.Header.Values("Link"), "canonical"); len(targets) > 0 {
+		return resolveURL(p.URL, targets[0])
+	}
+	return ""
+}
+
+// checkCanonical records the canonical URL of a page from its rel=canonical link or
+// Link header and flags pages whose canonical points elsewhere
+func checkCanonical(result *Result, page *Page) {
+	result.Canonical = page.canonicalURL()
+	if result.Canonical != "" && !sameURL(result.Canonical, result.URL) {
+		result.addIssue("CANONICAL_MISMATCH", "canonical points to "+result.Canonical)
+	}
+}
+
+// indexable reports whether a checked page can be indexed: it answered 200, has no
+// noindex directive, is not blocked by robots.txt and its canonical, if any, is itself
+func indexable(result *Result) bool {
+	if result.Error != nil || result.Status != 200 || result.Noindex || result.RobotsBlocked {
+		return false
+	}
+	return result.Canonical == "" || sameURL(result.Canonical, result.URL)
+}
+
+// formatIndexabilitySummary reports how many checked URLs can be indexed
+func formatIndexabilitySummary(results []Result) string {
+	count := 0
+	for _, result := range results {
+		if result.IsIndexable {
+			count++
+		}
+	}
+	return fmt.Sprintf("Indexable: %d URLs, not indexable: %d URLs", count, len(results)-count)
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"errors"
+	"testing"
+)
+
+// Test for checkNoindex function
+func TestCheckNoindex(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		head   string
+		want   bool
+	}{
+		{name: "indexable", head: `<meta name="robots" content="index, follow">`},
+		{name: "meta noindex", head: `<meta name="ROBOTS" content="noindex, follow">`, want: true},
+		{name: "meta none", head: `<meta name="robots" content="none">`, want: true},
+		{name: "header noindex", header: "noindex", want: true},
+		{name: "header for one bot", header: "googlebot: noindex, nofollow", want: true},
+		{name: "header nofollow", header: "nofollow"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page := parseTestPage(t, "https://example.com/", `<html><head>`+tt.head+`</head></html>`)
+			if tt.header != "" {
+				page.Header.Set("X-Robots-Tag", tt.header)
+			}
+			result := Result{URL: page.URL, Status: 200}
+			checkNoindex(&result, page)
+
+			if result.Noindex != tt.want {
+				t.Errorf("Noindex = %v, want %v", result.Noindex, tt.want)
+			}
+			if got := countIssues([]Result{result}, "NOINDEX") == 1; got != tt.want {
+				t.Errorf("NOINDEX issue = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// Test for checkCanonical function
+func TestCheckCanonical(t *testing.T) {
+	tests := []struct {
+		name       string
+		head       string
+		link       string
+		want       string
+		wantIssues int
+	}{
+		{name: "self", head: `<link rel="canonical" href="https://EXAMPLE.com/page">`, want: "https://EXAMPLE.com/page"},
+		{name: "relative elsewhere", head: `<link rel="canonical" href="/other">`, want: "https://example.com/other", wantIssues: 1},
+		{name: "link header", link: `<https://example.com/page>; rel="canonical"`, want: "https://example.com/page"},
+		{name: "none"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page := parseTestPage(t, "https://example.com/page", `<html><head>`+tt.head+`</head></html>`)
+			if tt.link != "" {
+				page.Header.Set("Link", tt.link)
+			}
+			result := Result{URL: page.URL, Status: 200}
+			checkCanonical(&result, page)
+
+			if result.Canonical != tt.want {
+				t.Errorf("Canonical = %q, want %q", result.Canonical, tt.want)
+			}
+			if got := countIssues([]Result{result}, "CANONICAL_MISMATCH"); got != tt.wantIssues {
+				t.Errorf("CANONICAL_MISMATCH issues = %d, want %d", got, tt.wantIssues)
+			}
+		})
+	}
+}
+
+// Test for indexable function
+func TestIndexable(t *testing.T) {
+	tests := []struct {
+		name   string
+		result Result
+		want   bool
+	}{
+		{name: "indexable", result: Result{URL: "https://example.com/", Status: 200, Canonical: "https://example.com/"}, want: true},
+		{name: "no canonical", result: Result{URL: "https://example.com/", Status: 200}, want: true},
+		{name: "not found", result: Result{URL: "https://example.com/", Status: 404}},
+		{name: "error", result: Result{URL: "https://example.com/", Error: errors.New("timeout")}},
+		{name: "noindex", result: Result{URL: "https://example.com/", Status: 200, Noindex: true}},
+		{name: "blocked", result: Result{URL: "https://example.com/", Status: 200, RobotsBlocked: true}},
+		{name: "canonical elsewhere", result: Result{URL: "https://example.com/a", Status: 200, Canonical: "https://example.com/b"}},
+	}
+
+	for _, tt := range tests {
+		if got := indexable(&tt.result); got != tt.want {
+			t.Errorf("%s: indexable() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	results := []Result{{IsIndexable: true}, {IsIndexable: true}, {}}
+	want := "Indexable: 2 URLs, not indexable: 1 URLs"
+	if got := formatIndexabilitySummary(results); got != want {
+		t.Errorf("formatIndexabilitySummary() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// jsonLDBlocks returns the contents of every <script type="application/ld+json"> element of the page
+func (p *Page) jsonLDBlocks() []string {
+	var blocks []string
+	walkHTML(p.Doc, func(n *html.Node) {
+		if n.Data != "script" {
+			return
+		}
+		scriptType, _ := attr(n, "type")
+		if !strings.EqualFold(strings.TrimSpace(scriptType), "application/ld+json") {
+			return
+		}
+		var text strings.Builder
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type == html.TextNode {
+				text.WriteString(c.Data)
+			}
+		}
+		blocks = append(blocks, text.String())
+	})
+	return blocks
+}
+
+// jsonLDTypes collects the @type values of a decoded JSON-LD document, including
+// the ones of top-level arrays and @graph entries
+func jsonLDTypes(doc interface{}) []string {
+	var types []string
+	switch v := doc.(type) {
+	case []interface{}:
+		for _, item := range v {
+			types = append(types, jsonLDTypes(item)...)
+		}
+	case map[string]interface{}:
+		switch t := v["@type"].(type) {
+		case string:
+			types = append(types, t)
+		case []interface{}:
+			for _, item := range t {
+				if s, ok := item.(string); ok {
+					types = append(types, s)
+				}
+			}
+		}
+		if graph, ok := v["@graph"]; ok {
+			types = append(types, jsonLDTypes(graph)...)
+		}
+	}
+	return types
+}
+
+// checkJSONLD validates the JSON-LD blocks of a page and records the schema types
+// they declare. A non-empty requiredType must be among them.
+func checkJSONLD(result *Result, page *Page, requiredType string) {
+	for i, block := range page.jsonLDBlocks() {
+		var doc interface{}
+		if err := json.Unmarshal([]byte(block), &doc); err != nil {
+			result.addIssue("INVALID_JSON_LD", fmt.Sprintf("block %d: %v", i+1, err))
+			continue
+		}
+		result.JSONLDTypes = append(result.JSONLDTypes, jsonLDTypes(doc)...)
+	}
+
+	if requiredType == "" {
+		return
+	}
+	for _, t := range result.JSONLDTypes {
+		if t == requiredType {
+			return
+		}
+	}
+	result.addIssue("MISSING_SCHEMA_TYPE", fmt.Sprintf("no JSON-LD block with @type %s", requiredType))
+}
+
+// formatJSONLDSummary reports how many pages declare structured data and how many failed validation
+func formatJSONLDSummary(results []Result, requiredType string) string {
+	typed := 0
+	for _, result := range results {
+		if len(result.JSONLDTypes) > 0 {
+			typed++
+		}
+	}
+	summary := fmt.Sprintf("JSON-LD: %d pages with a @type, %d invalid", typed, countIssues(results, "INVALID_JSON_LD"))
+	if requiredType != "" {
+		summary += fmt.Sprintf(", %d missing %s", countIssues(results, "MISSING_SCHEMA_TYPE"), requiredType)
+	}
+	return summary
+}
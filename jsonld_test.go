@@ -0,0 +1,70 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+// Test for checkJSONLD function
+func TestCheckJSONLD(t *testing.T) {
+	tests := []struct {
+		name         string
+		body         string
+		requiredType string
+		wantTypes    []string
+		wantCodes    []string
+	}{
+		{
+			name:      "valid block",
+			body:      `<script type="application/ld+json">{"@context": "https://schema.org", "@type": "Article"}</script>`,
+			wantTypes: []string{"Article"},
+		},
+		{
+			name:      "invalid block",
+			body:      `<script type="application/ld+json">{"@type": "Article",}</script>`,
+			wantCodes: []string{"INVALID_JSON_LD"},
+		},
+		{
+			name:      "graph and type arrays",
+			body:      `<script type="application/ld+json">{"@graph": [{"@type": "WebPage"}, {"@type": ["Product", "Thing"]}]}</script>`,
+			wantTypes: []string{"WebPage", "Product", "Thing"},
+		},
+		{
+			name:      "no type",
+			body:      `<script type="application/ld+json">{"name": "Example"}</script>`,
+			wantTypes: nil,
+		},
+		{
+			name:         "required type present",
+			body:         `<script type="application/ld+json">[{"@type": "BreadcrumbList"}, {"@type": "Product"}]</script>`,
+			requiredType: "Product",
+			wantTypes:    []string{"BreadcrumbList", "Product"},
+		},
+		{
+			name:         "required type missing",
+			body:         `<script type="application/ld+json">{"@type": "Article"}</script><script>var x = {</script>`,
+			requiredType: "Product",
+			wantTypes:    []string{"Article"},
+			wantCodes:    []string{"MISSING_SCHEMA_TYPE"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page := parseTestPage(t, "https://example.com/", `<html><head>`+tt.body+`</head></html>`)
+			result := Result{URL: page.URL, Status: 200}
+			checkJSONLD(&result, page, tt.requiredType)
+
+			if !reflect.DeepEqual(result.JSONLDTypes, tt.wantTypes) {
+				t.Errorf("JSONLDTypes = %v, want %v", result.JSONLDTypes, tt.wantTypes)
+			}
+			var codes []string
+			for _, issue := range result.Issues {
+				codes = append(codes, issue.Code)
+			}
+			if !reflect.DeepEqual(codes, tt.wantCodes) {
+				t.Errorf("issue codes = %v, want %v", codes, tt.wantCodes)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+)
+
+// JUnitTestSuites is the root element of a JUnit XML report
+type JUnitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Suites   []JUnitTestSuite `xml:"testsuite"`
+}
+
+// JUnitTestSuite groups the URLs of one sitemap
+type JUnitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Skipped  int             `xml:"skipped,attr"`
+	Time     float64         `xml:"time,attr"`
+	Cases    []JUnitTestCase `xml:"testcase"`
+}
+
+// JUnitTestCase is a checked URL. Redirects are skipped, errors and bad statuses fail.
+type JUnitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      float64       `xml:"time,attr"`
+	Skipped   *JUnitMessage `xml:"skipped,omitempty"`
+	Failure   *JUnitMessage `xml:"failure,omitempty"`
+}
+
+// JUnitMessage is the body of a <skipped> or <failure> element
+type JUnitMessage struct {
+	Message string `xml:"message,attr"`
+}
+
+// newJUnitReport converts check results to JUnit test suites, one per sitemap file
+func newJUnitReport(sitemapURL string, results []Result) JUnitTestSuites {
+	var report JUnitTestSuites
+	suiteIndex := make(map[string]int)
+
+	for _, result := range results {
+		name := result.SourceSitemap
+		if name == "" {
+			name = sitemapURL
+		}
+		i, ok := suiteIndex[name]
+		if !ok {
+			i = len(report.Suites)
+			suiteIndex[name] = i
+			report.Suites = append(report.Suites, JUnitTestSuite{Name: name})
+		}
+		suite := &report.Suites[i]
+
+		testCase := JUnitTestCase{
+			Name:      result.URL,
+			ClassName: name,
+			Time:      result.ResponseTime.Seconds(),
+		}
+		switch {
+		case result.Error != nil:
+			testCase.Failure = &JUnitMessage{Message: result.Error.Error()}
+		case result.IsRedirect:
+			testCase.Skipped = &JUnitMessage{Message: fmt.Sprintf("redirects to %s (Status: %d)", result.RedirectURL, result.Status)}
+		case result.Status < 200 || result.Status >= 300:
+			testCase.Failure = &JUnitMessage{Message: fmt.Sprintf("Status: %d", result.Status)}
+		}
+
+		suite.Cases = append(suite.Cases, testCase)
+		suite.Tests++
+		suite.Time += testCase.Time
+		report.Tests++
+		if testCase.Failure != nil {
+			suite.Failures++
+			report.Failures++
+		}
+		if testCase.Skipped != nil {
+			suite.Skipped++
+			report.Skipped++
+		}
+	}
+	return report
+}
+
+// writeJUnitReport writes the results as a JUnit XML document
+func writeJUnitReport(w io.Writer, sitemapURL string, results []Result) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(newJUnitReport(sitemapURL, results)); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// saveJUnitReport writes a JUnit XML report to a file
+func saveJUnitReport(path, sitemapURL string, results []Result) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create JUnit report: %w", err)
+	}
+	err = writeJUnitReport(file, sitemapURL, results)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Test for newJUnitReport function
+func TestNewJUnitReport(t *testing.T) {
+	results := []Result{
+		{URL: "https://example.com/", Status: 200, SourceSitemap: "https://example.com/pages.xml", ResponseTime: 250 * time.Millisecond},
+		{URL: "https://example.com/old", Status: 301, IsRedirect: true, RedirectURL: "https://example.com/new", SourceSitemap: "https://example.com/pages.xml"},
+		{URL: "https://example.com/missing", Status: 404, SourceSitemap: "https://example.com/posts.xml"},
+		{URL: "https://example.com/down", Error: errors.New("connection refused")},
+	}
+
+	report := newJUnitReport("https://example.com/sitemap.xml", results)
+
+	if report.Tests != 4 || report.Failures != 2 || report.Skipped != 1 {
+		t.Errorf("totals = %d tests, %d failures, %d skipped, want 4, 2, 1", report.Tests, report.Failures, report.Skipped)
+	}
+
+	wantSuites := []string{"https://example.com/pages.xml", "https://example.com/posts.xml", "https://example.com/sitemap.xml"}
+	if len(report.Suites) != len(wantSuites) {
+		t.Fatalf("got %d suites, want %d", len(report.Suites), len(wantSuites))
+	}
+	for i, name := range wantSuites {
+		if report.Suites[i].Name != name {
+			t.Errorf("suite %d name = %q, want %q", i, report.Suites[i].Name, name)
+		}
+	}
+
+	pages := report.Suites[0]
+	if pages.Tests != 2 || pages.Skipped != 1 || pages.Failures != 0 {
+		t.Errorf("pages suite = %d tests, %d failures, %d skipped, want 2, 0, 1", pages.Tests, pages.Failures, pages.Skipped)
+	}
+	if pages.Cases[0].Skipped != nil || pages.Cases[0].Failure != nil || pages.Cases[0].Time != 0.25 {
+		t.Errorf("200 test case = %+v, want a passing case taking 0.25s", pages.Cases[0])
+	}
+	if msg := report.Suites[1].Cases[0].Failure; msg == nil || msg.Message != "Status: 404" {
+		t.Errorf("404 failure = %+v, want Status: 404", msg)
+	}
+	if msg := report.Suites[2].Cases[0].Failure; msg == nil || msg.Message != "connection refused" {
+		t.Errorf("error failure = %+v, want connection refused", msg)
+	}
+}
+
+// Test for writeJUnitReport function
+func TestWriteJUnitReport(t *testing.T) {
+	results := []Result{
+		{URL: "https://example.com/?a=1&b=2", Status: 200},
+		{URL: "https://example.com/old", Status: 302, IsRedirect: true, RedirectURL: "https://example.com/new"},
+	}
+
+	var buf bytes.Buffer
+	if err := writeJUnitReport(&buf, "https://example.com/sitemap.xml", results); err != nil {
+		t.Fatalf("writeJUnitReport() error = %v", err)
+	}
+
+	output := buf.String()
+	if !strings.HasPrefix(output, "<?xml") {
+		t.Errorf("report does not start with an XML declaration:\n%s", output)
+	}
+	for _, want := range []string{`<testsuites tests="2" failures="0" skipped="1">`, `<skipped message="redirects to https://example.com/new (Status: 302)"></skipped>`, `a=1&amp;b=2`} {
+		if !strings.Contains(output, want) {
+			t.Errorf("report missing %q:\n%s", want, output)
+		}
+	}
+
+	var decoded JUnitTestSuites
+	if err := xml.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("report is not valid XML: %v", err)
+	}
+	if len(decoded.Suites) != 1 || len(decoded.Suites[0].Cases) != 2 {
+		t.Errorf("decoded report = %+v, want one suite with two cases", decoded)
+	}
+}
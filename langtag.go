@@ -0,0 +1,120 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// languageSubtags are the ISO 639-1 two-letter codes valid as BCP 47 primary language subtags
+var languageSubtags = stringSet(strings.Fields(`
+	aa ab ae af ak am an ar as av ay az ba be bg bh bi bm bn bo br bs ca ce ch co cr cs cu cv cy
+	da de dv dz ee el en eo es et eu fa ff fi fj fo fr fy ga gd gl gn gu gv ha he hi ho hr ht hu
+	hy hz ia id ie ig ii ik io is it iu ja jv ka kg ki kj kk kl km kn ko kr ks ku kv kw ky la lb
+	lg li ln lo lt lu lv mg mh mi mk ml mn mr ms mt my na nb nd ne ng nl nn no nr nv ny oc oj om
+	or os pa pi pl ps pt qu rm rn ro ru rw sa sc sd se sg si sk sl sm sn so sq sr ss st su sv sw
+	ta te tg th ti tk tl tn to tr ts tt tw ty ug uk ur uz ve vi vo wa wo xh yi yo za zh zu`))
+
+// regionSubtags are the ISO 3166-1 alpha-2 codes valid as BCP 47 region subtags
+var regionSubtags = stringSet(strings.Fields(`
+	AD AE AF AG AI AL AM AO AQ AR AS AT AU AW AX AZ BA BB BD BE BF BG BH BI BJ BL BM BN BO BQ BR
+	BS BT BV BW BY BZ CA CC CD CF CG CH CI CK CL CM CN CO CR CU CV CW CX CY CZ DE DJ DK DM DO DZ
+	EC EE EG EH ER ES ET FI FJ FK FM FO FR GA GB GD GE GF GG GH GI GL GM GN GP GQ GR GS GT GU GW
+	GY HK HM HN HR HT HU ID IE IL IM IN IO IQ IR IS IT JE JM JO JP KE KG KH KI KM KN KP KR KW KY
+	KZ LA LB LC LI LK LR LS LT LU LV LY MA MC MD ME MF MG MH MK ML MM MN MO MP MQ MR MS MT MU MV
+	MW MX MY MZ NA NC NE NF NG NI NL NO NP NR NU NZ OM PA PE PF PG PH PK PL PM PN PR PS PT PW PY
+	QA RE RO RS RU RW SA SB SC SD SE SG SH SI SJ SK SL SM SN SO SR SS ST SV SX SY SZ TC TD TF TG
+	TH TJ TK TL TM TN TO TR TT TV TW TZ UA UG UM US UY UZ VA VC VE VG VI VN VU WF WS YE YT ZA ZM
+	ZW EU UN`))
+
+// stringSet builds a set from a list of strings
+func stringSet(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, v := range values {
+		set[v] = true
+	}
+	return set
+}
+
+// isAlpha reports whether s consists of ASCII letters only
+func isAlpha(s string) bool {
+	for _, c := range s {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z') {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// isDigits reports whether s consists of ASCII digits only
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// validLangTag reports whether a lang attribute value is a valid BCP 47 tag. The primary
+// language must be a known two-letter code; three-letter ISO 639 codes are accepted by form.
+// A script (four letters) and a region (a known two-letter code or three digits) may follow,
+// and any further variant or extension subtags are only checked for their length.
+func validLangTag(tag string) bool {
+	subtags := strings.Split(tag, "-")
+	primary := strings.ToLower(subtags[0])
+	switch {
+	case len(primary) == 2 && languageSubtags[primary]:
+	case len(primary) == 3 && isAlpha(primary):
+	default:
+		return false
+	}
+
+	rest := subtags[1:]
+	if len(rest) > 0 && len(rest[0]) == 4 && isAlpha(rest[0]) {
+		rest = rest[1:]
+	}
+	if len(rest) > 0 {
+		switch {
+		case len(rest[0]) == 2:
+			if !regionSubtags[strings.ToUpper(rest[0])] {
+				return false
+			}
+			rest = rest[1:]
+		case len(rest[0]) == 3 && isDigits(rest[0]):
+			rest = rest[1:]
+		}
+	}
+	for _, subtag := range rest {
+		if len(subtag) < 1 || len(subtag) > 8 {
+			return false
+		}
+	}
+	return true
+}
+
+// htmlLang returns the lang attribute of the page's <html> element
+func (p *Page) htmlLang() (string, bool) {
+	var lang string
+	found := false
+	walkHTML(p.Doc, func(n *html.Node) {
+		if found || n.Data != "html" {
+			return
+		}
+		lang, found = attr(n, "lang")
+	})
+	return strings.TrimSpace(lang), found
+}
+
+// checkLangTag records the lang attribute of a page and flags a missing or invalid one
+func checkLangTag(result *Result, page *Page) {
+	lang, ok := page.htmlLang()
+	result.HTMLLang = lang
+	switch {
+	case !ok || lang == "":
+		result.addIssue("MISSING_LANG", "<html> element has no lang attribute")
+	case !validLangTag(lang):
+		result.addIssue("INVALID_LANG", fmt.Sprintf("lang=%q is not a valid BCP 47 language tag", lang))
+	}
+}
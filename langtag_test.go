@@ -0,0 +1,61 @@
+package main
+
+import "testing"
+
+// Test for validLangTag function
+func TestValidLangTag(t *testing.T) {
+	tests := map[string]bool{
+		"en":                     true,
+		"en-US":                  true,
+		"en-us":                  true,
+		"zh-Hant-TW":             true,
+		"es-419":                 true,
+		"de-CH-1996":             true,
+		"yue":                    true,
+		"english":                false,
+		"en_US":                  false,
+		"xx":                     false,
+		"en-XX":                  false,
+		"en-US-waytoolongsubtag": false,
+		"":                       false,
+	}
+	for tag, want := range tests {
+		if got := validLangTag(tag); got != want {
+			t.Errorf("validLangTag(%q) = %v, want %v", tag, got, want)
+		}
+	}
+}
+
+// Test for checkLangTag function
+func TestCheckLangTag(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		wantLang string
+		wantCode string
+	}{
+		{name: "valid", body: `<html lang="fr-CA"><head></head></html>`, wantLang: "fr-CA"},
+		{name: "missing", body: `<html><head></head></html>`, wantCode: "MISSING_LANG"},
+		{name: "empty", body: `<html lang=" "><head></head></html>`, wantCode: "MISSING_LANG"},
+		{name: "invalid", body: `<html lang="en_GB"><head></head></html>`, wantLang: "en_GB", wantCode: "INVALID_LANG"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page := parseTestPage(t, "https://example.com/", tt.body)
+			result := Result{URL: page.URL, Status: 200}
+			checkLangTag(&result, page)
+
+			if result.HTMLLang != tt.wantLang {
+				t.Errorf("HTMLLang = %q, want %q", result.HTMLLang, tt.wantLang)
+			}
+			var code string
+			if len(result.Issues) > 0 {
+				code = result.Issues[0].Code
+			}
+			if code != tt.wantCode {
+				t.Errorf("issue code = %q, want %q", code, tt.wantCode)
+			}
+		})
+	}
+}
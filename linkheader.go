@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// parseLinkHeader returns the target of every link in Link header values with the given rel
+func parseLinkHeader(values []string, rel string) []string {
+	var targets []string
+	for _, value := range values {
+		rest := value
+		for {
+			start := strings.Index(rest, "<")
+			if start < 0 {
+				break
+			}
+			end := strings.Index(rest[start:], ">")
+			if end < 0 {
+				break
+			}
+			target := strings.TrimSpace(rest[start+1 : start+end])
+			rest = rest[start+end+1:]
+
+			// Parameters run until the comma separating the next link
+			params := rest
+			if next := strings.Index(rest, ","); next >= 0 {
+				params, rest = rest[:next], rest[next+1:]
+			} else {
+				rest = ""
+			}
+			if linkHasRel(params, rel) {
+				targets = append(targets, target)
+			}
+		}
+	}
+	return targets
+}
+
+// linkHasRel reports whether the parameters of a Link header entry include the given rel
+func linkHasRel(params, rel string) bool {
+	for _, param := range strings.Split(params, ";") {
+		name, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+		if !ok || !strings.EqualFold(strings.TrimSpace(name), "rel") {
+			continue
+		}
+		for _, r := range strings.Fields(strings.Trim(strings.TrimSpace(value), `"`)) {
+			if strings.EqualFold(r, rel) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// checkPreloadLinks records the preload hints of a response's Link header and flags
+// the targets that are unreachable
+func checkPreloadLinks(client *http.Client, result *Result) {
+	for _, target := range parseLinkHeader(result.Header.Values("Link"), "preload") {
+		target = resolveURL(result.URL, target)
+		result.PreloadLinks = append(result.PreloadLinks, target)
+
+		status, err := headCheck(client, target)
+		if err != nil {
+			result.addIssue("PRELOAD_TARGET_BROKEN", fmt.Sprintf("%s: %v", target, err))
+		} else if isBroken(status, nil) {
+			result.addIssue("PRELOAD_TARGET_BROKEN", fmt.Sprintf("%s (Status: %d)", target, status))
+		}
+	}
+}
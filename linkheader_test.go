@@ -0,0 +1,46 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Test for parseLinkHeader function
+func TestParseLinkHeader(t *testing.T) {
+	values := []string{
+		`</style.css>; rel=preload; as=style, </font.woff2>; rel="preload"; as=font; crossorigin`,
+		`<https://example.com/page?a=1,2>; rel="next preload", </next>; rel=next`,
+	}
+
+	got := parseLinkHeader(values, "preload")
+	want := []string{"/style.css", "/font.woff2", "https://example.com/page?a=1,2"}
+	if !equalStringSlices(got, want) {
+		t.Errorf("parseLinkHeader() = %v, want %v", got, want)
+	}
+}
+
+// Test for checkPreloadLinks function
+func TestCheckPreloadLinks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing.js" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	result := Result{
+		URL:    server.URL + "/page",
+		Status: http.StatusOK,
+		Header: http.Header{"Link": {`</app.css>; rel=preload; as=style, </missing.js>; rel=preload; as=script`}},
+	}
+	checkPreloadLinks(server.Client(), &result)
+
+	want := []string{server.URL + "/app.css", server.URL + "/missing.js"}
+	if !equalStringSlices(result.PreloadLinks, want) {
+		t.Errorf("PreloadLinks = %v, want %v", result.PreloadLinks, want)
+	}
+	if len(result.Issues) != 1 || result.Issues[0].Code != "PRELOAD_TARGET_BROKEN" {
+		t.Errorf("Issues = %v, want one PRELOAD_TARGET_BROKEN", result.Issues)
+	}
+}
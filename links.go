@@ -0,0 +1,197 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/html"
+)
+
+// anchorLinks returns the resolved http(s) targets of the page's <a href> links without
+// their fragment, each once
+func (p *Page) anchorLinks() []string {
+	seen := make(map[string]bool)
+	var links []string
+	walkHTML(p.Doc, func(n *html.Node) {
+		if n.Data != "a" {
+			return
+		}
+		href, ok := attr(n, "href")
+		if !ok || strings.TrimSpace(href) == "" {
+			return
+		}
+		linkURL, err := url.Parse(resolveURL(p.URL, href))
+		if err != nil || (linkURL.Scheme != "http" && linkURL.Scheme != "https") {
+			return
+		}
+		linkURL.Fragment = ""
+		if link := linkURL.String(); !seen[link] {
+			seen[link] = true
+			links = append(links, link)
+		}
+	})
+	return links
+}
+
+// splitLinks separates links to the page's own host from links to other hosts
+func splitLinks(pageURL string, links []string) (internal, external []string) {
+	pageHost := ""
+	if parsedURL, err := url.Parse(pageURL); err == nil {
+		pageHost = strings.ToLower(parsedURL.Host)
+	}
+	for _, link := range links {
+		linkURL, err := url.Parse(link)
+		if err != nil {
+			continue
+		}
+		if strings.EqualFold(linkURL.Host, pageHost) {
+			internal = append(internal, link)
+		} else {
+			external = append(external, link)
+		}
+	}
+	return internal, external
+}
+
+// uniqueLinks returns the links of all results, each once
+func uniqueLinks(results []Result, links func(Result) []string) []string {
+	var unique []string
+	seen := make(map[string]bool)
+	for _, result := range results {
+		for _, link := range links(result) {
+			if !seen[link] {
+				seen[link] = true
+				unique = append(unique, link)
+			}
+		}
+	}
+	return unique
+}
+
+// linkThrottle spaces out link checks the way checkURLs spaces out the sitemap URLs
+type linkThrottle struct {
+	// delay is the -t delay between requests
+	delay time.Duration
+	// backoff raises the delay while the server rate limits requests
+	backoff *BackoffController
+	// domains limits how many domains are contacted at once
+	domains *DomainLimiter
+}
+
+// headCheckLinks HEAD-checks links with up to concurrency requests at a time. The checker
+// caches the outcome, so the pages linking to them only read it afterwards.
+func headCheckLinks(checker *AssetChecker, links []string, concurrency int, throttle linkThrottle) {
+	sem := make(chan struct{}, max(concurrency, 1))
+	var wg sync.WaitGroup
+	for _, link := range links {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(link string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			defer throttle.domains.Release(throttle.domains.Acquire(link))
+			status, err := checker.Check(link)
+			throttle.backoff.Record(Result{URL: link, Status: status, Error: err})
+		}(link)
+
+		if throttle.backoff.Backing() {
+			time.Sleep(throttle.backoff.Delay())
+		} else if throttle.delay > 0 && len(sem) < cap(sem) {
+			time.Sleep(throttle.delay)
+		}
+	}
+	wg.Wait()
+}
+
+// flagBrokenLinks adds an issue with code to every result linking to a link the checker
+// found broken and returns how many different links were broken
+func flagBrokenLinks(checker *AssetChecker, results []Result, links func(Result) []string, code string, logger *Logger) int {
+	brokenLinks := make(map[string]bool)
+	for i := range results {
+		result := &results[i]
+		for _, msg := range checker.brokenAssets(links(*result)) {
+			brokenLinks[msg] = true
+			result.addIssue(code, msg)
+			if logger != nil {
+				logger.Log(fmt.Sprintf("%s: %s - %s", code, result.URL, msg) + sourceTag(result.SourceSitemap))
+			}
+		}
+	}
+	return len(brokenLinks)
+}
+
+// checkExternalLinks HEAD-checks every external link found on the checked pages once,
+// with up to concurrency requests at a time, and flags the pages linking to broken ones.
+// It returns how many unique links were checked and how many were broken.
+func checkExternalLinks(checker *AssetChecker, results []Result, concurrency int, throttle linkThrottle, logger *Logger) (int, int) {
+	externalLinks := func(r Result) []string { return r.ExternalLinks }
+	links := uniqueLinks(results, externalLinks)
+	headCheckLinks(checker, links, concurrency, throttle)
+	return len(links), flagBrokenLinks(checker, results, externalLinks, "BROKEN_EXTERNAL_LINK", logger)
+}
+
+// checkInternalLinks flags the same-host links of the checked pages that are missing from
+// the sitemap, once per link, and flags the pages linking to broken ones. Links to checked
+// URLs reuse their result, the others are HEAD-checked once with the throttle applied.
+// It returns how many unique links were checked, were broken and were missing from the sitemap.
+func checkInternalLinks(checker *AssetChecker, results []Result, sitemapURLs []URL, concurrency int, throttle linkThrottle, logger *Logger) (int, int, int) {
+	inSitemap := make(map[string]bool, len(sitemapURLs))
+	for _, u := range sitemapURLs {
+		inSitemap[urlKey(u.Loc)] = true
+	}
+
+	// Report each missing link once, on the first page linking to it
+	type missingLink struct {
+		link   string
+		result *Result
+		pages  int
+	}
+	var missing []*missingLink
+	notInSitemap := make(map[string]*missingLink)
+	for i := range results {
+		result := &results[i]
+		for _, link := range result.InternalLinks {
+			key := urlKey(link)
+			if inSitemap[key] {
+				continue
+			}
+			if m, ok := notInSitemap[key]; ok {
+				m.pages++
+				continue
+			}
+			m := &missingLink{link: link, result: result, pages: 1}
+			notInSitemap[key] = m
+			missing = append(missing, m)
+		}
+	}
+	for _, m := range missing {
+		msg := fmt.Sprintf("%s (linked from %d pages)", m.link, m.pages)
+		m.result.addIssue("LINKED_NOT_IN_SITEMAP", msg)
+		if logger != nil {
+			logger.Log(fmt.Sprintf("LINKED_NOT_IN_SITEMAP: %s - %s", m.result.URL, msg) + sourceTag(m.result.SourceSitemap))
+		}
+	}
+
+	// The checked URLs already have a status, only the other links are requested
+	checked := make(map[string]*Result, len(results))
+	for i := range results {
+		if key := urlKey(results[i].URL); checked[key] == nil {
+			checked[key] = &results[i]
+		}
+	}
+	internalLinks := func(r Result) []string { return r.InternalLinks }
+	links := uniqueLinks(results, internalLinks)
+	var unchecked []string
+	for _, link := range links {
+		if result := checked[urlKey(link)]; result != nil {
+			checker.Record(link, result.Status, result.Error)
+		} else {
+			unchecked = append(unchecked, link)
+		}
+	}
+	headCheckLinks(checker, unchecked, concurrency, throttle)
+	return len(links), flagBrokenLinks(checker, results, internalLinks, "BROKEN_INTERNAL_LINK", logger), len(missing)
+}
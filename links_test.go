@@ -0,0 +1,115 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+)
+
+// Test for Page.anchorLinks and splitLinks
+func TestAnchorLinks(t *testing.T) {
+	page := parseTestPage(t, "https://example.com/blog/", `<html><body>
+		<a href="post#comments">Post</a>
+		<a href="/blog/post">Post again</a>
+		<a href="https://other.example/page">Other</a>
+		<a href="mailto:hi@example.com">Mail</a>
+		<a href="javascript:void(0)">JS</a>
+		<a href="#top">Top</a>
+		<a>No href</a>
+		</body></html>`)
+
+	links := page.anchorLinks()
+	want := []string{"https://example.com/blog/post", "https://other.example/page", "https://example.com/blog/"}
+	if !reflect.DeepEqual(links, want) {
+		t.Fatalf("anchorLinks() = %v, want %v", links, want)
+	}
+
+	internal, external := splitLinks(page.URL, links)
+	if !reflect.DeepEqual(internal, []string{"https://example.com/blog/post", "https://example.com/blog/"}) {
+		t.Errorf("internal = %v", internal)
+	}
+	if !reflect.DeepEqual(external, []string{"https://other.example/page"}) {
+		t.Errorf("external = %v", external)
+	}
+}
+
+// Test for checkExternalLinks function
+func TestCheckExternalLinks(t *testing.T) {
+	requests := make(chan string, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests <- r.URL.Path
+		if r.URL.Path == "/gone" {
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	results := []Result{
+		{URL: "https://example.com/a", Status: 200, ExternalLinks: []string{server.URL + "/ok", server.URL + "/gone"}},
+		{URL: "https://example.com/b", Status: 200, ExternalLinks: []string{server.URL + "/gone"}},
+		{URL: "https://example.com/c", Status: 200},
+	}
+
+	checked, broken := checkExternalLinks(NewAssetChecker(server.Client()), results, 2, linkThrottle{}, nil)
+	if checked != 2 || broken != 1 {
+		t.Errorf("checkExternalLinks() = %d checked, %d broken, want 2, 1", checked, broken)
+	}
+	if len(requests) != 2 {
+		t.Errorf("made %d requests, want 2", len(requests))
+	}
+	for i, want := range []int{1, 1, 0} {
+		if got := countIssues(results[i:i+1], "BROKEN_EXTERNAL_LINK"); got != want {
+			t.Errorf("%s BROKEN_EXTERNAL_LINK = %d, want %d", results[i].URL, got, want)
+		}
+	}
+}
+
+// Test for checkInternalLinks function
+func TestCheckInternalLinks(t *testing.T) {
+	var mu sync.Mutex
+	var requested []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requested = append(requested, r.URL.Path)
+		mu.Unlock()
+		if r.URL.Path == "/gone" {
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	sitemap := []URL{{Loc: server.URL + "/"}, {Loc: server.URL + "/about"}}
+	results := []Result{
+		{URL: server.URL + "/", Status: 200, InternalLinks: []string{server.URL + "/about", server.URL + "/hidden", server.URL + "/gone"}},
+		{URL: server.URL + "/about", Status: 200, InternalLinks: []string{server.URL, server.URL + "/hidden"}},
+	}
+
+	checked, broken, notInSitemap := checkInternalLinks(NewAssetChecker(server.Client()), results, sitemap, 2, linkThrottle{}, nil)
+	if checked != 4 || broken != 1 || notInSitemap != 2 {
+		t.Errorf("checkInternalLinks() = %d, %d, %d, want 4, 1, 2", checked, broken, notInSitemap)
+	}
+	codes := make(map[string]int)
+	for _, issue := range results[0].Issues {
+		codes[issue.Code]++
+	}
+	if codes["LINKED_NOT_IN_SITEMAP"] != 2 || codes["BROKEN_INTERNAL_LINK"] != 1 {
+		t.Errorf("issues for %s = %v, want 2 LINKED_NOT_IN_SITEMAP and 1 BROKEN_INTERNAL_LINK", results[0].URL, results[0].Issues)
+	}
+	if results[0].Issues[0].Message != server.URL+"/hidden (linked from 2 pages)" {
+		t.Errorf("LINKED_NOT_IN_SITEMAP message = %q, want the link and its page count", results[0].Issues[0].Message)
+	}
+
+	// The missing link is reported once, on the first page linking to it
+	if len(results[1].Issues) != 0 {
+		t.Errorf("issues for %s = %v, want none", results[1].URL, results[1].Issues)
+	}
+
+	// Links to checked URLs reuse their result
+	sort.Strings(requested)
+	if want := []string{"/gone", "/hidden"}; !equalStringSlices(requested, want) {
+		t.Errorf("requested %v, want %v", requested, want)
+	}
+}
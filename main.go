@@ -1,7 +1,9 @@
 package main
 
 import (
+	"crypto/rand"
 	"crypto/tls"
+	"encoding/hex"
 	"encoding/xml"
 	"flag"
 	"fmt"
@@ -10,6 +12,7 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"sync"
 	"time"
@@ -37,7 +40,17 @@ type URLSet struct {
 
 // URL represents a URL entry in a sitemap file
 type URL struct {
-	Loc string `xml:"loc"`
+	Loc    string   `xml:"loc"`
+	Images []string `xml:"image>loc"`
+
+	// Source is the sitemap file the URL was found in
+	Source string `xml:"-"`
+
+	// Original is the URL as listed in the sitemap when Loc was rewritten
+	Original string `xml:"-"`
+
+	// Issues are problems found in the URL itself before it is checked
+	Issues []Issue `xml:"-"`
 }
 
 // Result represents the result of checking a URL
@@ -47,12 +60,214 @@ type Result struct {
 	Error       error
 	RedirectURL string
 	IsRedirect  bool
+
+	// SourceSitemap is the sitemap file the URL was found in
+	SourceSitemap string
+	OriginalURL   string
+	HeadStatus    int
+	GetStatus     int
+	Header        http.Header
+	Images        []string
+	Protocol      string
+	ResponseTime  time.Duration
+	RetryCount    int
+	// TLSVersion is the TLS version the HTTPS connection negotiated, e.g. "TLS 1.3"
+	TLSVersion string
+	// CompressionEncoding is the Content-Encoding of a text response, "identity" when uncompressed
+	CompressionEncoding string
+
+	CloakingDetected bool
+	BotStatus        int
+	UTF8Valid        bool
+	ServerHeader     string
+	FromCache        bool
+	OGImage          string
+	XPoweredBy       string
+	CacheControl     string
+	ETagSupported    bool
+	AMPURL           string
+	AMPValid         bool
+	CSPHeader        string
+	HreflangTargets  []string
+	HreflangTarget   bool
+
+	// RedirectChain lists every URL of a followed redirect chain, starting with URL
+	RedirectChain []string
+	// RenderTime is the time until the load event when the page is rendered in a browser
+	RenderTime           time.Duration
+	DOMContentLoadedTime time.Duration
+	// PaginationCanonicalIssue describes canonical links pointing to another page of the pagination chain
+	PaginationCanonicalIssue string
+	// HTMLSize is the number of bytes in the body of an HTML page
+	HTMLSize int64
+	// MaxAge is the Cache-Control max-age of an HTML page in seconds
+	MaxAge int
+	// PreloadLinks are the rel=preload targets of the Link response header
+	PreloadLinks []string
+	// Title is the text of the page's <title> element
+	Title       string
+	TitleLength int
+	// MetaDescription is the content of the page's meta description
+	MetaDescription string
+	// JSONLDTypes are the schema @type values declared by the page's JSON-LD blocks
+	JSONLDTypes []string
+	// SchemaIssues describe structured data items lacking required properties
+	SchemaIssues []string
+	// Canonical is the page's canonical URL from a rel=canonical link or Link header
+	Canonical     string
+	Noindex       bool
+	RobotsBlocked bool
+	// IsIndexable combines the status, noindex, canonical and robots.txt signals
+	IsIndexable bool
+	// CustomErrorPage is set when a 404 response serves a page other than the server's default
+	CustomErrorPage bool
+	// BudgetMs is the response time budget of the -time-budget pattern matching the URL
+	BudgetMs      int
+	BudgetPattern string
+	// URLLength is the number of characters in the URL
+	URLLength int
+	// MobileFriendly is Google's Mobile-Friendly Test verdict, nil when the page wasn't tested
+	MobileFriendly *bool
+
+	Issues           []Issue
+	PaginationIssues []string
+	BrokenImages     []string
+	VaryIssues       []string
+	FontIssues       []string
+	CSSIssues        []string
+	JSIssues         []string
+	// InternalLinks and ExternalLinks are the links of the page to its own and to other hosts
+	InternalLinks []string
+	ExternalLinks []string
+	// Expires is the date of the Expires header, nil when it is missing or invalid
+	Expires *time.Time
+	// WordCount is the number of words in the visible text of the page, set when WordCounted is
+	WordCount   int
+	WordCounted bool
+	// A11yIssues are the accessibility problems found on the page
+	A11yIssues []string
+	// BrokenAnchors are the in-page anchor links whose target is not on the page
+	BrokenAnchors []string
+	// ViewportContent is the content of the page's viewport meta tag
+	ViewportContent string
+	// HTMLLang is the lang attribute of the page's <html> element
+	HTMLLang string
+	// IconLinks are the rel=icon links of the page
+	IconLinks []string
+	// MixedContentURLs are the resources an HTTPS page loads over plain HTTP
+	MixedContentURLs []string
+	// HTTPVersionIssues are the assets of an HTTP/2 page served over HTTP/1.x
+	HTTPVersionIssues []string
+
+	// ResourceHintIssues are the preconnect and dns-prefetch hosts that failed to resolve
+	ResourceHintIssues []string
+}
+
+// Issue represents a problem detected by one of the optional checks
+type Issue struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// CheckOptions holds the optional checks enabled on the command line
+type CheckOptions struct {
+	CheckPagination  bool
+	VerifyGet        bool
+	CheckHTTPToHTTPS bool
+	CheckImages      bool
+	CheckVary        bool
+	CloakingCheck    bool
+	BodyCheck        bool
+	CheckUTF8        bool
+	PerHostTimeout   time.Duration
+	UseCache         bool
+	CheckOGImage     bool
+	CheckCache       bool
+	Verbose          bool
+
+	CheckServerHeader    bool
+	FlagServerDisclosure bool
+	CheckXPoweredBy      bool
+	AllowedPoweredBy     *regexp.Regexp
+	CheckPaginationFull  bool
+	CheckETag            bool
+	CheckHTTP10          bool
+	CheckXCTO            bool
+	CheckAMP             bool
+	CheckCSP             bool
+	TUI                  bool
+	HreflangTargets      bool
+	BackoffOnTimeout     bool
+	MaxRetries           int
+	CheckJSONLD          bool
+	RequireJSONLDType    string
+	FollowRedirects      bool
+	MaxRedirectChain     int
+	CheckTitle           bool
+	CheckTitleLength     bool
+	TitleMin             int
+	TitleMax             int
+	CheckLinkHeader      bool
+	CheckMaxAge          bool
+	MaxHTMLAge           int
+	CheckNosniff         bool
+	CheckFonts           bool
+	PaginationCanonical  bool
+	CheckResourceHints   bool
+	CheckMetaDescription bool
+	CheckHTMLSize        bool
+	MaxHTMLBytes         int64
+	CheckSchema          bool
+	RequiredSchemaType   string
+	CheckTLSVersion      bool
+	CheckCompression     bool
+	CheckNoindex         bool
+	CheckCanonical       bool
+	CheckIndexability    bool
+	HreflangComplete     bool
+	CheckCanonRedirect   bool
+	CheckCustom404       bool
+	CheckCSS             bool
+	CheckJS              bool
+	CheckHTTPVersion     bool
+	CheckMixedContent    bool
+	CheckFavicon         bool
+	CheckLangTag         bool
+	CheckExternalLinks   bool
+	CheckInternalLinks   bool
+	CheckWordCount       bool
+	MinWords             int
+	MaxConcurrentDomains int
+	CheckExpires         bool
+	CheckA11y            bool
+	CheckInPageAnchors   bool
+	CheckViewport        bool
+
+	// Webhook receives broken and redirected URLs as they are found
+	Webhook *WebhookNotifier
+	// Progress records every checked URL so the run can be resumed
+	Progress *ProgressFile
+	// Tracing exports a span for every checked URL
+	Tracing *Tracing
+	// Renderer measures page load times in headless Chrome
+	Renderer *Renderer
+	// Robots checks URLs against the robots.txt of their host
+	Robots *RobotsChecker
+	// MobileFriendly runs Google's Mobile-Friendly Test on pages
+	MobileFriendly *MobileFriendlyChecker
+	// Backoff raises the delay between requests when the server rate limits them
+	Backoff *BackoffController
+	// Assets checks the stylesheets and scripts pages reference, each only once
+	Assets *AssetChecker
+	// Pagination walks each pagination chain once and reports its issues once
+	Pagination *PaginationWalker
 }
 
 // Logger represents a simple logger for writing to a file
 type Logger struct {
-	file *os.File
-	mu   sync.Mutex
+	file  *os.File
+	mu    sync.Mutex
+	runID string
 }
 
 // ProgressBar represents a simple progress bar
@@ -61,6 +276,9 @@ type ProgressBar struct {
 	current    int
 	mu         sync.Mutex
 	lastUpdate time.Time
+
+	// dashboard replaces the progress bar output when -tui is used
+	dashboard *Dashboard
 }
 
 // NewProgressBar creates a new progress bar
@@ -95,10 +313,22 @@ func NewLogger(filename string) (*Logger, error) {
 func (l *Logger) Log(message string) error {
 	l.mu.Lock()
 	defer l.mu.Unlock()
+	if l.runID != "" {
+		message = fmt.Sprintf("[RunID: %s] %s", l.runID, message)
+	}
 	_, err := fmt.Fprintln(l.file, message)
 	return err
 }
 
+// newRunID generates a random ID that tags every log line of this run
+func newRunID() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate run ID: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
 // Close closes the log file
 func (l *Logger) Close() error {
 	l.mu.Lock()
@@ -135,6 +365,14 @@ func createLogFilename(sitemapURL string) (string, error) {
 	return filename, nil
 }
 
+// sourceTag returns the log annotation naming the sitemap a URL was found in
+func sourceTag(source string) string {
+	if source == "" {
+		return ""
+	}
+	return fmt.Sprintf(" [source: %s]", source)
+}
+
 // indexOf returns the index of the first instance of substr in s, or -1 if not found
 func indexOf(s, substr string) int {
 	for i := 0; i <= len(s)-len(substr); i++ {
@@ -151,6 +389,11 @@ func (pb *ProgressBar) Increment() {
 	defer pb.mu.Unlock()
 	pb.current++
 
+	if pb.dashboard != nil {
+		pb.dashboard.SetProgress(pb.current)
+		return
+	}
+
 	// Only update the progress bar every 100ms to avoid flooding the terminal
 	if time.Since(pb.lastUpdate) > 100*time.Millisecond || pb.current == pb.total {
 		pb.update()
@@ -185,23 +428,285 @@ func (pb *ProgressBar) update() {
 
 func main() {
 	// Define command-line flags
-	sitemapURL := flag.String("u", "", "URL of the sitemap.xml file (required)")
+	sitemapURL := flag.String("u", "", "URL of the sitemap.xml file (required unless -csv-input is used)")
+	csvInput := flag.String("csv-input", "", "Check the URLs listed in this CSV file instead of a sitemap")
+	urlColumn := flag.Int("url-column", 0, "Index of the CSV column holding the URLs, starting at 0 (used with -csv-input)")
+	csvHeader := flag.Bool("csv-header", false, "Skip the first row of the CSV file (used with -csv-input)")
 	timeout := flag.Int("t", 1000, "Timeout in milliseconds between check requests")
 	logDir := flag.String("logdir", "", "Directory to store log files (default: current directory)")
 	concurrency := flag.Int("c", 1, "Number of parallel requests to execute simultaneously")
 	insecure := flag.Bool("k", false, "Skip SSL certificate validation")
+	verifyDNSFlag := flag.Bool("verify-dns", false, "Resolve every hostname before checking and skip the URLs of hosts that fail as DNS_FAIL")
+	whoisCheck := flag.Bool("whois-check", false, "Look up the WHOIS expiry date of every domain in the sitemap")
+	domainWarnDays := flag.Int("domain-warn-days", 30, "Flag domains expiring within this many days (used with -whois-check)")
+	chunkSize := flag.Int("chunk-size", 0, "Split the URL list into chunks of this size and check only one chunk (0 disables chunking)")
+	chunkIndex := flag.Int("chunk-index", 0, "Index of the chunk to check, starting at 0 (used with -chunk-size)")
+	ignoreParams := flag.String("ignore-params", "", "Comma-separated query parameters to strip from URLs before checking, e.g. utm_source,fbclid")
+	stripUTM := flag.Bool("strip-utm", false, "Remove utm_* campaign parameters from URLs before checking")
+	checkProtocolRelative := flag.Bool("check-protocol-relative", false, "Flag sitemap URLs starting with // without a scheme as PROTOCOL_RELATIVE_URL")
+	fixProtocolRelative := flag.Bool("fix-protocol-relative", false, "Add -default-scheme to protocol-relative URLs before checking them")
+	defaultScheme := flag.String("default-scheme", "https", "Scheme given to protocol-relative URLs (used with -fix-protocol-relative)")
+	checkIPURLs := flag.Bool("check-ip-address-urls", false, "Flag sitemap URLs whose host is an IP address as IP_ADDRESS_URL")
+	autoBackoff := flag.Bool("auto-backoff", false, "Double the delay between requests on every 429 response and lower it again after 10 successful requests")
+	checkURLLengthFlag := flag.Bool("check-url-length", false, "Flag URLs longer than -max-url-length or with query strings longer than -max-query-length before checking")
+	maxURLLength := flag.Int("max-url-length", 2048, "Longest URL in characters (used with -check-url-length)")
+	maxQueryLength := flag.Int("max-query-length", 1024, "Longest query string in characters (used with -check-url-length)")
+	urlAllowlist := flag.String("url-allowlist", "", "File of URL prefixes or glob patterns, one per line; flag sitemap URLs matching none as URL_NOT_IN_ALLOWLIST")
+	dedupCase := flag.Bool("dedup-case-insensitive", false, "Check URLs differing only in letter case once and flag them as CASE_DUPLICATE")
+	dedupScheme := flag.Bool("dedup-scheme-insensitive", false, "Check http:// and https:// variants of a URL once and flag them as SCHEME_DUPLICATE")
+	normalizeURLs := flag.Bool("normalize-urls", false, "Canonicalize URLs before checking and flag the ones that change")
+	checkCompliance := flag.Bool("check-url-compliance", false, "Flag URLs that are not in the form the WHATWG URL standard requires")
+	dryRun := flag.Bool("dry-run", false, "Print the URLs that would be checked without making any check requests")
+	accessLog := flag.String("access-log", "", "Access log in Common Log Format used to find sitemap URLs that were never crawled")
+	sinceDays := flag.Int("since-days", 30, "Only consider access log entries from the last N days (0 for all, used with -access-log)")
+	checkWWWFlag := flag.Bool("check-www", false, "Verify each domain serves content on only one of its www and non-www variants")
+	checkWWWCanonicalFlag := flag.Bool("check-www-canonical", false, "Flag domains whose www and non-www variants both answer 200 unless the variant not in the sitemap is excluded from indexing")
+	checkHSTS := flag.Bool("check-hsts", false, "Verify the root URL of each host sends a Strict-Transport-Security header")
+	checkHSTSPreload := flag.Bool("check-hsts-preload", false, "Like -check-hsts, also verify the header meets the HSTS preload list requirements")
+	proxyURL := flag.String("proxy", "", "Proxy URL for all requests (http://, https:// or socks5://)")
+	flag.StringVar(&ftpUser, "ftp-user", "", "Username for ftp:// sitemap URLs (default: anonymous)")
+	flag.StringVar(&ftpPass, "ftp-pass", "", "Password for ftp:// sitemap URLs")
+	flag.IntVar(&maxSitemapFiles, "max-sitemap-files", maxSitemapFiles, "Maximum number of child sitemaps processed per sitemap index (0 for no limit)")
+	flag.IntVar(&sitemapFetchConcurrency, "sitemap-fetch-concurrency", sitemapFetchConcurrency, "Number of child sitemaps of a sitemap index fetched in parallel")
+	flag.BoolVar(&validateNamespace, "validate-namespace", false, "Flag sitemap files with a missing or wrong sitemap, image, video or news namespace as INVALID_NAMESPACE")
+	progressPath := flag.String("progress-file", "", "Record checked URLs in this file and skip them when resuming an interrupted run")
+	group404 := flag.Bool("group-404-patterns", false, "Group 404 URLs by path template (numbers, UUIDs and dates replaced) in the summary")
+	webhookURL := flag.String("webhook-url", "", "POST broken and redirected URLs to this webhook as they are found")
+	webhookBatchSize := flag.Int("webhook-batch-size", 1, "Number of URLs sent per webhook request (used with -webhook-url)")
+	var failThreshold FailThreshold
+	flag.Float64Var(&failThreshold.Pct, "fail-threshold-pct", 0, "Exit with status 1 when more than this percentage of URLs is broken (0 fails on any broken URL)")
+	flag.IntVar(&failThreshold.Abs, "fail-threshold-abs", -1, "Exit with status 1 when more than this number of URLs is broken")
+	jsonReport := flag.String("json-report", "", "Write the results as JSON to this file")
+	baselineReport := flag.String("baseline-report", "", "JSON report of an earlier run to compare response times against")
+	regressionFactor := flag.Float64("regression-factor", 2.0, "Flag URLs responding this many times slower than in -baseline-report")
+	timeBudgetSpec := flag.String("time-budget", "", `JSON object of URL patterns to response time budgets, e.g. {"*/api/*": "200ms"}; flag URLs slower than the budget of their longest matching pattern`)
+	checkRedirectTargets := flag.Bool("check-redirect-target-in-sitemap", false, "Flag redirecting URLs whose target is also listed in the sitemap")
+	submit := flag.Bool("submit", false, "Submit the sitemap to Google and Bing after the check")
+	submitOnlyOnClean := flag.Bool("submit-only-on-clean", true, "Only submit when the check passed the fail threshold, set to false to submit anyway (used with -submit)")
+	googleOnly := flag.Bool("google-only", false, "Only submit the sitemap to Google (used with -submit)")
+	bingOnly := flag.Bool("bing-only", false, "Only submit the sitemap to Bing (used with -submit)")
+	checkRenderingFlag := flag.Bool("check-rendering", false, "Load 200 pages in headless Chrome and record their DOMContentLoaded and load times (requires -tags headless)")
+	pathPrefixReport := flag.Bool("path-prefix-report", false, "Print the URL count and error rate of each path prefix, highest error rate first")
+	prefixDepth := flag.Int("prefix-depth", 2, "Number of path segments grouped together (used with -path-prefix-report)")
+	reportDomains := flag.Bool("report-domains", false, "List the hostnames of the checked URLs, and of redirect targets with -follow-redirects, with their URL counts")
+	htmlReportPath := flag.String("html", "", "Write an HTML report with a response time heatmap to this file")
+	junitReport := flag.String("junit", "", "Write the results as JUnit XML to this file for CI systems")
+	outputSQL := flag.String("output-sql", "", "Write the results as SQL INSERT statements to this file")
+	sqlDialect := flag.String("sql-dialect", sqlDialectStandard, "String escaping of -output-sql: standard (PostgreSQL, SQLite) or mysql")
+	outputCurl := flag.String("output-curl", "", "Write a Makefile with a curl command per problematic URL to this file")
+	otelEndpoint := flag.String("otel-endpoint", "", "Export traces to this OpenTelemetry collector (OTLP gRPC on port 4317, HTTP otherwise)")
+	runIDFile := flag.String("run-id-file", "", "Write the run ID prefixed to every log line to this file")
+	oauth2TokenURL := flag.String("oauth2-token-url", "", "OAuth2 token endpoint for the client credentials flow")
+	oauth2ClientID := flag.String("oauth2-client-id", "", "OAuth2 client ID (used with -oauth2-token-url)")
+	oauth2ClientSecret := flag.String("oauth2-client-secret", "", "OAuth2 client secret (used with -oauth2-token-url)")
+	oauth2Hosts := flag.String("oauth2-hosts", "", "Comma-separated extra hosts that receive the OAuth2 token besides the sitemap host")
+	printConfigFlag := flag.Bool("print-config", false, "Print the effective configuration as JSON to stderr before running")
+	printConfigOnly := flag.Bool("print-config-only", false, "Exit after printing the configuration (used with -print-config)")
+
+	var opts CheckOptions
+	flag.BoolVar(&opts.VerifyGet, "verify-get", false, "Repeat successful HEAD checks with GET and flag differing status codes")
+	flag.BoolVar(&opts.CheckHTTPToHTTPS, "check-http-to-https", false, "Verify every http:// URL redirects with a 301 to its https:// equivalent")
+	flag.BoolVar(&opts.CheckImages, "check-images", false, "Check image:loc URLs from image sitemaps alongside their page")
+	flag.BoolVar(&opts.CheckVary, "check-vary", false, "Verify compressed responses send Vary: Accept-Encoding")
+	flag.BoolVar(&opts.CloakingCheck, "cloaking-check", false, "Request each URL again as Googlebot and flag differing responses")
+	flag.BoolVar(&opts.BodyCheck, "body-check", false, "Compare response bodies of 200 pages in content checks such as -cloaking-check")
+	flag.BoolVar(&opts.CheckUTF8, "check-utf8", false, "Verify HTML bodies are valid UTF-8 and declare charset=utf-8")
+	flag.Int64Var(&bodyReadLimit, "body-read-limit", bodyReadLimit, "Maximum number of body bytes read for content checks")
+	flag.IntVar(&opts.MaxConcurrentDomains, "max-concurrent-domains", 5, "Largest number of different domains requested at the same time (0 disables)")
+	flag.DurationVar(&opts.PerHostTimeout, "per-host-timeout", 0, "Stop checking a host once this much time has passed since its first request, e.g. 5m (0 disables)")
+	flag.BoolVar(&opts.CheckServerHeader, "check-server-header", false, "Record the Server response header and report the server software found")
+	flag.BoolVar(&opts.FlagServerDisclosure, "flag-server-disclosure", false, "Flag Server headers that reveal a version number")
+	flag.BoolVar(&opts.UseCache, "cache", false, "Reuse the result of URLs already checked instead of requesting them again")
+	flag.BoolVar(&opts.Verbose, "v", false, "Verbose logging")
+	flag.BoolVar(&opts.TUI, "tui", false, "Show a live dashboard of the latest results, counters and remaining time while checking")
+	flag.BoolVar(&opts.CheckOGImage, "check-og-image", false, "Verify og:image URLs are reachable images of at least 200x200 pixels")
+	flag.BoolVar(&opts.CheckXPoweredBy, "check-x-powered-by", false, "Record the X-Powered-By header and flag values revealing a version number")
+	allowedPoweredBy := flag.String("allowed-powered-by", "", "Regular expression of X-Powered-By values not to flag (used with -check-x-powered-by)")
+	flag.BoolVar(&opts.CheckCache, "check-cache", false, "Verify static assets (images, scripts, stylesheets) send caching headers")
+	flag.BoolVar(&opts.CheckETag, "check-etag", false, "Repeat 200 responses with If-None-Match/If-Modified-Since and flag pages not answering 304")
+	flag.BoolVar(&opts.CheckHTTP10, "check-http10", false, "Record the HTTP version of each response and flag URLs served over HTTP/1.0")
+	flag.BoolVar(&opts.CheckXCTO, "check-xcto", false, "Flag 200 responses without X-Content-Type-Options: nosniff")
+	flag.BoolVar(&opts.CheckAMP, "check-amp", false, "Verify rel=amphtml pages are reachable and point back with rel=canonical")
+	flag.BoolVar(&opts.CheckCSP, "check-csp", false, "Flag 200 responses with a missing Content-Security-Policy or one allowing unsafe-inline/unsafe-eval")
+	flag.BoolVar(&opts.HreflangTargets, "check-hreflang-targets", false, "Also check the alternate language URLs that pages link to with hreflang")
+	flag.BoolVar(&opts.BackoffOnTimeout, "backoff-on-timeout", false, "Retry timed out requests with exponential backoff and a doubled timeout")
+	flag.IntVar(&opts.MaxRetries, "max-retries", 3, "Maximum number of retries per request (used with -backoff-on-timeout)")
+	flag.BoolVar(&opts.CheckJSONLD, "check-json-ld", false, "Validate the JSON-LD structured data of 200 pages and flag invalid blocks")
+	flag.StringVar(&opts.RequireJSONLDType, "require-json-ld-type", "", "Flag pages without a JSON-LD block of this @type, e.g. Product (used with -check-json-ld)")
+	flag.BoolVar(&opts.FollowRedirects, "follow-redirects", false, "Follow the redirects of each redirecting URL and record the full chain")
+	flag.IntVar(&opts.MaxRedirectChain, "max-redirect-chain", 3, "Flag redirect chains with more hops than this (used with -follow-redirects)")
+	flag.BoolVar(&opts.CheckTitle, "check-title", false, "Record the <title> of 200 pages and flag pages without one")
+	flag.BoolVar(&opts.CheckTitleLength, "check-title-length", false, "Flag titles shorter than -title-min or longer than -title-max characters (used with -check-title)")
+	flag.IntVar(&opts.TitleMin, "title-min", 30, "Minimum title length in characters (used with -check-title-length)")
+	flag.IntVar(&opts.TitleMax, "title-max", 60, "Maximum title length in characters (used with -check-title-length)")
+	flag.BoolVar(&opts.CheckLinkHeader, "check-link-header", false, "Verify the rel=preload targets of the Link header of 200 responses are reachable")
+	flag.BoolVar(&opts.CheckMaxAge, "check-max-age", false, "Flag HTML pages whose Cache-Control max-age exceeds -max-html-age")
+	flag.IntVar(&opts.MaxHTMLAge, "max-html-age", 3600, "Longest Cache-Control max-age in seconds allowed on HTML pages (used with -check-max-age)")
+	flag.BoolVar(&opts.CheckNosniff, "check-nosniff", false, "Flag script, stylesheet and image responses without X-Content-Type-Options: nosniff")
+	flag.BoolVar(&opts.CheckFonts, "check-fonts", false, "Verify the web fonts referenced by url(...) in 200 CSS responses are reachable")
+	flag.BoolVar(&opts.CheckResourceHints, "check-resource-hints", false, "Resolve the hosts of rel=preconnect and rel=dns-prefetch links of 200 pages and flag DNS failures")
+	flag.BoolVar(&opts.CheckMetaDescription, "check-meta-desc", false, "Record the meta description of 200 pages and flag missing ones or ones outside 120-160 characters")
+	flag.BoolVar(&opts.CheckHTMLSize, "check-html-size", false, "Download 200 HTML pages, record their size and flag pages larger than -max-html-bytes")
+	flag.Int64Var(&opts.MaxHTMLBytes, "max-html-bytes", 1<<20, "Largest HTML page size in bytes (used with -check-html-size)")
+	flag.BoolVar(&opts.CheckSchema, "check-schema", false, "Flag structured data items of -required-schema-type on 200 pages that lack required properties")
+	flag.StringVar(&opts.RequiredSchemaType, "required-schema-type", "", "schema.org type whose items are verified, e.g. Product or Article (used with -check-schema)")
+	flag.BoolVar(&opts.CheckTLSVersion, "check-tls-version", false, "Record the TLS version of HTTPS responses and flag connections negotiated with TLS 1.0 or 1.1")
+	flag.BoolVar(&opts.CheckCompression, "check-compression", false, "Request gzip or br and flag text responses served uncompressed or with another encoding")
+	flag.BoolVar(&opts.CheckNoindex, "check-noindex", false, "Flag 200 pages excluded from indexing by X-Robots-Tag or meta robots noindex")
+	flag.BoolVar(&opts.CheckCanonical, "check-canonical", false, "Record the canonical URL of 200 pages and flag canonicals pointing to another URL")
+	checkRobotsFlag := flag.Bool("check-robots", false, "Flag URLs disallowed for all user agents by their host's robots.txt")
+	flag.BoolVar(&opts.CheckIndexability, "check-indexability", false, "Combine -check-noindex, -check-canonical and -check-robots with the status to report which URLs are indexable")
+	flag.BoolVar(&opts.HreflangComplete, "check-hreflang-complete", false, "Flag sitemap pages that an alternate language page links to with hreflang but that don't link back")
+	checkMobileFriendlyFlag := flag.Bool("check-mobile-friendly", false, "Run Google's Mobile-Friendly Test on 200 pages and flag the ones that fail (requires -google-api-key)")
+	googleAPIKey := flag.String("google-api-key", "", "Google API key for the Mobile-Friendly Test API (used with -check-mobile-friendly)")
+	flag.BoolVar(&opts.CheckCanonRedirect, "check-canon-redirect", false, "Flag redirecting URLs that declare themselves canonical or whose target declares another canonical (requires -check-canonical and -follow-redirects)")
+	flag.BoolVar(&opts.CheckCustom404, "check-custom-404", false, "Flag 404 responses serving the web server's default error page instead of a custom one")
+	flag.BoolVar(&opts.CheckCSS, "check-css", false, "HEAD-check the stylesheets linked from 200 HTML pages and flag the ones that fail to load")
+	flag.BoolVar(&opts.CheckJS, "check-js", false, "HEAD-check the external scripts of 200 HTML pages and flag the ones that fail to load")
+	flag.BoolVar(&opts.CheckHTTPVersion, "check-http-version-consistency", false, "Flag assets of 200 HTTP/2 HTML pages that are served over HTTP/1.x")
+	flag.BoolVar(&opts.CheckMixedContent, "check-mixed-content", false, "Flag 200 HTTPS HTML pages that load images, scripts, stylesheets or iframes over HTTP")
+	flag.BoolVar(&opts.CheckFavicon, "check-favicon", false, "Verify every host serves /favicon.ico as an image or a working rel=icon, and that all rel=icon links are reachable")
+	flag.BoolVar(&opts.CheckLangTag, "check-lang-tag", false, "Flag 200 HTML pages whose <html> element has a missing or invalid BCP 47 lang attribute")
+	flag.BoolVar(&opts.CheckExternalLinks, "check-external-links", false, "HEAD-check the links of 200 HTML pages to other hosts and flag the pages linking to broken ones")
+	extLinkConcurrency := flag.Int("ext-link-concurrency", 5, "Number of parallel requests for external links (used with -check-external-links)")
+	flag.BoolVar(&opts.CheckInternalLinks, "check-internal-links", false, "HEAD-check the same-host links of 200 HTML pages and flag the ones missing from the sitemap or broken")
+	flag.BoolVar(&opts.CheckWordCount, "check-word-count", false, "Flag 200 HTML pages with fewer than -min-words words of visible text")
+	flag.IntVar(&opts.MinWords, "min-words", 300, "Fewest words of visible text on a page (used with -check-word-count)")
+	flag.BoolVar(&opts.CheckExpires, "check-expires", false, "Flag 200 responses whose Expires date has passed or that send neither Expires nor Cache-Control")
+	flag.BoolVar(&opts.CheckA11y, "check-a11y", false, "Run basic accessibility checks on 200 HTML pages: image alt text, form labels and the lang attribute")
+	flag.BoolVar(&opts.CheckInPageAnchors, "check-in-page-anchors", false, "Flag <a href=\"#...\"> links on 200 HTML pages whose target id or name is not on the page")
+	flag.BoolVar(&opts.CheckViewport, "check-viewport", false, "Flag 200 HTML pages without a viewport meta tag or whose viewport disables zooming")
+	flag.BoolVar(&opts.CheckPagination, "check-pagination", false, "Follow rel=next/prev links of 200 pages and verify the pagination chain")
+	flag.BoolVar(&opts.CheckPaginationFull, "check-pagination-full", false, "Like -check-pagination, also verify rel=first/last of every page point to the ends of the chain")
+	flag.BoolVar(&opts.PaginationCanonical, "check-pagination-canonical", false, "Like -check-pagination, also verify each page's canonical is itself or the first page of the chain")
 
 	flag.Parse()
 
+	// An absolute threshold on its own replaces the default of failing on any broken URL
+	pctSet := false
+	flag.Visit(func(f *flag.Flag) { pctSet = pctSet || f.Name == "fail-threshold-pct" })
+	if failThreshold.Abs >= 0 && !pctSet {
+		failThreshold.Pct = -1
+	}
+
+	// Show the resolved flag values before any request is made, credentials are redacted
+	if *printConfigFlag {
+		if err := printConfig(os.Stderr, flag.CommandLine); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			osExit(1)
+			return
+		}
+		if *printConfigOnly {
+			osExit(0)
+			return
+		}
+	}
+
+	if *allowedPoweredBy != "" {
+		pattern, err := regexp.Compile(*allowedPoweredBy)
+		if err != nil {
+			fmt.Printf("Error: Invalid -allowed-powered-by pattern: %v\n", err)
+			osExit(1)
+			return
+		}
+		opts.AllowedPoweredBy = pattern
+	}
+
+	if opts.CheckIndexability && !(opts.CheckNoindex && opts.CheckCanonical && *checkRobotsFlag) {
+		fmt.Println("Error: -check-indexability requires -check-noindex, -check-canonical and -check-robots")
+		osExit(1)
+		return
+	}
+
+	if opts.CheckCanonRedirect && !(opts.CheckCanonical && opts.FollowRedirects) {
+		fmt.Println("Error: -check-canon-redirect requires -check-canonical and -follow-redirects")
+		osExit(1)
+		return
+	}
+
+	if *checkMobileFriendlyFlag && *googleAPIKey == "" {
+		fmt.Println("Error: -check-mobile-friendly requires -google-api-key")
+		osExit(1)
+		return
+	}
+
+	var timeBudgets []TimeBudget
+	if *timeBudgetSpec != "" {
+		var err error
+		timeBudgets, err = parseTimeBudgets(*timeBudgetSpec)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			osExit(1)
+			return
+		}
+	}
+
+	if *googleOnly && *bingOnly {
+		fmt.Println("Error: -google-only and -bing-only can't be used together")
+		osExit(1)
+		return
+	}
+
+	if *prefixDepth < 1 {
+		fmt.Println("Error: -prefix-depth must be at least 1")
+		osExit(1)
+		return
+	}
+
+	if !validSQLDialect(*sqlDialect) {
+		fmt.Printf("Error: Invalid -sql-dialect %q, use standard or mysql\n", *sqlDialect)
+		osExit(1)
+		return
+	}
+
+	if opts.CheckSchema && opts.RequiredSchemaType == "" {
+		fmt.Println("Error: -check-schema requires -required-schema-type")
+		osExit(1)
+		return
+	}
+
+	// URLs from a CSV file replace the sitemap
+	var csvURLs []URL
+	if *csvInput != "" {
+		var err error
+		csvURLs, err = loadCSVURLs(*csvInput, *urlColumn, *csvHeader)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			osExit(1)
+			return
+		}
+	}
+
 	// Check if sitemap URL is provided
-	if *sitemapURL == "" {
+	if *sitemapURL == "" && *csvInput == "" {
 		fmt.Println("Error: Sitemap URL is required. Use -u flag to specify the URL.")
 		flag.Usage()
 		osExit(1)
 	}
 
+	// Name the log after the sitemap's host, or the first CSV URL's without a sitemap
+	logSource := *sitemapURL
+	if logSource == "" && len(csvURLs) > 0 {
+		logSource = csvURLs[0].Loc
+	}
+
+	startTime := time.Now()
+
+	// Tag log lines with a run ID so concurrent runs can be told apart
+	runID, err := newRunID()
+	if err != nil {
+		fmt.Printf("Warning: %v\n", err)
+	} else {
+		fmt.Printf("Run ID: %s\n", runID)
+		if *runIDFile != "" {
+			if err := os.WriteFile(*runIDFile, []byte(runID+"\n"), 0644); err != nil {
+				fmt.Printf("Warning: Failed to write run ID file: %v\n", err)
+			}
+		}
+	}
+
 	// Create log filename with format %hostname%-%date%-%time%.log
-	logFilename, err := createLogFilename(*sitemapURL)
+	logFilename, err := createLogFilename(logSource)
 	if err != nil {
 		fmt.Printf("Warning: Failed to create log filename: %v. Using default filename.\n", err)
 		logFilename = "sitemap-check.log"
@@ -218,14 +723,15 @@ func main() {
 		fmt.Printf("Warning: Failed to create logger: %v. Proceeding without logging.\n", err)
 	} else {
 		defer logger.Close()
+		logger.runID = runID
 		fmt.Printf("Logging to: %s\n", logFilename)
 
 		// Write header to log file
-		parsedURL, err := url.Parse(*sitemapURL)
+		parsedURL, err := url.Parse(logSource)
 		if err == nil {
 			logger.Log(fmt.Sprintf("Sitemap check for: %s", parsedURL.Host))
 		}
-		logger.Log(fmt.Sprintf("Started at: %s", time.Now().Format(time.RFC3339)))
+		logger.Log(fmt.Sprintf("Started at: %s", startTime.Format(time.RFC3339)))
 		logger.Log(fmt.Sprintf("Concurrency: %d parallel requests", *concurrency))
 		if *insecure {
 			logger.Log("SSL certificate validation: DISABLED")
@@ -240,25 +746,70 @@ func main() {
 		fmt.Println("Warning: SSL certificate validation is disabled")
 	}
 
+	// A transport with a custom TLS config only speaks HTTP/2 when asked to
+	if opts.CheckHTTPVersion {
+		transport.ForceAttemptHTTP2 = true
+	}
+
+	// Go refuses TLS 1.0 and 1.1 by default, accept them so servers limited to them can be reported
+	if opts.CheckTLSVersion {
+		if transport.TLSClientConfig == nil {
+			transport.TLSClientConfig = &tls.Config{}
+		}
+		transport.TLSClientConfig.MinVersion = tls.VersionTLS10
+	}
+
+	// Route requests through a proxy, the scheme selects HTTP or SOCKS5
+	if *proxyURL != "" {
+		if err := configureProxy(transport, *proxyURL); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			if logger != nil {
+				logger.Log(fmt.Sprintf("Error: %v", err))
+			}
+			osExit(1)
+			return
+		}
+	}
+
+	// Authenticate requests to the checked site with short-lived tokens that are refreshed automatically
+	var roundTripper http.RoundTripper = transport
+	if *oauth2TokenURL != "" {
+		oauthHosts := append(uniqueHosts(append([]string{*sitemapURL}, urlLocs(csvURLs)...)), splitList(*oauth2Hosts)...)
+		roundTripper = NewOAuth2Transport(*oauth2TokenURL, *oauth2ClientID, *oauth2ClientSecret, oauthHosts, transport, logger)
+		if logger != nil {
+			logger.Log("OAuth2 client credentials: ENABLED")
+		}
+	}
+
 	// Create HTTP client with CheckRedirect to prevent following redirects
 	client := &http.Client{
 		Timeout:   30 * time.Second,
-		Transport: transport,
+		Transport: roundTripper,
 		CheckRedirect: func(req *http.Request, via []*http.Request) error {
 			// Don't follow redirects - instead return an error to capture the redirect
 			return http.ErrUseLastResponse
 		},
 	}
 
+	// Third-party services and off-site links never get the OAuth2 token
+	plainClient := *client
+	plainClient.Transport = transport
+
 	// Retrieve and process the sitemap
-	fmt.Println("Retrieving URLs from sitemap...")
-	allURLs, err := retrieveAllURLs(client, *sitemapURL, *insecure)
-	if err != nil {
-		fmt.Printf("Error retrieving URLs: %v\n", err)
-		if logger != nil {
-			logger.Log(fmt.Sprintf("Error retrieving URLs: %v", err))
+	var allURLs []URL
+	if *csvInput != "" {
+		fmt.Printf("Read %d URLs from %s\n", len(csvURLs), *csvInput)
+		allURLs = csvURLs
+	} else {
+		fmt.Println("Retrieving URLs from sitemap...")
+		allURLs, err = retrieveAllURLs(client, *sitemapURL, *insecure)
+		if err != nil {
+			fmt.Printf("Error retrieving URLs: %v\n", err)
+			if logger != nil {
+				logger.Log(fmt.Sprintf("Error retrieving URLs: %v", err))
+			}
+			osExit(1)
 		}
-		osExit(1)
 	}
 
 	fmt.Printf("Found %d URLs to check\n", len(allURLs))
@@ -266,14 +817,422 @@ func main() {
 		logger.Log(fmt.Sprintf("Found %d URLs to check", len(allURLs)))
 	}
 
+	// Namespace problems were printed while the sitemaps were retrieved, before logging them was possible
+	namespaceIssues := recordedNamespaceProblems()
+	if logger != nil {
+		for _, p := range namespaceIssues {
+			logger.Log(fmt.Sprintf("INVALID_NAMESPACE: %s - %s", p.Sitemap, p.Problem))
+		}
+	}
+
+	// A URL without a scheme can't be requested, it is only checked when it is fixed
+	if *checkProtocolRelative || *fixProtocolRelative {
+		relativeCount := 0
+		for i := range allURLs {
+			if !isProtocolRelative(allURLs[i].Loc) {
+				continue
+			}
+			relativeCount++
+			loc := strings.TrimSpace(allURLs[i].Loc)
+			allURLs[i].addIssue("PROTOCOL_RELATIVE_URL", "URL has no scheme")
+			if logger != nil {
+				logger.Log(fmt.Sprintf("PROTOCOL_RELATIVE_URL: %s", loc) + sourceTag(allURLs[i].Source))
+			}
+			if *fixProtocolRelative {
+				allURLs[i].Original = allURLs[i].Loc
+				allURLs[i].Loc = *defaultScheme + ":" + loc
+				if logger != nil {
+					logger.Log(fmt.Sprintf("Rewrote protocol-relative URL: %s -> %s", loc, allURLs[i].Loc))
+				}
+			}
+		}
+		if relativeCount > 0 {
+			fmt.Printf("Warning: %d protocol-relative URLs in sitemap\n", relativeCount)
+		}
+	}
+
+	// UTM parameters in a sitemap are a mistake, so each URL carrying them is reported
+	if *stripUTM {
+		var changed []URLRewrite
+		allURLs, changed = rewriteURLs(allURLs, func(u string) string { return stripQueryParams(u, utmParams) })
+		for _, rewrite := range changed {
+			msg := fmt.Sprintf("Warning: UTM parameters in sitemap URL: %s -> %s", rewrite.From, rewrite.To)
+			fmt.Println(msg)
+			if logger != nil {
+				logger.Log(msg)
+			}
+		}
+	}
+
+	// Strip tracking parameters so URLs differing only by them are checked once
+	if params := splitList(*ignoreParams); len(params) > 0 {
+		var changed []URLRewrite
+		allURLs, changed = rewriteURLs(allURLs, func(u string) string { return stripQueryParams(u, params) })
+		for _, rewrite := range changed {
+			if logger != nil {
+				logger.Log(fmt.Sprintf("Stripped parameters: %s -> %s", rewrite.From, rewrite.To))
+			}
+		}
+		fmt.Printf("Stripped query parameters from %d URLs, %d URLs left to check\n", len(changed), len(allURLs))
+	}
+
+	// Variants of the same URL waste crawl budget, only the first form listed is checked
+	if *dedupCase || *dedupScheme {
+		var duplicates []URLDuplicate
+		allURLs, duplicates = dedupSimilarURLs(allURLs, *dedupCase, *dedupScheme)
+		for _, d := range duplicates {
+			if logger != nil {
+				logger.Log(fmt.Sprintf("%s: %s - duplicate of %s", d.Code, d.Variant, d.Canonical))
+			}
+		}
+		fmt.Printf("Dropped %d duplicate URL variants, %d URLs left to check\n", len(duplicates), len(allURLs))
+	}
+
+	// IP address hosts usually mean a staging sitemap slipped into production, the URLs are still checked
+	if *checkIPURLs {
+		for i := range allURLs {
+			if !isIPAddressURL(allURLs[i].Loc) {
+				continue
+			}
+			allURLs[i].addIssue("IP_ADDRESS_URL", "host is an IP address instead of a hostname")
+			if logger != nil {
+				logger.Log(fmt.Sprintf("IP_ADDRESS_URL: %s", allURLs[i].Loc) + sourceTag(allURLs[i].Source))
+			}
+		}
+	}
+
+	// Overly long URLs are linted before any request, they are still checked
+	var urlLengths []int
+	if *checkURLLengthFlag {
+		for i := range allURLs {
+			urlLengths = append(urlLengths, len(allURLs[i].Loc))
+			checkURLLength(&allURLs[i], *maxURLLength, *maxQueryLength)
+			if logger == nil {
+				continue
+			}
+			for _, issue := range allURLs[i].Issues {
+				if issue.Code == "URL_TOO_LONG" || issue.Code == "QUERY_TOO_LONG" {
+					logger.Log(fmt.Sprintf("%s: %s - %s", issue.Code, allURLs[i].Loc, issue.Message) + sourceTag(allURLs[i].Source))
+				}
+			}
+		}
+	}
+
+	// Enforce the organisation's URL policy, URLs outside it are still checked
+	if *urlAllowlist != "" {
+		patterns, err := loadAllowlist(*urlAllowlist)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			if logger != nil {
+				logger.Log(fmt.Sprintf("Error: %v", err))
+			}
+			osExit(1)
+			return
+		}
+		for i := range allURLs {
+			if matchesAllowlist(allURLs[i].Loc, patterns) {
+				continue
+			}
+			allURLs[i].addIssue("URL_NOT_IN_ALLOWLIST", "URL matches no allowlist pattern")
+			if logger != nil {
+				logger.Log(fmt.Sprintf("URL_NOT_IN_ALLOWLIST: %s", allURLs[i].Loc) + sourceTag(allURLs[i].Source))
+			}
+		}
+	}
+
+	// Flag URLs that are not in the form the WHATWG URL standard requires
+	// Non-compliant URLs are still checked, the issue is reported next to their status
+	if *checkCompliance {
+		for i := range allURLs {
+			problems := checkURLCompliance(allURLs[i].Loc)
+			if len(problems) == 0 {
+				continue
+			}
+			msg := strings.Join(problems, "; ")
+			allURLs[i].addIssue("URL_NOT_COMPLIANT", msg)
+			if logger != nil {
+				logger.Log(fmt.Sprintf("URL_NOT_COMPLIANT: %s - %s", allURLs[i].Loc, msg) + sourceTag(allURLs[i].Source))
+			}
+		}
+	}
+
+	// Check the canonical form of each URL, flagging URLs that weren't canonical
+	if *normalizeURLs {
+		for i := range allURLs {
+			normalized := normalizeURL(allURLs[i].Loc)
+			if normalized == allURLs[i].Loc {
+				continue
+			}
+			allURLs[i].Original = allURLs[i].Loc
+			allURLs[i].Loc = normalized
+			allURLs[i].addIssue("URL_NEEDS_NORMALIZATION", fmt.Sprintf("normalized from %s", allURLs[i].Original))
+			if logger != nil {
+				logger.Log(fmt.Sprintf("URL_NEEDS_NORMALIZATION: %s -> %s", allURLs[i].Original, normalized))
+			}
+		}
+	}
+
+	// Only keep the requested chunk when the list is split across several runs
+	if *chunkSize > 0 {
+		totalURLs := len(allURLs)
+		allURLs, err = chunkURLs(allURLs, *chunkSize, *chunkIndex)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			if logger != nil {
+				logger.Log(fmt.Sprintf("Error: %v", err))
+			}
+			osExit(1)
+			return
+		}
+
+		chunkCount := (totalURLs + *chunkSize - 1) / *chunkSize
+		start := *chunkIndex * *chunkSize
+		chunkMsg := fmt.Sprintf("Checking chunk %d of %d: URLs %d-%d of %d total",
+			*chunkIndex, chunkCount, start, start+len(allURLs)-1, totalURLs)
+		fmt.Println(chunkMsg)
+		if logger != nil {
+			logger.Log(chunkMsg)
+		}
+	}
+
+	// Resume an interrupted run by skipping the URLs it already checked
+	if *progressPath != "" {
+		done, err := loadProgress(*progressPath)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			if logger != nil {
+				logger.Log(fmt.Sprintf("Error: %v", err))
+			}
+			osExit(1)
+			return
+		}
+		if len(done) > 0 {
+			totalURLs := len(allURLs)
+			allURLs = skipCheckedURLs(allURLs, done)
+			resumeMsg := fmt.Sprintf("Resuming: skipping %d URLs already checked, %d remaining", totalURLs-len(allURLs), len(allURLs))
+			fmt.Println(resumeMsg)
+			if logger != nil {
+				logger.Log(resumeMsg)
+			}
+		}
+	}
+
+	// In dry-run mode only list what would be checked
+	if *dryRun {
+		for _, u := range allURLs {
+			fmt.Println(u.Loc)
+		}
+		dryRunMsg := fmt.Sprintf("Dry run: %d URLs would be checked", len(allURLs))
+		fmt.Println(dryRunMsg)
+		if logger != nil {
+			logger.Log(dryRunMsg)
+		}
+		return
+	}
+
+	// Resolve each host once up front instead of timing out on every URL of a dead domain
+	var dnsResults []Result
+	if *verifyDNSFlag {
+		hosts, failed := verifyDNS(allURLs)
+		allURLs, dnsResults = skipDNSFailures(allURLs, failed)
+		for _, line := range formatDNSReport(hosts, failed) {
+			fmt.Println(line)
+			if logger != nil {
+				logger.Log(line)
+			}
+		}
+	}
+
+	expiringDomains := 0
+	if *whoisCheck {
+		fmt.Println("Checking domain expiry dates...")
+		expiringDomains = checkDomainExpiry(NewWhoisChecker(), urlLocs(allURLs), *domainWarnDays, logger)
+	}
+
+	// Cross-reference the sitemap with the paths crawlers actually requested
+	neverCrawledCount := 0
+	if *accessLog != "" {
+		fmt.Println("Comparing sitemap with access log...")
+		neverCrawledCount, err = checkAccessLog(*accessLog, *sinceDays, allURLs, logger)
+		if err != nil {
+			fmt.Printf("Warning: %v\n", err)
+			if logger != nil {
+				logger.Log(fmt.Sprintf("Warning: %v", err))
+			}
+		}
+	}
+
+	var wwwSummary []string
+	if *checkWWWFlag {
+		fmt.Println("Checking www consistency...")
+		wwwSummary = checkWWWConsistency(client, urlLocs(allURLs), logger)
+	}
+
+	if *checkWWWCanonicalFlag {
+		fmt.Println("Checking www canonicalisation...")
+		wwwSummary = append(wwwSummary, checkWWWCanonical(client, urlLocs(allURLs), logger)...)
+	}
+
+	var hstsSummary []string
+	if *checkHSTS || *checkHSTSPreload {
+		fmt.Println("Checking HSTS headers...")
+		hstsSummary = checkHSTSDomains(client, urlLocs(allURLs), *checkHSTSPreload, logger)
+	}
+
+	if *progressPath != "" {
+		opts.Progress, err = OpenProgressFile(*progressPath)
+		if err != nil {
+			fmt.Printf("Warning: %v. Progress will not be saved.\n", err)
+		}
+	}
+
+	if *otelEndpoint != "" {
+		opts.Tracing, err = NewTracing(*otelEndpoint, *sitemapURL, runID)
+		if err != nil {
+			fmt.Printf("Warning: %v. Traces will not be exported.\n", err)
+		}
+	}
+
+	if *checkRenderingFlag {
+		opts.Renderer, err = NewRenderer()
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			if logger != nil {
+				logger.Log(fmt.Sprintf("Error: %v", err))
+			}
+			osExit(1)
+			return
+		}
+		defer opts.Renderer.Close()
+	}
+
+	if *checkRobotsFlag {
+		opts.Robots = NewRobotsChecker(client)
+	}
+
+	if *autoBackoff {
+		opts.Backoff = NewBackoffController(time.Duration(*timeout)*time.Millisecond, logger)
+	}
+
+	if opts.CheckCSS || opts.CheckJS {
+		opts.Assets = NewAssetChecker(client)
+	}
+
+	if *checkMobileFriendlyFlag {
+		opts.MobileFriendly = NewMobileFriendlyChecker(&plainClient, *googleAPIKey)
+	}
+
+	if opts.CheckPagination || opts.CheckPaginationFull || opts.PaginationCanonical {
+		opts.Pagination = NewPaginationWalker()
+	}
+
+	var baseline JSONReport
+	if *baselineReport != "" {
+		baseline, err = loadJSONReport(*baselineReport)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			if logger != nil {
+				logger.Log(fmt.Sprintf("Error: %v", err))
+			}
+			osExit(1)
+			return
+		}
+	}
+
+	if *webhookURL != "" {
+		opts.Webhook = NewWebhookNotifier(*webhookURL, runID, *webhookBatchSize, transport, logger)
+	}
+
 	fmt.Println("Checking URLs...")
 
 	// Check all URLs with progress bar and logger
-	results := checkURLs(client, allURLs, *timeout, *concurrency, logger)
+	results := checkURLs(client, allURLs, *timeout, *concurrency, logger, opts)
+	results = append(results, dnsResults...)
+
+	// Check the alternate language pages linked from the sitemap pages that aren't listed themselves
+	hreflangChecked, hreflangBroken := 0, 0
+	if opts.HreflangTargets {
+		if targets := hreflangTargetURLs(results); len(targets) > 0 {
+			fmt.Printf("Checking %d hreflang targets...\n", len(targets))
+			targetResults := checkURLs(client, targets, *timeout, *concurrency, logger, opts.requestOnly())
+			hreflangChecked = len(targets)
+			hreflangBroken = markHreflangTargets(targetResults, logger)
+			results = append(results, targetResults...)
+		}
+	}
+
+	// Every page's hreflang links are known now, so missing return links can be found
+	hreflangMissingReturn := 0
+	if opts.HreflangComplete {
+		hreflangMissingReturn = checkHreflangReturnLinks(results, logger)
+	}
+
+	// Internal links are compared with the whole sitemap, so they are checked once it is done
+	internalChecked, internalBroken, internalNotInSitemap := 0, 0, 0
+	if opts.CheckInternalLinks {
+		fmt.Println("Checking internal links...")
+		throttle := linkThrottle{delay: time.Duration(*timeout) * time.Millisecond, backoff: opts.Backoff}
+		if opts.MaxConcurrentDomains > 0 {
+			throttle.domains = NewDomainLimiter(opts.MaxConcurrentDomains)
+		}
+		internalChecked, internalBroken, internalNotInSitemap = checkInternalLinks(NewAssetChecker(client), results, allURLs, *concurrency, throttle, logger)
+	}
+
+	// External links are checked after the sitemap, with their own concurrency
+	externalChecked, externalBroken := 0, 0
+	if opts.CheckExternalLinks {
+		fmt.Println("Checking external links...")
+		var throttle linkThrottle
+		if opts.MaxConcurrentDomains > 0 {
+			throttle.domains = NewDomainLimiter(opts.MaxConcurrentDomains)
+		}
+		externalChecked, externalBroken = checkExternalLinks(NewAssetChecker(&plainClient), results, *extLinkConcurrency, throttle, logger)
+	}
+
+	// The icons declared by the checked pages are known now, so each host's favicon can be judged
+	var faviconStatuses []FaviconStatus
+	if opts.CheckFavicon {
+		fmt.Println("Checking favicons...")
+		faviconStatuses = checkFavicons(client, results, logger)
+	}
+
+	// Deliver the webhook events still queued before reporting
+	opts.Webhook.Close()
+
+	// The run completed, so there is nothing left to resume
+	if err := opts.Progress.Remove(); err != nil {
+		fmt.Printf("Warning: Failed to remove progress file: %v\n", err)
+	}
+
+	// Compare response times with the baseline run, flagging regressions before reporting
+	var regressions []Regression
+	if *baselineReport != "" {
+		regressions = compareResponseTimes(baseline, results, *regressionFactor)
+		if logger != nil {
+			for _, r := range regressions {
+				logger.Log(fmt.Sprintf("RESPONSE_TIME_REGRESSION: %s - %v -> %v", r.URL, r.Baseline, r.Current))
+			}
+		}
+	}
+
+	// Hold every URL to the response time budget of its page type
+	overBudget := 0
+	if len(timeBudgets) > 0 {
+		overBudget = checkTimeBudgets(results, timeBudgets, logger)
+	}
+
+	// A redirecting entry is redundant when its target is listed too
+	redundantRedirects := 0
+	if *checkRedirectTargets {
+		redundantRedirects = flagRedundantRedirects(results, logger)
+	}
 
 	// Print problematic URLs
 	problematicCount := 0
 	redirectCount := 0
+	issueCount := 0
+	imageCount := 0
+	brokenImageCount := 0
 
 	for _, result := range results {
 		if result.Error != nil || result.Status < 200 || result.Status >= 300 {
@@ -288,29 +1247,327 @@ func main() {
 				fmt.Printf("INVALID STATUS: %s - %d\n", result.URL, result.Status)
 			}
 		}
+
+		if opts.CheckImages && result.Error == nil && result.Status == http.StatusOK {
+			imageCount += len(result.Images)
+			brokenImageCount += len(result.BrokenImages)
+		}
+
+		if len(result.Issues) > 0 {
+			issueCount++
+			for _, issue := range result.Issues {
+				fmt.Printf("%s: %s - %s\n", issue.Code, result.URL, issue.Message)
+			}
+		}
 	}
 
 	// Log and print summary
 	summaryMsg := fmt.Sprintf("\nSummary: Found %d problematic URLs out of %d total URLs", problematicCount, len(results))
 	redirectMsg := fmt.Sprintf("Redirects: %d URLs", redirectCount)
+	brokenCount := countBroken(results)
+	errorMsg := fmt.Sprintf("Broken URLs: %d (%.2f%%)", brokenCount, errorPct(brokenCount, len(results)))
+
+	// Additional summary lines for the optional checks
+	summary := []string{fmt.Sprintf("URLs with check issues: %d", issueCount)}
+	if opts.CheckImages {
+		summary = append(summary, fmt.Sprintf("Images: %d checked, %d broken", imageCount, brokenImageCount))
+	}
+	if opts.CheckVary {
+		summary = append(summary, fmt.Sprintf("Missing Vary headers: %d URLs", countIssues(results, "MISSING_VARY")))
+	}
+
+	summary = append(summary, wwwSummary...)
+	summary = append(summary, hstsSummary...)
+	if opts.UseCache {
+		cacheHits := 0
+		for _, result := range results {
+			if result.FromCache {
+				cacheHits++
+			}
+		}
+		hitRate := 0.0
+		if len(results) > 0 {
+			hitRate = float64(cacheHits) / float64(len(results)) * 100
+		}
+		summary = append(summary, fmt.Sprintf("Cache hits: %d of %d results (%.1f%%)", cacheHits, len(results), hitRate))
+	}
+	if *whoisCheck {
+		summary = append(summary, fmt.Sprintf("Domains expiring within %d days: %d", *domainWarnDays, expiringDomains))
+	}
+	if *accessLog != "" {
+		summary = append(summary, fmt.Sprintf("Never crawled: %d URLs", neverCrawledCount))
+	}
+	if opts.CheckServerHeader || opts.FlagServerDisclosure {
+		summary = append(summary, "Server software: "+formatValueCounts(results, func(r Result) string { return r.ServerHeader }))
+	}
+
+	if opts.CheckCompression {
+		summary = append(summary, formatCompressionSummary(results))
+	}
+	if opts.CheckTLSVersion {
+		summary = append(summary, "TLS versions: "+formatValueCounts(results, func(r Result) string { return r.TLSVersion }))
+	}
+	if opts.CheckHTTP10 {
+		summary = append(summary, fmt.Sprintf("HTTP/1.0 responses: %d URLs", countIssues(results, "OLD_PROTOCOL")))
+	}
+	if opts.CheckCache {
+		summary = append(summary, fmt.Sprintf("Uncached assets: %d URLs", countIssues(results, "NO_CACHE_HEADERS")))
+	}
+	if opts.CheckXPoweredBy {
+		summary = append(summary, "X-Powered-By: "+formatValueCounts(results, func(r Result) string { return r.XPoweredBy }))
+	}
+	if opts.CheckXCTO {
+		summary = append(summary, formatXCTOSummary(results))
+	}
+	if opts.CheckCSP {
+		summary = append(summary, formatCSPSummary(results))
+	}
+	if *urlAllowlist != "" {
+		summary = append(summary, fmt.Sprintf("URLs not in allowlist: %d", countIssues(results, "URL_NOT_IN_ALLOWLIST")))
+	}
+	if *checkURLLengthFlag {
+		summary = append(summary, formatURLLengthSummary(urlLengths),
+			fmt.Sprintf("URLs too long: %d, query strings too long: %d", countIssues(results, "URL_TOO_LONG"), countIssues(results, "QUERY_TOO_LONG")))
+	}
+	if opts.CheckCSS {
+		summary = append(summary, fmt.Sprintf("Pages with broken stylesheets: %d URLs", countIssues(results, "CSS_BROKEN")))
+	}
+	if opts.CheckJS {
+		summary = append(summary, formatBrokenJSSummary(results)...)
+	}
+	if opts.CheckHTTPVersion {
+		summary = append(summary, fmt.Sprintf("HTTP/2 pages with HTTP/1.x assets: %d URLs", countIssues(results, "HTTP_VERSION_MISMATCH")))
+	}
+	if len(timeBudgets) > 0 {
+		summary = append(summary, fmt.Sprintf("Over response time budget: %d URLs", overBudget))
+	}
+	if opts.CheckMixedContent {
+		summary = append(summary, formatMixedContentSummary(results)...)
+	}
+	if opts.CheckFavicon {
+		summary = append(summary, formatFaviconSummary(faviconStatuses)...)
+	}
+	if opts.CheckLangTag {
+		summary = append(summary, fmt.Sprintf("Missing lang: %d URLs, invalid lang: %d URLs", countIssues(results, "MISSING_LANG"), countIssues(results, "INVALID_LANG")))
+	}
+	if opts.CheckInternalLinks {
+		summary = append(summary, fmt.Sprintf("Internal links: %d checked, %d broken, %d not in sitemap", internalChecked, internalBroken, internalNotInSitemap))
+	}
+	if opts.CheckExternalLinks {
+		summary = append(summary, fmt.Sprintf("External links: %d checked, %d broken on %d URLs", externalChecked, externalBroken, countIssues(results, "BROKEN_EXTERNAL_LINK")))
+	}
+	if opts.CheckWordCount {
+		summary = append(summary, formatWordCountSummary(results),
+			fmt.Sprintf("Thin content: %d URLs", countIssues(results, "THIN_CONTENT")))
+	}
+	if opts.CheckExpires {
+		summary = append(summary, fmt.Sprintf("Expires: %d URLs already expired, %d URLs without Expires or Cache-Control",
+			countIssues(results, "ALREADY_EXPIRED"), countIssues(results, "NO_EXPIRY_HEADERS")))
+	}
+	if opts.CheckA11y {
+		issues, pages := countA11yIssues(results)
+		summary = append(summary, fmt.Sprintf("Accessibility: %d issues on %d URLs", issues, pages))
+	}
+	if opts.CheckInPageAnchors {
+		summary = append(summary, fmt.Sprintf("Broken in-page anchors: %d URLs", countIssues(results, "BROKEN_ANCHOR")))
+	}
+	if opts.CheckViewport {
+		summary = append(summary, fmt.Sprintf("Viewport: %d URLs missing, %d URLs restricting zoom",
+			countIssues(results, "MISSING_VIEWPORT"), countIssues(results, "VIEWPORT_RESTRICTS_ZOOM")))
+	}
+	if validateNamespace {
+		summary = append(summary, formatNamespaceSummary(namespaceIssues))
+	}
+	if opts.CheckCustom404 {
+		summary = append(summary, fmt.Sprintf("Default 404 pages: %d URLs", countIssues(results, "DEFAULT_404_PAGE")))
+	}
+	if *checkIPURLs {
+		summary = append(summary, fmt.Sprintf("IP address URLs: %d", countIssues(results, "IP_ADDRESS_URL")))
+	}
+	if opts.CheckResourceHints {
+		summary = append(summary, fmt.Sprintf("Unresolvable resource hints: %d URLs", countIssues(results, "RESOURCE_HINT_DNS_FAIL")))
+	}
+	if opts.CheckFonts {
+		summary = append(summary, fmt.Sprintf("Stylesheets with broken fonts: %d URLs", countIssues(results, "FONT_BROKEN")))
+	}
+	if opts.CheckNosniff {
+		summary = append(summary, formatNosniffSummary(results))
+	}
+	if opts.CheckHTMLSize {
+		summary = append(summary, formatHTMLSizeSummary(results)...)
+	}
+	if opts.CheckMaxAge {
+		summary = append(summary, formatHTMLMaxAgeSummary(results))
+	}
+	if opts.CheckLinkHeader {
+		summary = append(summary, fmt.Sprintf("Broken preload targets: %d URLs", countIssues(results, "PRELOAD_TARGET_BROKEN")))
+	}
+	if opts.FollowRedirects {
+		summary = append(summary, formatRedirectChainSummary(results))
+	}
+	if opts.Renderer != nil {
+		summary = append(summary, formatRenderSummary(results))
+	}
+	if opts.CheckTitle {
+		summary = append(summary, fmt.Sprintf("Missing titles: %d URLs", countIssues(results, "MISSING_TITLE")))
+		if opts.CheckTitleLength {
+			summary = append(summary, formatTitleLengthSummary(results, opts.TitleMin, opts.TitleMax))
+		}
+	}
+	if opts.CheckMetaDescription {
+		summary = append(summary, formatMetaDescriptionSummary(results))
+	}
+	if opts.CheckJSONLD {
+		summary = append(summary, formatJSONLDSummary(results, opts.RequireJSONLDType))
+	}
+	if opts.MobileFriendly != nil {
+		summary = append(summary, fmt.Sprintf("Not mobile-friendly: %d URLs", countIssues(results, "NOT_MOBILE_FRIENDLY")))
+	}
+	if opts.Robots != nil {
+		summary = append(summary, fmt.Sprintf("Blocked by robots.txt: %d URLs", countIssues(results, "ROBOTS_BLOCKED")))
+	}
+	if opts.CheckNoindex {
+		summary = append(summary, fmt.Sprintf("Noindex pages: %d URLs", countIssues(results, "NOINDEX")))
+	}
+	if opts.CheckCanonical {
+		summary = append(summary, fmt.Sprintf("Canonical mismatches: %d URLs", countIssues(results, "CANONICAL_MISMATCH")))
+	}
+	if opts.CheckCanonRedirect {
+		summary = append(summary, fmt.Sprintf("Canonical and redirect conflicts: %d URLs", countIssues(results, "CANONICAL_REDIRECT_CONFLICT")))
+	}
+	if opts.CheckIndexability {
+		summary = append(summary, formatIndexabilitySummary(results))
+	}
+	if opts.CheckSchema {
+		summary = append(summary, fmt.Sprintf("Incomplete %s schemas: %d URLs", opts.RequiredSchemaType, countIssues(results, "SCHEMA_INCOMPLETE")))
+	}
+	if *group404 {
+		summary = append(summary, format404Patterns(group404Patterns(results))...)
+	}
+	if opts.BackoffOnTimeout {
+		summary = append(summary, formatRetrySummary(results))
+	}
+	if opts.HreflangComplete {
+		summary = append(summary, fmt.Sprintf("Hreflang missing return links: %d URLs", hreflangMissingReturn))
+	}
+	if opts.HreflangTargets {
+		summary = append(summary, fmt.Sprintf("Hreflang targets: %d checked, %d broken", hreflangChecked, hreflangBroken))
+	}
+	if *checkRedirectTargets {
+		summary = append(summary, fmt.Sprintf("Redundant redirects in sitemap: %d URLs", redundantRedirects))
+	}
+	var domains []DomainCount
+	if *reportDomains {
+		domains = countDomains(results, opts.FollowRedirects)
+		summary = append(summary, formatDomainCounts(domains)...)
+	}
+	if *pathPrefixReport {
+		summary = append(summary, formatPrefixReport(countByPrefix(results, *prefixDepth))...)
+	}
+	if *baselineReport != "" {
+		summary = append(summary, formatRegressions(regressions)...)
+	}
+
+	if err := opts.Tracing.Finish(len(results), problematicCount); err != nil {
+		fmt.Printf("Warning: Failed to export traces: %v\n", err)
+	}
 
 	fmt.Println(summaryMsg)
 	fmt.Println(redirectMsg)
+	fmt.Println(errorMsg)
+	for _, line := range summary {
+		fmt.Println(line)
+	}
 
 	if logger != nil {
 		logger.Log("-------------------------------------------")
 		logger.Log(summaryMsg)
 		logger.Log(redirectMsg)
+		logger.Log(errorMsg)
+		for _, line := range summary {
+			logger.Log(line)
+		}
 		logger.Log(fmt.Sprintf("Finished at: %s", time.Now().Format(time.RFC3339)))
 	}
+
+	if *jsonReport != "" {
+		report := newJSONReport(runID, *sitemapURL, results)
+		report.Domains = domains
+		if err := writeJSONReport(*jsonReport, report); err != nil {
+			fmt.Printf("Warning: %v\n", err)
+		} else {
+			fmt.Printf("JSON report written to: %s\n", *jsonReport)
+		}
+	}
+
+	if *htmlReportPath != "" {
+		lines := append([]string{summaryMsg, redirectMsg, errorMsg}, summary...)
+		if err := saveHTMLReport(*htmlReportPath, runID, *sitemapURL, lines, results, domains); err != nil {
+			fmt.Printf("Warning: %v\n", err)
+		} else {
+			fmt.Printf("HTML report written to: %s\n", *htmlReportPath)
+		}
+	}
+
+	if *junitReport != "" {
+		if err := saveJUnitReport(*junitReport, *sitemapURL, results); err != nil {
+			fmt.Printf("Warning: %v\n", err)
+		} else {
+			fmt.Printf("JUnit report written to: %s\n", *junitReport)
+		}
+	}
+
+	if *outputSQL != "" {
+		if err := saveSQL(*outputSQL, startTime, results, *sqlDialect); err != nil {
+			fmt.Printf("Warning: %v\n", err)
+		} else {
+			fmt.Printf("SQL statements written to: %s\n", *outputSQL)
+		}
+	}
+
+	if *outputCurl != "" {
+		if count, err := saveCurlMakefile(*outputCurl, results, *insecure); err != nil {
+			fmt.Printf("Warning: %v\n", err)
+		} else {
+			fmt.Printf("curl commands for %d URLs written to: %s\n", count, *outputCurl)
+		}
+	}
+
+	exceeded, reason := failThreshold.Exceeded(brokenCount, len(results))
+
+	// Tell search engines about the sitemap once it has been checked
+	if *submit && *sitemapURL != "" {
+		if exceeded && *submitOnlyOnClean {
+			fmt.Println("Skipping sitemap submission: the check failed")
+		} else {
+			submitClient := &http.Client{Timeout: 30 * time.Second, Transport: transport}
+			submitSitemap(submitClient, *sitemapURL, selectPingEndpoints(*googleOnly, *bingOnly), logger)
+		}
+	}
+
+	if exceeded {
+		failMsg := fmt.Sprintf("FAILED: %s", reason)
+		fmt.Println(failMsg)
+		if logger != nil {
+			logger.Log(failMsg)
+			logger.Close()
+		}
+		osExit(1)
+		return
+	}
 }
 
-// retrieveAllURLs retrieves all URLs from a sitemap, including referenced sitemaps
-func retrieveAllURLs(client *http.Client, sitemapURL string, insecure bool) ([]string, error) {
-	// Create a temporary client that follows redirects for sitemap retrieval
-	transport := &http.Transport{}
-	if insecure {
-		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+// retrieveAllURLs retrieves all URL entries from a sitemap, including referenced sitemaps
+func retrieveAllURLs(client *http.Client, sitemapURL string, insecure bool) ([]URL, error) {
+	// Create a temporary client that follows redirects for sitemap retrieval,
+	// sharing the checker's transport so authentication applies to the sitemap too
+	transport := client.Transport
+	if transport == nil {
+		defaultTransport := &http.Transport{}
+		if insecure {
+			defaultTransport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+		}
+		transport = defaultTransport
 	}
 
 	tempClient := &http.Client{
@@ -318,24 +1575,66 @@ func retrieveAllURLs(client *http.Client, sitemapURL string, insecure bool) ([]s
 		Transport: transport,
 	}
 
-	body, err := fetchURL(tempClient, sitemapURL)
+	body, contentType, err := fetchURL(tempClient, sitemapURL)
 	if err != nil {
 		return nil, fmt.Errorf("error fetching sitemap: %w", err)
 	}
 
+	// Atom and RSS feeds list page URLs like a sitemap does
+	if format := feedFormat(contentType, body); format != "" {
+		urls, err := parseFeed(format, body)
+		if err != nil {
+			return nil, err
+		}
+		for i := range urls {
+			urls[i].Source = sitemapURL
+		}
+		return urls, nil
+	}
+
+	if validateNamespace {
+		reportSitemapNamespaces(sitemapURL, body)
+	}
+
 	// Try to parse as a sitemap index first
 	var sitemapIndex SitemapIndex
 	if err := xml.Unmarshal(body, &sitemapIndex); err == nil && len(sitemapIndex.Sitemaps) > 0 {
 		fmt.Printf("Found sitemap index with %d sitemaps\n", len(sitemapIndex.Sitemaps))
 
-		var allURLs []string
-		for _, sitemap := range sitemapIndex.Sitemaps {
-			fmt.Printf("Processing referenced sitemap: %s\n", sitemap.Loc)
-			urls, err := retrieveAllURLs(client, sitemap.Loc, insecure)
-			if err != nil {
-				fmt.Printf("Warning: Error processing referenced sitemap %s: %v\n", sitemap.Loc, err)
-				continue
-			}
+		sitemaps := sitemapIndex.Sitemaps
+		if maxSitemapFiles > 0 && len(sitemaps) > maxSitemapFiles {
+			fmt.Printf("Warning: Sitemap index lists %d sitemaps, only the first %d were processed (-max-sitemap-files)\n",
+				len(sitemaps), maxSitemapFiles)
+			sitemaps = sitemaps[:maxSitemapFiles]
+		}
+
+		// Fetch child sitemaps with a pool of workers, keeping their URLs in index order
+		childURLs := make([][]URL, len(sitemaps))
+		jobs := make(chan int)
+		var wg sync.WaitGroup
+		for w := 0; w < max(sitemapFetchConcurrency, 1); w++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := range jobs {
+					fmt.Printf("Processing referenced sitemap: %s\n", sitemaps[i].Loc)
+					urls, err := retrieveAllURLs(client, sitemaps[i].Loc, insecure)
+					if err != nil {
+						fmt.Printf("Warning: Error processing referenced sitemap %s: %v\n", sitemaps[i].Loc, err)
+						continue
+					}
+					childURLs[i] = urls
+				}
+			}()
+		}
+		for i := range sitemaps {
+			jobs <- i
+		}
+		close(jobs)
+		wg.Wait()
+
+		var allURLs []URL
+		for _, urls := range childURLs {
 			allURLs = append(allURLs, urls...)
 		}
 
@@ -348,31 +1647,57 @@ func retrieveAllURLs(client *http.Client, sitemapURL string, insecure bool) ([]s
 		return nil, fmt.Errorf("error parsing sitemap: %w", err)
 	}
 
-	var urls []string
-	for _, u := range urlSet.URLs {
-		urls = append(urls, u.Loc)
+	for i := range urlSet.URLs {
+		urlSet.URLs[i].Source = sitemapURL
 	}
 
-	return urls, nil
+	return urlSet.URLs, nil
 }
 
-// fetchURL fetches the content of a URL
-func fetchURL(client *http.Client, url string) ([]byte, error) {
+// maxSitemapFiles limits how many child sitemaps of an index are processed
+var maxSitemapFiles = 100
+
+// sitemapFetchConcurrency is the number of child sitemaps of an index fetched in parallel
+var sitemapFetchConcurrency = 5
+
+// fetchURL fetches the content of a URL along with its Content-Type
+func fetchURL(client *http.Client, url string) ([]byte, string, error) {
+	if strings.HasPrefix(strings.ToLower(url), "ftp://") {
+		body, err := fetchFTP(url)
+		return body, "", err
+	}
+
 	resp, err := client.Get(url)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("received non-200 status code: %d", resp.StatusCode)
+		return nil, "", fmt.Errorf("received non-200 status code: %d", resp.StatusCode)
 	}
 
-	return io.ReadAll(resp.Body)
+	body, err := io.ReadAll(resp.Body)
+	return body, resp.Header.Get("Content-Type"), err
+}
+
+// ftpUser and ftpPass authenticate ftp:// sitemap downloads
+var ftpUser, ftpPass string
+
+// ftpCredentials returns the login for an FTP URL, preferring credentials embedded in the URL
+func ftpCredentials(parsedURL *url.URL) (string, string) {
+	if parsedURL.User != nil {
+		pass, _ := parsedURL.User.Password()
+		return parsedURL.User.Username(), pass
+	}
+	if ftpUser != "" {
+		return ftpUser, ftpPass
+	}
+	return "anonymous", "anonymous"
 }
 
 // checkURLs checks all URLs and returns their status
-func checkURLs(client *http.Client, urls []string, timeoutMs int, concurrency int, logger *Logger) []Result {
+func checkURLs(client *http.Client, urls []URL, timeoutMs int, concurrency int, logger *Logger, opts CheckOptions) []Result {
 	results := make([]Result, 0, len(urls))
 	resultsChan := make(chan Result, len(urls))
 
@@ -382,28 +1707,104 @@ func checkURLs(client *http.Client, urls []string, timeoutMs int, concurrency in
 	// Create progress bar
 	progressBar := NewProgressBar(len(urls))
 
+	// The dashboard takes over the terminal while checking
+	var dashboard *Dashboard
+	if opts.TUI {
+		dashboard = newTerminalDashboard(len(urls))
+		if dashboard != nil {
+			progressBar.dashboard = dashboard
+			dashboard.Start()
+			defer dashboard.Stop()
+		} else {
+			fmt.Println("Warning: -tui needs a terminal, showing the progress bar instead")
+		}
+	}
+
+	// Remember results so URLs listed in several sitemaps are only checked once
+	var cache *ResultCache
+	if opts.UseCache {
+		cache = NewResultCache()
+	}
+
+	// Track a deadline per host so a single slow server can't hold up the run
+	var hostTimeouts *HostTimeouts
+	if opts.PerHostTimeout > 0 {
+		hostTimeouts = NewHostTimeouts(opts.PerHostTimeout)
+		defer hostTimeouts.Stop()
+	}
+
+	// Keep multi-domain sitemaps from contacting every domain at once
+	var domains *DomainLimiter
+	if opts.MaxConcurrentDomains > 0 {
+		domains = NewDomainLimiter(opts.MaxConcurrentDomains)
+	}
+
 	var wg sync.WaitGroup
 
 	// Process URLs with rate limiting and concurrency control
-	for _, url := range urls {
+	for _, entry := range urls {
 		wg.Add(1)
 
 		// Acquire semaphore (blocks if we've reached max concurrency)
 		sem <- struct{}{}
 
-		go func(url string) {
+		go func(entry URL) {
+			url := entry.Loc
+
 			defer wg.Done()
 			defer func() { <-sem }() // Release semaphore when done
+			defer opts.Progress.Record(url)
+
+			// Send a result for collection, remembering it for later duplicates
+			send := func(result Result) {
+				// Cached results were completed when they were first sent
+				if !result.FromCache {
+					// Issues found in the URL itself were logged before checking started
+					result.OriginalURL = entry.Original
+					result.URLLength = len(url)
+					if len(entry.Issues) > 0 {
+						result.Issues = append(append([]Issue{}, entry.Issues...), result.Issues...)
+					}
+					if cache != nil {
+						cache.Add(url, result)
+					}
+				}
+				opts.Backoff.Record(result)
+				opts.Webhook.Notify(result)
+				opts.Tracing.RecordResult(result)
+				dashboard.Add(result)
+				resultsChan <- result
+			}
+
+			// Reuse the results of an earlier check of the same URL
+			if cache != nil {
+				if cached, ok := cache.Lookup(url); ok {
+					for _, result := range cached {
+						result.SourceSitemap = entry.Source
+						result.FromCache = true
+						send(result)
+					}
+					if opts.Verbose && logger != nil {
+						logger.Log(fmt.Sprintf("CACHE HIT: %s", url) + sourceTag(entry.Source))
+					}
+					progressBar.Increment()
+					return
+				}
+				defer cache.Finish(url)
+			}
+
+			// Wait for the domain's turn, holding it until the URL is checked
+			defer domains.Release(domains.Acquire(url))
 
 			// Create a request to check headers only
 			req, err := http.NewRequest("HEAD", url, nil)
 			if err != nil {
-				result := Result{URL: url, Error: err}
-				resultsChan <- result
+				result := Result{URL: url, Error: err, SourceSitemap: entry.Source}
+				send(result)
 
 				// Log error immediately
 				if logger != nil {
-					logger.Log(fmt.Sprintf("ERROR: %s - %v", url, err))
+					logger.Log(fmt.Sprintf("ERROR: %s - %v", url, err) + sourceTag(entry.Source))
 				}
 
 				progressBar.Increment()
@@ -411,34 +1812,58 @@ func checkURLs(client *http.Client, urls []string, timeoutMs int, concurrency in
 			}
 
 			// Set a user agent to avoid being blocked
-			req.Header.Set("User-Agent", "SitemapChecker/1.0")
+			opts.prepareRequest(req)
+
+			// Skip hosts that have used up their time and cancel requests when they do
+			if hostTimeouts != nil {
+				if hostTimeouts.Exceeded(req.URL.Host) {
+					result := Result{URL: url, Error: hostTimeouts.Error(req.URL.Host), SourceSitemap: entry.Source}
+					send(result)
+
+					if logger != nil {
+						logger.Log(fmt.Sprintf("ERROR: %s - %v", url, result.Error) + sourceTag(entry.Source))
+					}
 
-			resp, err := client.Do(req)
+					progressBar.Increment()
+					return
+				}
+				req = req.WithContext(hostTimeouts.Context(req.URL.Host))
+			}
+
+			start := time.Now()
+			resp, retries, err := opts.do(client, req)
+			responseTime := time.Since(start)
+			if err != nil && hostTimeouts != nil && hostTimeouts.Exceeded(req.URL.Host) {
+				err = hostTimeouts.Error(req.URL.Host)
+			}
 			if err != nil {
 				// Check if it's a redirect error
 				if resp != nil && (resp.StatusCode >= 300 && resp.StatusCode < 400) {
 					// It's a redirect
 					redirectURL := resp.Header.Get("Location")
 					result := Result{
-						URL:         url,
-						Status:      resp.StatusCode,
-						IsRedirect:  true,
-						RedirectURL: redirectURL,
+						URL:           url,
+						Status:        resp.StatusCode,
+						IsRedirect:    true,
+						RedirectURL:   redirectURL,
+						SourceSitemap: entry.Source,
+						ResponseTime:  responseTime,
+						RetryCount:    retries,
 					}
-					resultsChan <- result
+					send(result)
 
 					// Log redirect immediately
 					if logger != nil {
-						logger.Log(fmt.Sprintf("REDIRECT: %s -> %s (Status: %d)", url, redirectURL, resp.StatusCode))
+						logger.Log(fmt.Sprintf("REDIRECT: %s -> %s (Status: %d)", url, redirectURL, resp.StatusCode) + sourceTag(entry.Source))
 					}
 				} else {
 					// It's another error
-					result := Result{URL: url, Error: err}
-					resultsChan <- result
+					result := Result{URL: url, Error: err, SourceSitemap: entry.Source, ResponseTime: responseTime, RetryCount: retries}
+					send(result)
 
 					// Log error immediately
 					if logger != nil {
-						logger.Log(fmt.Sprintf("ERROR: %s - %v", url, err))
+						logger.Log(fmt.Sprintf("ERROR: %s - %v", url, err) + sourceTag(entry.Source))
 					}
 				}
 
@@ -447,7 +1872,18 @@ func checkURLs(client *http.Client, urls []string, timeoutMs int, concurrency in
 			}
 			defer resp.Body.Close()
 
-			result := Result{URL: url, Status: resp.StatusCode}
+			result := Result{
+				URL:           url,
+				Status:        resp.StatusCode,
+				HeadStatus:    resp.StatusCode,
+				Header:        resp.Header,
+				Protocol:      resp.Proto,
+				TLSVersion:    tlsVersionName(resp.TLS),
+				ResponseTime:  responseTime,
+				RetryCount:    retries,
+				Images:        entry.Images,
+				SourceSitemap: entry.Source,
+			}
 
 			// Check for redirects (status codes 301, 302, 303, 307, 308)
 			if resp.StatusCode >= 300 && resp.StatusCode < 400 {
@@ -457,16 +1893,21 @@ func checkURLs(client *http.Client, urls []string, timeoutMs int, concurrency in
 
 				// Log redirect immediately
 				if logger != nil {
-					logger.Log(fmt.Sprintf("REDIRECT: %s -> %s (Status: %d)", url, redirectURL, resp.StatusCode))
+					logger.Log(fmt.Sprintf("REDIRECT: %s -> %s (Status: %d)", url, redirectURL, resp.StatusCode) + sourceTag(entry.Source))
 				}
 			} else if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 				// Log bad status immediately
 				if logger != nil {
-					logger.Log(fmt.Sprintf("INVALID STATUS: %s - %d", url, resp.StatusCode))
+					logger.Log(fmt.Sprintf("INVALID STATUS: %s - %d", url, resp.StatusCode) + sourceTag(entry.Source))
 				}
 			}
 
-			resultsChan <- result
+			// Run the optional checks unless GET is about to be retried below
+			if resp.StatusCode != http.StatusMethodNotAllowed {
+				runChecks(client, &result, opts, logger)
+			}
+
+			send(result)
 
 			// If HEAD request returned 405 Method Not Allowed, try GET instead
 			if resp.StatusCode == http.StatusMethodNotAllowed {
@@ -478,31 +1919,42 @@ func checkURLs(client *http.Client, urls []string, timeoutMs int, concurrency in
 					return
 				}
 
-				getReq.Header.Set("User-Agent", "SitemapChecker/1.0")
+				opts.prepareRequest(getReq)
+				if hostTimeouts != nil {
+					getReq = getReq.WithContext(hostTimeouts.Context(getReq.URL.Host))
+				}
 
-				getResp, err := client.Do(getReq)
+				getStart := time.Now()
+				getResp, getRetries, err := opts.do(client, getReq)
+				getResponseTime := time.Since(getStart)
+				if err != nil && hostTimeouts != nil && hostTimeouts.Exceeded(getReq.URL.Host) {
+					err = hostTimeouts.Error(getReq.URL.Host)
+				}
 				if err != nil {
 					// Check if it's a redirect error
 					if getResp != nil && (getResp.StatusCode >= 300 && getResp.StatusCode < 400) {
 						// It's a redirect
 						redirectURL := getResp.Header.Get("Location")
 						getResult := Result{
-							URL:         url,
-							Status:      getResp.StatusCode,
-							IsRedirect:  true,
-							RedirectURL: redirectURL,
+							URL:           url,
+							Status:        getResp.StatusCode,
+							IsRedirect:    true,
+							RedirectURL:   redirectURL,
+							SourceSitemap: entry.Source,
+							ResponseTime:  getResponseTime,
+							RetryCount:    retries + getRetries,
 						}
-						resultsChan <- getResult
+						send(getResult)
 
 						// Log redirect immediately
 						if logger != nil {
 							logger.Log(fmt.Sprintf("REDIRECT (GET after 405): %s -> %s (Status: %d)",
-								url, redirectURL, getResp.StatusCode))
+								url, redirectURL, getResp.StatusCode) + sourceTag(entry.Source))
 						}
 					} else {
 						// It's another error
 						if logger != nil {
-							logger.Log(fmt.Sprintf("ERROR (GET after 405): %s - %v", url, err))
+							logger.Log(fmt.Sprintf("ERROR (GET after 405): %s - %v", url, err) + sourceTag(entry.Source))
 						}
 					}
 
@@ -511,7 +1963,19 @@ func checkURLs(client *http.Client, urls []string, timeoutMs int, concurrency in
 				}
 				defer getResp.Body.Close()
 
-				getResult := Result{URL: url, Status: getResp.StatusCode}
+				getResult := Result{
+					URL:           url,
+					Status:        getResp.StatusCode,
+					HeadStatus:    resp.StatusCode,
+					GetStatus:     getResp.StatusCode,
+					Header:        getResp.Header,
+					Protocol:      getResp.Proto,
+					TLSVersion:    tlsVersionName(getResp.TLS),
+					ResponseTime:  getResponseTime,
+					RetryCount:    retries + getRetries,
+					Images:        entry.Images,
+					SourceSitemap: entry.Source,
+				}
 
 				// Check for redirects (status codes 301, 302, 303, 307, 308)
 				if getResp.StatusCode >= 300 && getResp.StatusCode < 400 {
@@ -522,24 +1986,29 @@ func checkURLs(client *http.Client, urls []string, timeoutMs int, concurrency in
 					// Log redirect immediately
 					if logger != nil {
 						logger.Log(fmt.Sprintf("REDIRECT (GET after 405): %s -> %s (Status: %d)",
-							url, redirectURL, getResp.StatusCode))
+							url, redirectURL, getResp.StatusCode) + sourceTag(entry.Source))
 					}
 				} else if getResp.StatusCode < 200 || getResp.StatusCode >= 300 {
 					// Log bad status immediately
 					if logger != nil {
-						logger.Log(fmt.Sprintf("INVALID STATUS (GET after 405): %s - %d", url, getResp.StatusCode))
+						logger.Log(fmt.Sprintf("INVALID STATUS (GET after 405): %s - %d", url, getResp.StatusCode) + sourceTag(entry.Source))
 					}
 				}
 
-				resultsChan <- getResult
+				runChecks(client, &getResult, opts, logger)
+
+				send(getResult)
 			}
 
 			progressBar.Increment()
-		}(url)
+		}(entry)
 
 		// Sleep to respect the timeout between requests
-		// Only if not running at max concurrency (which naturally spaces out requests)
-		if len(sem) < concurrency {
+		// Only if not running at max concurrency (which naturally spaces out requests),
+		// unless the server is rate limiting us
+		if opts.Backoff.Backing() {
+			time.Sleep(opts.Backoff.Delay())
+		} else if len(sem) < concurrency {
 			time.Sleep(time.Duration(timeoutMs) * time.Millisecond)
 		}
 	}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Recommended meta description length in characters, longer ones are cut off in search results
+const (
+	metaDescriptionMin = 120
+	metaDescriptionMax = 160
+)
+
+// checkMetaDescription records the page's meta description and flags it when it is
+// missing or its length is outside the recommended range. Non-HTML responses are skipped.
+func checkMetaDescription(result *Result, page *Page) {
+	if !isHTML(page.Header.Get("Content-Type")) {
+		return
+	}
+
+	content, _ := page.metaContent("name", "description")
+	result.MetaDescription = strings.Join(strings.Fields(content), " ")
+	if result.MetaDescription == "" {
+		result.addIssue("MISSING_META_DESCRIPTION", "page has no meta description")
+		return
+	}
+
+	if length := utf8.RuneCountInString(result.MetaDescription); length < metaDescriptionMin || length > metaDescriptionMax {
+		result.addIssue("META_DESCRIPTION_LENGTH", fmt.Sprintf("%d characters, recommended is %d-%d",
+			length, metaDescriptionMin, metaDescriptionMax))
+	}
+}
+
+// formatMetaDescriptionSummary reports how many descriptions are missing, short, within range and long
+func formatMetaDescriptionSummary(results []Result) string {
+	short, ok, long := 0, 0, 0
+	for _, result := range results {
+		if result.MetaDescription == "" {
+			continue
+		}
+		switch length := utf8.RuneCountInString(result.MetaDescription); {
+		case length < metaDescriptionMin:
+			short++
+		case length > metaDescriptionMax:
+			long++
+		default:
+			ok++
+		}
+	}
+	return fmt.Sprintf("Meta descriptions: %d missing, %d under %d characters, %d within %d-%d, %d over %d",
+		countIssues(results, "MISSING_META_DESCRIPTION"), short, metaDescriptionMin, ok,
+		metaDescriptionMin, metaDescriptionMax, long, metaDescriptionMax)
+}
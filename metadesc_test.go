@@ -0,0 +1,64 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// Test for checkMetaDescription function
+func TestCheckMetaDescription(t *testing.T) {
+	tests := []struct {
+		name     string
+		head     string
+		want     string
+		wantCode string
+	}{
+		{name: "missing", head: `<title>Page</title>`, wantCode: "MISSING_META_DESCRIPTION"},
+		{name: "empty", head: `<meta name="description" content="  ">`, wantCode: "MISSING_META_DESCRIPTION"},
+		{name: "too short", head: `<meta name="description" content="Short.">`, want: "Short.", wantCode: "META_DESCRIPTION_LENGTH"},
+		{
+			name: "within range",
+			head: `<meta name="Description" content="` + strings.Repeat("a", 140) + `">`,
+			want: strings.Repeat("a", 140),
+		},
+		{
+			name:     "too long",
+			head:     `<meta name="description" content="` + strings.Repeat("é", 161) + `">`,
+			want:     strings.Repeat("é", 161),
+			wantCode: "META_DESCRIPTION_LENGTH",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page := parseTestPage(t, "https://example.com/", `<html><head>`+tt.head+`</head></html>`)
+			result := Result{URL: page.URL, Status: 200}
+			checkMetaDescription(&result, page)
+
+			if result.MetaDescription != tt.want {
+				t.Errorf("MetaDescription = %q, want %q", result.MetaDescription, tt.want)
+			}
+			var code string
+			if len(result.Issues) > 0 {
+				code = result.Issues[0].Code
+			}
+			if code != tt.wantCode {
+				t.Errorf("issue code = %q, want %q", code, tt.wantCode)
+			}
+		})
+	}
+}
+
+// Test for formatMetaDescriptionSummary function
+func TestFormatMetaDescriptionSummary(t *testing.T) {
+	results := []Result{
+		{Issues: []Issue{{Code: "MISSING_META_DESCRIPTION"}}},
+		{MetaDescription: "short"},
+		{MetaDescription: strings.Repeat("a", 150)},
+		{MetaDescription: strings.Repeat("a", 200)},
+	}
+	want := "Meta descriptions: 1 missing, 1 under 120 characters, 1 within 120-160, 1 over 160"
+	if got := formatMetaDescriptionSummary(results); got != want {
+		t.Errorf("formatMetaDescriptionSummary() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// fetchedLinkRels are the <link> relations whose target the browser loads with the page
+var fetchedLinkRels = []string{"stylesheet", "icon", "apple-touch-icon", "preload", "modulepreload", "manifest"}
+
+// resourceURLs returns the resolved URLs of the images, scripts, loaded <link> targets
+// and iframes of the page
+func (p *Page) resourceURLs() []string {
+	var resources []string
+	walkHTML(p.Doc, func(n *html.Node) {
+		var ref string
+		switch n.Data {
+		case "img", "script", "iframe":
+			ref, _ = attr(n, "src")
+		case "link":
+			for _, rel := range fetchedLinkRels {
+				if hasRel(n, rel) {
+					ref, _ = attr(n, "href")
+					break
+				}
+			}
+		}
+		if strings.TrimSpace(ref) != "" {
+			resources = append(resources, resolveURL(p.URL, ref))
+		}
+	})
+	return resources
+}
+
+// checkMixedContent flags the resources an HTTPS page loads over plain HTTP
+func checkMixedContent(result *Result, page *Page) {
+	seen := make(map[string]bool)
+	for _, resource := range page.resourceURLs() {
+		if !strings.HasPrefix(strings.ToLower(resource), "http://") || seen[resource] {
+			continue
+		}
+		seen[resource] = true
+		result.MixedContentURLs = append(result.MixedContentURLs, resource)
+	}
+	if len(result.MixedContentURLs) > 0 {
+		result.addIssue("MIXED_CONTENT", fmt.Sprintf("%d resources loaded over HTTP: %s",
+			len(result.MixedContentURLs), strings.Join(result.MixedContentURLs, ", ")))
+	}
+}
+
+// formatMixedContentSummary reports the total number of insecure resources and lists the pages loading them
+func formatMixedContentSummary(results []Result) []string {
+	total, pages := 0, 0
+	var lines []string
+	for _, result := range results {
+		if len(result.MixedContentURLs) == 0 {
+			continue
+		}
+		total += len(result.MixedContentURLs)
+		pages++
+		lines = append(lines, fmt.Sprintf("  %s (%d resources)", result.URL, len(result.MixedContentURLs)))
+	}
+	return append([]string{fmt.Sprintf("Mixed content: %d HTTP resources on %d URLs", total, pages)}, lines...)
+}
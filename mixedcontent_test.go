@@ -0,0 +1,43 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+// Test for checkMixedContent function
+func TestCheckMixedContent(t *testing.T) {
+	page := parseTestPage(t, "https://example.com/blog/", `<html><head>
+		<link rel="stylesheet" href="http://cdn.example.com/style.css">
+		<link rel="canonical" href="http://example.com/blog/">
+		<link rel="icon" href="/favicon.ico">
+		<script src="HTTP://cdn.example.com/app.js"></script>
+		</head><body>
+		<img src="http://images.example.com/a.png"><img src="http://images.example.com/a.png">
+		<img src="//images.example.com/b.png">
+		<iframe src="http://video.example.com/embed"></iframe>
+		<a href="http://other.example/">link</a>
+		</body></html>`)
+
+	result := Result{URL: page.URL, Status: 200}
+	checkMixedContent(&result, page)
+
+	want := []string{
+		"http://cdn.example.com/style.css",
+		"http://cdn.example.com/app.js",
+		"http://images.example.com/a.png",
+		"http://video.example.com/embed",
+	}
+	if !reflect.DeepEqual(result.MixedContentURLs, want) {
+		t.Errorf("MixedContentURLs = %v, want %v", result.MixedContentURLs, want)
+	}
+	if countIssues([]Result{result}, "MIXED_CONTENT") != 1 {
+		t.Errorf("issues = %+v, want one MIXED_CONTENT", result.Issues)
+	}
+
+	results := []Result{result, {URL: "https://example.com/clean", Status: 200}}
+	wantSummary := []string{"Mixed content: 4 HTTP resources on 1 URLs", "  https://example.com/blog/ (4 resources)"}
+	if got := formatMixedContentSummary(results); !reflect.DeepEqual(got, wantSummary) {
+		t.Errorf("formatMixedContentSummary() = %q, want %q", got, wantSummary)
+	}
+}
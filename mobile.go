@@ -0,0 +1,145 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// mobileFriendlyEndpoint is Google's Mobile-Friendly Test API
+const mobileFriendlyEndpoint = "https://searchconsole.googleapis.com/v1/urlTestingTools/mobileFriendlyTest:run"
+
+// mobileFriendlyInterval spaces API calls to stay within the per-minute quota
+const mobileFriendlyInterval = time.Second
+
+// mobileFriendlyResponse is the part of the API response the check uses
+type mobileFriendlyResponse struct {
+	TestStatus struct {
+		Status  string `json:"status"`
+		Details string `json:"details"`
+	} `json:"testStatus"`
+	MobileFriendliness   string `json:"mobileFriendliness"`
+	MobileFriendlyIssues []struct {
+		Rule string `json:"rule"`
+	} `json:"mobileFriendlyIssues"`
+}
+
+// MobileFriendlyResult holds the outcome of a Mobile-Friendly Test
+type MobileFriendlyResult struct {
+	Friendly bool
+	Issues   []string
+	Error    error
+}
+
+// MobileFriendlyChecker runs Mobile-Friendly Tests, rate limiting the calls and
+// caching the result per URL
+type MobileFriendlyChecker struct {
+	client   *http.Client
+	apiKey   string
+	endpoint string
+	interval time.Duration
+	lastCall time.Time
+	cache    map[string]MobileFriendlyResult
+	mu       sync.Mutex
+}
+
+// NewMobileFriendlyChecker creates a new Mobile-Friendly Test checker
+func NewMobileFriendlyChecker(client *http.Client, apiKey string) *MobileFriendlyChecker {
+	return &MobileFriendlyChecker{
+		client:   client,
+		apiKey:   apiKey,
+		endpoint: mobileFriendlyEndpoint,
+		interval: mobileFriendlyInterval,
+		cache:    make(map[string]MobileFriendlyResult),
+	}
+}
+
+// Test returns the Mobile-Friendly Test result of a URL, calling the API only once per URL
+func (m *MobileFriendlyChecker) Test(pageURL string) MobileFriendlyResult {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if result, ok := m.cache[pageURL]; ok {
+		return result
+	}
+
+	if wait := m.interval - time.Since(m.lastCall); wait > 0 {
+		time.Sleep(wait)
+	}
+	m.lastCall = time.Now()
+
+	var result MobileFriendlyResult
+	result.Friendly, result.Issues, result.Error = m.run(pageURL)
+	// Failed calls are not cached so a later check can retry them
+	if result.Error == nil {
+		m.cache[pageURL] = result
+	}
+	return result
+}
+
+// run calls the API for one URL
+func (m *MobileFriendlyChecker) run(pageURL string) (bool, []string, error) {
+	payload, err := json.Marshal(map[string]interface{}{"url": pageURL, "requestScreenshot": false})
+	if err != nil {
+		return false, nil, err
+	}
+	req, err := http.NewRequest("POST", m.endpoint+"?key="+url.QueryEscape(m.apiKey), bytes.NewReader(payload))
+	if err != nil {
+		return false, nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return false, nil, fmt.Errorf("mobile-friendly test failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return false, nil, fmt.Errorf("failed to read mobile-friendly test response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return false, nil, fmt.Errorf("mobile-friendly test returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var parsed mobileFriendlyResponse
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return false, nil, fmt.Errorf("invalid mobile-friendly test response: %w", err)
+	}
+	if parsed.TestStatus.Status != "COMPLETE" {
+		return false, nil, fmt.Errorf("mobile-friendly test status %s: %s", parsed.TestStatus.Status, parsed.TestStatus.Details)
+	}
+
+	var issues []string
+	for _, issue := range parsed.MobileFriendlyIssues {
+		issues = append(issues, issue.Rule)
+	}
+	return parsed.MobileFriendliness == "MOBILE_FRIENDLY", issues, nil
+}
+
+// checkMobileFriendly records whether Google considers a page mobile-friendly and flags it otherwise
+func checkMobileFriendly(checker *MobileFriendlyChecker, result *Result) error {
+	test := checker.Test(result.URL)
+	if test.Error != nil {
+		return test.Error
+	}
+
+	friendly := test.Friendly
+	result.MobileFriendly = &friendly
+	if !friendly {
+		msg := "page is not mobile-friendly"
+		if len(test.Issues) > 0 {
+			msg += ": " + strings.Join(test.Issues, ", ")
+		}
+		result.addIssue("NOT_MOBILE_FRIENDLY", msg)
+	}
+	return nil
+}
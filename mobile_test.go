@@ -0,0 +1,71 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Test for checkMobileFriendly function
+func TestCheckMobileFriendly(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.Method != "POST" || r.URL.Query().Get("key") != "secret" {
+			t.Errorf("request = %s %s, want POST with the API key", r.Method, r.URL)
+		}
+		var body struct {
+			URL string `json:"url"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+
+		switch body.URL {
+		case "https://example.com/mobile":
+			fmt.Fprint(w, `{"testStatus": {"status": "COMPLETE"}, "mobileFriendliness": "MOBILE_FRIENDLY"}`)
+		case "https://example.com/desktop":
+			fmt.Fprint(w, `{"testStatus": {"status": "COMPLETE"}, "mobileFriendliness": "NOT_MOBILE_FRIENDLY",
+				"mobileFriendlyIssues": [{"rule": "TEXT_TOO_SMALL"}, {"rule": "TAP_TARGETS_TOO_CLOSE"}]}`)
+		default:
+			fmt.Fprint(w, `{"testStatus": {"status": "PAGE_UNREACHABLE", "details": "timeout"}}`)
+		}
+	}))
+	defer server.Close()
+
+	checker := NewMobileFriendlyChecker(server.Client(), "secret")
+	checker.endpoint = server.URL
+	checker.interval = 0
+
+	mobile := Result{URL: "https://example.com/mobile", Status: 200}
+	if err := checkMobileFriendly(checker, &mobile); err != nil {
+		t.Fatalf("checkMobileFriendly() error = %v", err)
+	}
+	if mobile.MobileFriendly == nil || !*mobile.MobileFriendly || len(mobile.Issues) != 0 {
+		t.Errorf("mobile result = %+v, want mobile-friendly without issues", mobile)
+	}
+
+	desktop := Result{URL: "https://example.com/desktop", Status: 200}
+	if err := checkMobileFriendly(checker, &desktop); err != nil {
+		t.Fatalf("checkMobileFriendly() error = %v", err)
+	}
+	if desktop.MobileFriendly == nil || *desktop.MobileFriendly {
+		t.Errorf("desktop MobileFriendly = %v, want false", desktop.MobileFriendly)
+	}
+	want := "page is not mobile-friendly: TEXT_TOO_SMALL, TAP_TARGETS_TOO_CLOSE"
+	if len(desktop.Issues) != 1 || desktop.Issues[0].Code != "NOT_MOBILE_FRIENDLY" || desktop.Issues[0].Message != want {
+		t.Errorf("desktop issues = %+v, want NOT_MOBILE_FRIENDLY %q", desktop.Issues, want)
+	}
+
+	unreachable := Result{URL: "https://example.com/down", Status: 200}
+	if err := checkMobileFriendly(checker, &unreachable); err == nil || unreachable.MobileFriendly != nil {
+		t.Errorf("checkMobileFriendly() = %v, MobileFriendly %v, want an error and no result", err, unreachable.MobileFriendly)
+	}
+
+	// Cached results don't call the API again
+	again := Result{URL: "https://example.com/mobile", Status: 200}
+	checkMobileFriendly(checker, &again)
+	if calls != 3 {
+		t.Errorf("API called %d times, want 3", calls)
+	}
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"sync"
+)
+
+// sitemapNamespace is the namespace every urlset and sitemapindex must declare
+const sitemapNamespace = "http://www.sitemaps.org/schemas/sitemap/0.9"
+
+// extensionNamespaces are the namespaces of the Google sitemap extensions by their usual prefix
+var extensionNamespaces = map[string]string{
+	"image": "http://www.google.com/schemas/sitemap-image/1.1",
+	"video": "http://www.google.com/schemas/sitemap-video/1.1",
+	"news":  "http://www.google.com/schemas/sitemap-news/0.9",
+}
+
+// validateNamespace enables the namespace check of every sitemap file retrieved
+var validateNamespace bool
+
+// checkSitemapNamespaces returns the problems with the namespace declarations of a
+// sitemap's root element
+func checkSitemapNamespaces(body []byte) ([]string, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, fmt.Errorf("no root element found: %w", err)
+		}
+		root, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		var problems []string
+		switch root.Name.Space {
+		case sitemapNamespace:
+		case "":
+			problems = append(problems, fmt.Sprintf("<%s> does not declare xmlns=%q", root.Name.Local, sitemapNamespace))
+		default:
+			problems = append(problems, fmt.Sprintf("<%s> declares xmlns=%q, want %q", root.Name.Local, root.Name.Space, sitemapNamespace))
+		}
+
+		for _, a := range root.Attr {
+			if a.Name.Space != "xmlns" {
+				continue
+			}
+			if want, ok := extensionNamespaces[a.Name.Local]; ok && a.Value != want {
+				problems = append(problems, fmt.Sprintf("xmlns:%s=%q, want %q", a.Name.Local, a.Value, want))
+			}
+		}
+		return problems, nil
+	}
+}
+
+// namespaceProblem is an INVALID_NAMESPACE problem of a sitemap file
+type namespaceProblem struct {
+	Sitemap string
+	Problem string
+}
+
+// namespaceProblems collects the problems of every sitemap retrieved, child sitemaps
+// are retrieved concurrently
+var namespaceProblems struct {
+	sync.Mutex
+	list []namespaceProblem
+}
+
+// reportSitemapNamespaces prints an INVALID_NAMESPACE warning for every namespace problem
+// of a sitemap and records it for the log and the summary
+func reportSitemapNamespaces(sitemapURL string, body []byte) {
+	problems, err := checkSitemapNamespaces(body)
+	if err != nil {
+		problems = []string{err.Error()}
+	}
+
+	namespaceProblems.Lock()
+	defer namespaceProblems.Unlock()
+	for _, problem := range problems {
+		fmt.Printf("Warning: INVALID_NAMESPACE: %s - %s\n", sitemapURL, problem)
+		namespaceProblems.list = append(namespaceProblems.list, namespaceProblem{Sitemap: sitemapURL, Problem: problem})
+	}
+}
+
+// recordedNamespaceProblems returns the problems recorded while retrieving the sitemaps
+func recordedNamespaceProblems() []namespaceProblem {
+	namespaceProblems.Lock()
+	defer namespaceProblems.Unlock()
+	return append([]namespaceProblem(nil), namespaceProblems.list...)
+}
+
+// formatNamespaceSummary reports how many problems were found in how many sitemap files
+func formatNamespaceSummary(problems []namespaceProblem) string {
+	sitemaps := make(map[string]bool)
+	for _, p := range problems {
+		sitemaps[p.Sitemap] = true
+	}
+	return fmt.Sprintf("Invalid namespaces: %d problems in %d sitemap files", len(problems), len(sitemaps))
+}
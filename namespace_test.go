@@ -0,0 +1,67 @@
+package main
+
+import "testing"
+
+// Test for checkSitemapNamespaces function
+func TestCheckSitemapNamespaces(t *testing.T) {
+	tests := []struct {
+		name         string
+		xml          string
+		wantProblems int
+	}{
+		{
+			name: "valid urlset",
+			xml: `<?xml version="1.0" encoding="UTF-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9" xmlns:image="http://www.google.com/schemas/sitemap-image/1.1">
+<url><loc>https://example.com/</loc></url></urlset>`,
+		},
+		{
+			name:         "missing namespace",
+			xml:          `<urlset><url><loc>https://example.com/</loc></url></urlset>`,
+			wantProblems: 1,
+		},
+		{
+			name:         "wrong namespace",
+			xml:          `<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.8"></sitemapindex>`,
+			wantProblems: 1,
+		},
+		{
+			name: "wrong extension namespaces",
+			xml: `<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9"
+ xmlns:video="http://www.google.com/schemas/sitemap-video/1.0"
+ xmlns:news="http://www.google.com/schemas/sitemap-news/0.9"
+ xmlns:image="https://www.google.com/schemas/sitemap-image/1.1"></urlset>`,
+			wantProblems: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			problems, err := checkSitemapNamespaces([]byte(tt.xml))
+			if err != nil {
+				t.Fatalf("checkSitemapNamespaces() error = %v", err)
+			}
+			if len(problems) != tt.wantProblems {
+				t.Errorf("checkSitemapNamespaces() = %v, want %d problems", problems, tt.wantProblems)
+			}
+		})
+	}
+}
+
+// Test that namespace problems are recorded for the log and the summary
+func TestReportSitemapNamespaces(t *testing.T) {
+	namespaceProblems.list = nil
+	defer func() { namespaceProblems.list = nil }()
+
+	reportSitemapNamespaces("https://example.com/a.xml", []byte(`<urlset xmlns:image="http://example.com/image"></urlset>`))
+	reportSitemapNamespaces("https://example.com/b.xml", []byte(`<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9"></urlset>`))
+	reportSitemapNamespaces("https://example.com/c.xml", []byte(`not xml`))
+
+	problems := recordedNamespaceProblems()
+	if len(problems) != 3 || problems[0].Sitemap != "https://example.com/a.xml" || problems[2].Sitemap != "https://example.com/c.xml" {
+		t.Errorf("recordedNamespaceProblems() = %+v, want 2 for a.xml and 1 for c.xml", problems)
+	}
+	if got, want := formatNamespaceSummary(problems), "Invalid namespaces: 3 problems in 2 sitemap files"; got != want {
+		t.Errorf("formatNamespaceSummary() = %q, want %q", got, want)
+	}
+}
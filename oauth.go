@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+// OAuth2Transport adds a bearer token obtained with the client credentials flow to
+// requests sent to the allowed hosts. Tokens are refreshed automatically when they
+// expire. If a token cannot be obtained, the error is logged and requests continue
+// without auth.
+type OAuth2Transport struct {
+	source   oauth2.TokenSource
+	base     http.RoundTripper
+	hosts    map[string]bool
+	logger   *Logger
+	mu       sync.Mutex
+	disabled bool
+}
+
+// NewOAuth2Transport creates a transport that authenticates requests sent through base.
+// Only requests to one of hosts (host or host:port) carry the token, so it never
+// leaks to third parties such as search engines or linked sites.
+func NewOAuth2Transport(tokenURL, clientID, clientSecret string, hosts []string, base http.RoundTripper, logger *Logger) *OAuth2Transport {
+	config := &clientcredentials.Config{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		TokenURL:     tokenURL,
+	}
+
+	// Fetch tokens through the base transport so settings like -k apply to the token endpoint
+	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, &http.Client{Transport: base})
+
+	allowed := make(map[string]bool, len(hosts))
+	for _, host := range hosts {
+		allowed[strings.ToLower(host)] = true
+	}
+
+	return &OAuth2Transport{
+		source: config.TokenSource(ctx),
+		base:   base,
+		hosts:  allowed,
+		logger: logger,
+	}
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *OAuth2Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !t.allowed(req) {
+		return t.base.RoundTrip(req)
+	}
+
+	token, ok := t.token()
+	if !ok {
+		return t.base.RoundTrip(req)
+	}
+
+	// RoundTrippers must not modify the original request
+	authReq := req.Clone(req.Context())
+	token.SetAuthHeader(authReq)
+	return t.base.RoundTrip(authReq)
+}
+
+// allowed reports whether the request goes to a host that may receive the token
+func (t *OAuth2Transport) allowed(req *http.Request) bool {
+	host := strings.ToLower(req.URL.Host)
+	return t.hosts[host] || t.hosts[strings.ToLower(req.URL.Hostname())]
+}
+
+// token returns a valid token, or false once obtaining a token has failed
+func (t *OAuth2Transport) token() (*oauth2.Token, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.disabled {
+		return nil, false
+	}
+
+	token, err := t.source.Token()
+	if err != nil {
+		t.disabled = true
+		msg := fmt.Sprintf("Warning: Failed to obtain OAuth2 token: %v. Continuing without authentication.", err)
+		fmt.Println(msg)
+		if t.logger != nil {
+			t.logger.Log(msg)
+		}
+		return nil, false
+	}
+	return token, true
+}
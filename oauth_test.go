@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+// Test that OAuth2Transport authenticates requests with the client credentials token
+func TestOAuth2Transport(t *testing.T) {
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"access_token":"test-token","token_type":"Bearer","expires_in":3600}`)
+	}))
+	defer tokenServer.Close()
+
+	var gotAuth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+	}))
+	defer server.Close()
+	serverURL, _ := url.Parse(server.URL)
+
+	var otherAuth string
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		otherAuth = r.Header.Get("Authorization")
+	}))
+	defer other.Close()
+
+	tests := []struct {
+		name     string
+		tokenURL string
+		wantAuth string
+	}{
+		{name: "token obtained", tokenURL: tokenServer.URL + "/token", wantAuth: "Bearer test-token"},
+		{name: "token failure continues without auth", tokenURL: tokenServer.URL + "/invalid", wantAuth: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport := NewOAuth2Transport(tt.tokenURL, "id", "secret", []string{serverURL.Host}, http.DefaultTransport, nil)
+			client := &http.Client{Transport: transport}
+
+			for i := 0; i < 2; i++ {
+				gotAuth = "unset"
+				resp, err := client.Get(server.URL)
+				if err != nil {
+					t.Fatalf("Get() error = %v", err)
+				}
+				resp.Body.Close()
+
+				if gotAuth != tt.wantAuth {
+					t.Errorf("Authorization header = %q, want %q", gotAuth, tt.wantAuth)
+				}
+			}
+
+			// Hosts outside the allow-list never see the token
+			otherAuth = "unset"
+			resp, err := client.Get(other.URL)
+			if err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+			resp.Body.Close()
+			if otherAuth != "" {
+				t.Errorf("Authorization header sent to other host = %q, want none", otherAuth)
+			}
+		})
+	}
+}
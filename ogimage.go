@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"mime"
+	"net/http"
+)
+
+// ogImageMinSize is the minimum width and height of an OG image for social previews
+const ogImageMinSize = 200
+
+// ogImageHeaderBytes limits how much of an OG image is read to decode its dimensions
+const ogImageHeaderBytes = 256 << 10
+
+// allowedOGImageTypes are the image formats social networks accept for OG images
+var allowedOGImageTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+}
+
+// checkOGImage verifies that the og:image of a page is reachable, has an allowed
+// image type and is large enough for social sharing previews
+func checkOGImage(client *http.Client, result *Result, page *Page) {
+	ogImage, ok := page.metaContent("property", "og:image")
+	if !ok || ogImage == "" {
+		return
+	}
+	ogImage = resolveURL(page.URL, ogImage)
+	result.OGImage = ogImage
+
+	req, err := http.NewRequest("GET", ogImage, nil)
+	if err != nil {
+		result.addIssue("OG_IMAGE_BROKEN", fmt.Sprintf("%s: %v", ogImage, err))
+		return
+	}
+	req.Header.Set("User-Agent", userAgent)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		result.addIssue("OG_IMAGE_BROKEN", fmt.Sprintf("%s: %v", ogImage, err))
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		result.addIssue("OG_IMAGE_BROKEN", fmt.Sprintf("%s (Status: %d)", ogImage, resp.StatusCode))
+		return
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if !allowedOGImageTypes[mediaType] {
+		result.addIssue("OG_IMAGE_INVALID_TYPE", fmt.Sprintf("%s has Content-Type %q", ogImage, resp.Header.Get("Content-Type")))
+		return
+	}
+
+	// Only the image header is needed to read the dimensions
+	config, _, err := image.DecodeConfig(io.LimitReader(resp.Body, ogImageHeaderBytes))
+	if err != nil {
+		result.addIssue("OG_IMAGE_INVALID_TYPE", fmt.Sprintf("%s could not be decoded: %v", ogImage, err))
+		return
+	}
+
+	if config.Width < ogImageMinSize || config.Height < ogImageMinSize {
+		result.addIssue("OG_IMAGE_TOO_SMALL", fmt.Sprintf("%s is %dx%d, minimum is %dx%d",
+			ogImage, config.Width, config.Height, ogImageMinSize, ogImageMinSize))
+	}
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Test for checkOGImage function
+func TestCheckOGImage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/large.png":
+			w.Header().Set("Content-Type", "image/png")
+			png.Encode(w, image.NewRGBA(image.Rect(0, 0, 1200, 630)))
+		case "/small.png":
+			w.Header().Set("Content-Type", "image/png")
+			png.Encode(w, image.NewRGBA(image.Rect(0, 0, 100, 100)))
+		case "/image.svg":
+			w.Header().Set("Content-Type", "image/svg+xml")
+			fmt.Fprint(w, "<svg></svg>")
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		ogImage  string
+		wantCode string
+	}{
+		{name: "valid image", ogImage: "/large.png"},
+		{name: "too small", ogImage: "/small.png", wantCode: "OG_IMAGE_TOO_SMALL"},
+		{name: "unsupported type", ogImage: "/image.svg", wantCode: "OG_IMAGE_INVALID_TYPE"},
+		{name: "missing image", ogImage: "/missing.png", wantCode: "OG_IMAGE_BROKEN"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page := parseTestPage(t, server.URL+"/page",
+				fmt.Sprintf(`<html><head><meta property="og:image" content="%s"></head></html>`, tt.ogImage))
+
+			result := Result{URL: page.URL, Status: http.StatusOK}
+			checkOGImage(server.Client(), &result, page)
+
+			if result.OGImage != server.URL+tt.ogImage {
+				t.Errorf("OGImage = %q, want %q", result.OGImage, server.URL+tt.ogImage)
+			}
+			var code string
+			if len(result.Issues) > 0 {
+				code = result.Issues[0].Code
+			}
+			if code != tt.wantCode {
+				t.Errorf("checkOGImage() issue = %q, want %q", code, tt.wantCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,236 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// userAgent is sent with every request made by the checker
+const userAgent = "SitemapChecker/1.0"
+
+// bodyReadLimit limits how many bytes of a page body are read for content checks
+var bodyReadLimit int64 = 5 << 20
+
+// Page represents a fetched HTML page used by the content checks
+type Page struct {
+	URL    string
+	Status int
+	Header http.Header
+	Body   []byte
+	Doc    *html.Node
+
+	// Truncated is set when the body was cut off at bodyReadLimit
+	Truncated bool
+}
+
+// fetchPage retrieves a page with GET and parses its body as HTML
+func fetchPage(client *http.Client, pageURL string) (*Page, error) {
+	return fetchPageAs(client, pageURL, userAgent)
+}
+
+// fetchPageAs retrieves a page like fetchPage, identifying as the given user agent
+func fetchPageAs(client *http.Client, pageURL, agent string) (*Page, error) {
+	req, err := http.NewRequest("GET", pageURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", agent)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	// Read one byte past the limit to tell whether the body was truncated
+	body, err := io.ReadAll(io.LimitReader(resp.Body, bodyReadLimit+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read body: %w", err)
+	}
+
+	page := &Page{
+		URL:    pageURL,
+		Status: resp.StatusCode,
+		Header: resp.Header,
+		Body:   body,
+	}
+	if int64(len(body)) > bodyReadLimit {
+		page.Body = body[:bodyReadLimit]
+		page.Truncated = true
+	}
+
+	// An unparsable body is not fatal, the checks just won't find any elements
+	if doc, err := html.Parse(bytes.NewReader(page.Body)); err == nil {
+		page.Doc = doc
+	}
+
+	return page, nil
+}
+
+// isHTML reports whether a Content-Type header value denotes an HTML document
+func isHTML(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && mediaType == "text/html"
+}
+
+// headCheck requests a URL with HEAD, falling back to GET if HEAD is not allowed
+func headCheck(client *http.Client, target string) (int, error) {
+	return headCheckAs(client, target, userAgent)
+}
+
+// headCheckAs requests a URL like headCheck, identifying as the given user agent
+func headCheckAs(client *http.Client, target, agent string) (int, error) {
+	for _, method := range []string{"HEAD", "GET"} {
+		req, err := http.NewRequest(method, target, nil)
+		if err != nil {
+			return 0, err
+		}
+		req.Header.Set("User-Agent", agent)
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return 0, err
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode != http.StatusMethodNotAllowed {
+			return resp.StatusCode, nil
+		}
+	}
+	return http.StatusMethodNotAllowed, nil
+}
+
+// isBroken reports whether a status code or error means the resource is unreachable
+func isBroken(status int, err error) bool {
+	return err != nil || status >= 400
+}
+
+// resolveURL resolves a possibly relative reference against a base URL
+func resolveURL(base, ref string) string {
+	baseURL, err := url.Parse(base)
+	if err != nil {
+		return ref
+	}
+	refURL, err := url.Parse(strings.TrimSpace(ref))
+	if err != nil {
+		return ref
+	}
+	return baseURL.ResolveReference(refURL).String()
+}
+
+// walkHTML calls fn for every element node in the document
+func walkHTML(n *html.Node, fn func(*html.Node)) {
+	if n == nil {
+		return
+	}
+	if n.Type == html.ElementNode {
+		fn(n)
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		walkHTML(c, fn)
+	}
+}
+
+// attr returns the value of the named attribute of an element
+func attr(n *html.Node, name string) (string, bool) {
+	for _, a := range n.Attr {
+		if strings.EqualFold(a.Key, name) {
+			return a.Val, true
+		}
+	}
+	return "", false
+}
+
+// hasRel reports whether an element's rel attribute contains the given value
+func hasRel(n *html.Node, rel string) bool {
+	value, ok := attr(n, "rel")
+	if !ok {
+		return false
+	}
+	for _, r := range strings.Fields(value) {
+		if strings.EqualFold(r, rel) {
+			return true
+		}
+	}
+	return false
+}
+
+// findRelLinks returns the resolved href of every <link> or <a> element with the given rel
+func (p *Page) findRelLinks(rel string) []string {
+	var links []string
+	walkHTML(p.Doc, func(n *html.Node) {
+		if n.Data != "link" && n.Data != "a" {
+			return
+		}
+		if !hasRel(n, rel) {
+			return
+		}
+		if href, ok := attr(n, "href"); ok && strings.TrimSpace(href) != "" {
+			links = append(links, resolveURL(p.URL, href))
+		}
+	})
+	return links
+}
+
+// firstRelLink returns the first link with the given rel, or an empty string
+func (p *Page) firstRelLink(rel string) string {
+	if links := p.findRelLinks(rel); len(links) > 0 {
+		return links[0]
+	}
+	return ""
+}
+
+// hreflangLinks returns the resolved href of every alternate language link of the page
+func (p *Page) hreflangLinks() []string {
+	var links []string
+	walkHTML(p.Doc, func(n *html.Node) {
+		if n.Data != "link" || !hasRel(n, "alternate") {
+			return
+		}
+		if _, ok := attr(n, "hreflang"); !ok {
+			return
+		}
+		if href, ok := attr(n, "href"); ok && strings.TrimSpace(href) != "" {
+			links = append(links, resolveURL(p.URL, href))
+		}
+	})
+	return links
+}
+
+// metaContent returns the content of the first <meta> element whose key attribute
+// (such as name or property) equals value, ignoring case
+func (p *Page) metaContent(key, value string) (string, bool) {
+	var content string
+	found := false
+	walkHTML(p.Doc, func(n *html.Node) {
+		if found || n.Data != "meta" {
+			return
+		}
+		if v, ok := attr(n, key); ok && strings.EqualFold(strings.TrimSpace(v), value) {
+			content, _ = attr(n, "content")
+			found = true
+		}
+	})
+	return strings.TrimSpace(content), found
+}
+
+// iconLinks returns the resolved href of every <link rel="icon"> of the page, including "shortcut icon"
+func (p *Page) iconLinks() []string {
+	var links []string
+	walkHTML(p.Doc, func(n *html.Node) {
+		if n.Data != "link" || !hasRel(n, "icon") {
+			return
+		}
+		if href, ok := attr(n, "href"); ok && strings.TrimSpace(href) != "" {
+			links = append(links, resolveURL(p.URL, href))
+		}
+	})
+	return links
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/html"
+)
+
+// parseTestPage builds a Page from an HTML string for testing the content checks
+func parseTestPage(t *testing.T, pageURL, body string) *Page {
+	t.Helper()
+	doc, err := html.Parse(strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("Failed to parse test page: %v", err)
+	}
+	return &Page{
+		URL:    pageURL,
+		Status: http.StatusOK,
+		Header: http.Header{"Content-Type": {"text/html; charset=utf-8"}},
+		Body:   []byte(body),
+		Doc:    doc,
+	}
+}
+
+// Test for Page link and meta helpers
+func TestPageHelpers(t *testing.T) {
+	page := parseTestPage(t, "https://example.com/blog/page2", `<html><head>
+<link rel="prev" href="/blog/page1">
+<link rel="alternate next" href="page3">
+<meta name="Description" content=" A description ">
+</head></html>`)
+
+	if got := page.firstRelLink("prev"); got != "https://example.com/blog/page1" {
+		t.Errorf("firstRelLink(prev) = %q", got)
+	}
+	if got := page.firstRelLink("next"); got != "https://example.com/blog/page3" {
+		t.Errorf("firstRelLink(next) = %q", got)
+	}
+	if got := page.firstRelLink("canonical"); got != "" {
+		t.Errorf("firstRelLink(canonical) = %q, want empty", got)
+	}
+	if got, ok := page.metaContent("name", "description"); !ok || got != "A description" {
+		t.Errorf("metaContent(description) = %q, %v", got, ok)
+	}
+}
+
+// Test for Page.hreflangLinks
+func TestPageHreflangLinks(t *testing.T) {
+	page := parseTestPage(t, "https://example.com/en/", `<html><head>
+<link rel="alternate" hreflang="de" href="/de/">
+<link rel="alternate" hreflang="x-default" href="https://example.com/">
+<link rel="alternate" type="application/rss+xml" href="/feed.xml">
+<link rel="canonical" hreflang="en" href="/en/">
+</head></html>`)
+
+	want := []string{"https://example.com/de/", "https://example.com/"}
+	if got := page.hreflangLinks(); !equalStringSlices(got, want) {
+		t.Errorf("hreflangLinks() = %v, want %v", got, want)
+	}
+}
+
+// Test for Page.iconLinks
+func TestPageIconLinks(t *testing.T) {
+	page := parseTestPage(t, "https://example.com/blog/", `<html><head>
+<link rel="icon" type="image/png" href="/icon.png">
+<link rel="shortcut icon" href="favicon.ico">
+<link rel="apple-touch-icon" href="/touch.png">
+<link rel="icon" href="">
+</head></html>`)
+
+	want := []string{"https://example.com/icon.png", "https://example.com/blog/favicon.ico"}
+	if got := page.iconLinks(); !equalStringSlices(got, want) {
+		t.Errorf("iconLinks() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// maxPaginationPages limits how many pages are followed in each direction of a pagination chain
+const maxPaginationPages = 50
+
+// paginationPage keeps the links of a chain page the chain checks need, so walked
+// chains can be remembered without their bodies
+type paginationPage struct {
+	URL       string
+	First     string
+	Last      string
+	Canonical string
+}
+
+// newPaginationPage extracts the pagination links of a page
+func newPaginationPage(p *Page) paginationPage {
+	return paginationPage{
+		URL:       p.URL,
+		First:     p.firstRelLink("first"),
+		Last:      p.firstRelLink("last"),
+		Canonical: p.firstRelLink("canonical"),
+	}
+}
+
+// walkPagination follows rel=prev and rel=next links from a page and returns the
+// chain ordered from the first to the last page, along with any broken links found.
+// visit, when set, is called with every link before it is requested.
+func walkPagination(client *http.Client, start *Page, visit func(link string)) ([]paginationPage, []string) {
+	var issues []string
+	visited := map[string]bool{start.URL: true}
+
+	follow := func(from *Page, rel string) []paginationPage {
+		var pages []paginationPage
+		current := from
+		for len(pages) < maxPaginationPages {
+			link := current.firstRelLink(rel)
+			if link == "" || visited[link] {
+				break
+			}
+			visited[link] = true
+			if visit != nil {
+				visit(link)
+			}
+
+			page, err := fetchPage(client, link)
+			if err != nil {
+				issues = append(issues, fmt.Sprintf("rel=%s on %s points to %s: %v", rel, current.URL, link, err))
+				break
+			}
+			if page.Status != http.StatusOK {
+				issues = append(issues, fmt.Sprintf("rel=%s on %s points to %s (Status: %d)", rel, current.URL, link, page.Status))
+				break
+			}
+
+			pages = append(pages, newPaginationPage(page))
+			current = page
+		}
+		return pages
+	}
+
+	prev := follow(start, "prev")
+	next := follow(start, "next")
+
+	chain := make([]paginationPage, 0, len(prev)+len(next)+1)
+	for i := len(prev) - 1; i >= 0; i-- {
+		chain = append(chain, prev[i])
+	}
+	chain = append(chain, newPaginationPage(start))
+	chain = append(chain, next...)
+
+	return chain, issues
+}
+
+// paginationChain is a walked pagination chain, shared by all of its pages. It is
+// filled in once, by the walk that reached one of its pages first.
+type paginationChain struct {
+	once   sync.Once
+	pages  []paginationPage
+	issues []string
+}
+
+// PaginationWalker walks each pagination chain once per run, no matter how many of its
+// pages are in the sitemap, and makes sure every chain issue is only reported once
+type PaginationWalker struct {
+	chains   map[string]*paginationChain
+	reported map[string]bool
+	mu       sync.Mutex
+}
+
+// NewPaginationWalker creates a new pagination walker
+func NewPaginationWalker() *PaginationWalker {
+	return &PaginationWalker{
+		chains:   make(map[string]*paginationChain),
+		reported: make(map[string]bool),
+	}
+}
+
+// Walk returns the chain the page belongs to and the broken links found walking it.
+// A chain already walked, or being walked, from another of its pages is reused.
+// A nil walker always walks.
+func (w *PaginationWalker) Walk(client *http.Client, start *Page) ([]paginationPage, []string) {
+	if w == nil {
+		return walkPagination(client, start, nil)
+	}
+
+	// The page is claimed before walking, as is every page the walk reaches, so
+	// the other pages of the chain wait for this walk instead of starting their own
+	w.mu.Lock()
+	chain, ok := w.chains[start.URL]
+	if !ok {
+		chain = &paginationChain{}
+		w.chains[start.URL] = chain
+	}
+	w.mu.Unlock()
+
+	chain.once.Do(func() {
+		chain.pages, chain.issues = walkPagination(client, start, func(link string) {
+			w.mu.Lock()
+			if _, ok := w.chains[link]; !ok {
+				w.chains[link] = chain
+			}
+			w.mu.Unlock()
+		})
+	})
+	return chain.pages, chain.issues
+}
+
+// Unreported returns the issues that were not returned before, so an issue of a chain
+// is only attached to the first of its pages. A nil walker returns all issues.
+func (w *PaginationWalker) Unreported(issues []string) []string {
+	if w == nil {
+		return issues
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var fresh []string
+	for _, issue := range issues {
+		if !w.reported[issue] {
+			w.reported[issue] = true
+			fresh = append(fresh, issue)
+		}
+	}
+	return fresh
+}
+
+// checkPaginationIntegrity verifies that the rel=first and rel=last links of every
+// page in a chain point to the chain's first and last page
+func checkPaginationIntegrity(chain []paginationPage) []string {
+	if len(chain) < 2 {
+		return nil
+	}
+	first, last := chain[0].URL, chain[len(chain)-1].URL
+
+	var issues []string
+	for _, page := range chain {
+		if link := page.First; link != "" && link != first {
+			issues = append(issues, fmt.Sprintf("rel=first on %s points to %s, chain starts at %s", page.URL, link, first))
+		}
+		if link := page.Last; link != "" && link != last {
+			issues = append(issues, fmt.Sprintf("rel=last on %s points to %s, chain ends at %s", page.URL, link, last))
+		}
+	}
+	return issues
+}
+
+// checkPaginationCanonical verifies that every page in a chain has a canonical URL of
+// either itself or the first page, rather than another page of the chain
+func checkPaginationCanonical(chain []paginationPage) []string {
+	if len(chain) < 2 {
+		return nil
+	}
+	inChain := make(map[string]bool, len(chain))
+	for _, page := range chain {
+		inChain[page.URL] = true
+	}
+	first := chain[0].URL
+
+	var issues []string
+	for _, page := range chain {
+		canonical := page.Canonical
+		if canonical == "" || canonical == page.URL || canonical == first || !inChain[canonical] {
+			continue
+		}
+		issues = append(issues, fmt.Sprintf("rel=canonical on %s points to %s, want itself or %s", page.URL, canonical, first))
+	}
+	return issues
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// Test for walkPagination function
+func TestWalkPagination(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/page1":
+			fmt.Fprint(w, `<html><head><link rel="next" href="/page2"></head></html>`)
+		case "/page2":
+			fmt.Fprint(w, `<html><head><link rel="prev" href="/page1"><link rel="next" href="/page3"></head></html>`)
+		case "/page3":
+			fmt.Fprint(w, `<html><head><link rel="prev" href="/page2"><link rel="next" href="/page4"></head></html>`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	start, err := fetchPage(server.Client(), server.URL+"/page2")
+	if err != nil {
+		t.Fatalf("fetchPage() error = %v", err)
+	}
+
+	chain, issues := walkPagination(server.Client(), start, nil)
+
+	var got []string
+	for _, page := range chain {
+		got = append(got, strings.TrimPrefix(page.URL, server.URL))
+	}
+	want := []string{"/page1", "/page2", "/page3"}
+	if !equalStringSlices(got, want) {
+		t.Errorf("walkPagination() chain = %v, want %v", got, want)
+	}
+
+	if len(issues) != 1 || !strings.Contains(issues[0], "/page4") {
+		t.Errorf("walkPagination() issues = %v, want one issue for /page4", issues)
+	}
+}
+
+// Test that PaginationWalker walks a chain once and reports its issues once
+func TestPaginationWalker(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		switch r.URL.Path {
+		case "/page1":
+			fmt.Fprint(w, `<html><head><link rel="next" href="/page2"></head></html>`)
+		case "/page2":
+			fmt.Fprint(w, `<html><head><link rel="prev" href="/page1"><link rel="next" href="/page3"></head></html>`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	walker := NewPaginationWalker()
+	var reported []string
+	for _, path := range []string{"/page1", "/page2"} {
+		start, err := fetchPage(server.Client(), server.URL+path)
+		if err != nil {
+			t.Fatalf("fetchPage() error = %v", err)
+		}
+		chain, issues := walker.Walk(server.Client(), start)
+		if len(chain) != 2 {
+			t.Errorf("Walk(%s) chain has %d pages, want 2", path, len(chain))
+		}
+		reported = append(reported, walker.Unreported(issues)...)
+	}
+
+	// Two pages fetched by the test, the chain walked once: page2 then the broken page3
+	if got := requests.Load(); got != 4 {
+		t.Errorf("server received %d requests, want 4", got)
+	}
+	if len(reported) != 1 || !strings.Contains(reported[0], "/page3") {
+		t.Errorf("reported issues = %v, want one issue for /page3", reported)
+	}
+}
+
+// Test that a page of a chain being walked waits for that walk instead of walking again
+func TestPaginationWalkerSingleFlight(t *testing.T) {
+	var page2Requests atomic.Int32
+	started, release := make(chan struct{}), make(chan struct{})
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/page2" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if page2Requests.Add(1) == 1 {
+			close(started)
+		}
+		<-release
+		fmt.Fprintf(w, `<html><head><link rel="prev" href="%s/page1"></head></html>`, server.URL)
+	}))
+	defer server.Close()
+
+	page1 := parseTestPage(t, server.URL+"/page1", `<html><head><link rel="next" href="`+server.URL+`/page2"></head></html>`)
+	page2 := parseTestPage(t, server.URL+"/page2", `<html><head><link rel="prev" href="`+server.URL+`/page1"></head></html>`)
+
+	walker := NewPaginationWalker()
+	var wg sync.WaitGroup
+	chains := make([][]paginationPage, 2)
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		chains[0], _ = walker.Walk(server.Client(), page1)
+	}()
+	<-started
+	go func() {
+		defer wg.Done()
+		chains[1], _ = walker.Walk(server.Client(), page2)
+	}()
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if got := page2Requests.Load(); got != 1 {
+		t.Errorf("/page2 requested %d times, want 1", got)
+	}
+	for i, chain := range chains {
+		if len(chain) != 2 {
+			t.Errorf("chain %d has %d pages, want 2", i, len(chain))
+		}
+	}
+}
+
+// paginationPages extracts the pagination links of test pages
+func paginationPages(pages []*Page) []paginationPage {
+	var chain []paginationPage
+	for _, page := range pages {
+		chain = append(chain, newPaginationPage(page))
+	}
+	return chain
+}
+
+// Test for checkPaginationIntegrity function
+func TestCheckPaginationIntegrity(t *testing.T) {
+	const base = "https://example.com"
+	links := `<link rel="first" href="/page1"><link rel="last" href="/page3">`
+	chain := []*Page{
+		parseTestPage(t, base+"/page1", `<html><head>`+links+`</head></html>`),
+		parseTestPage(t, base+"/page2", `<html><head>`+links+`</head></html>`),
+		parseTestPage(t, base+"/page3", `<html><head>`+links+`</head></html>`),
+	}
+	if issues := checkPaginationIntegrity(paginationPages(chain)); len(issues) != 0 {
+		t.Errorf("checkPaginationIntegrity() consistent chain issues = %v, want none", issues)
+	}
+
+	// The last page claims the chain ends somewhere else, the middle page has no first/last links
+	chain[1] = parseTestPage(t, base+"/page2", `<html><head></head></html>`)
+	chain[2] = parseTestPage(t, base+"/page3", `<html><head><link rel="first" href="/page1"><link rel="last" href="/page9"></head></html>`)
+	issues := checkPaginationIntegrity(paginationPages(chain))
+	if len(issues) != 1 || !strings.Contains(issues[0], "rel=last on "+base+"/page3") {
+		t.Errorf("checkPaginationIntegrity() issues = %v, want one rel=last issue on /page3", issues)
+	}
+
+	if issues := checkPaginationIntegrity(paginationPages(chain[:1])); issues != nil {
+		t.Errorf("checkPaginationIntegrity() single page issues = %v, want none", issues)
+	}
+}
+
+// Test for checkPaginationCanonical function
+func TestCheckPaginationCanonical(t *testing.T) {
+	const base = "https://example.com"
+	canonical := func(href string) string {
+		return `<html><head><link rel="canonical" href="` + href + `"></head></html>`
+	}
+	chain := []*Page{
+		parseTestPage(t, base+"/page1", canonical("/page1")),
+		parseTestPage(t, base+"/page2", canonical("/page2")),
+		parseTestPage(t, base+"/page3", canonical("/page1")),
+		parseTestPage(t, base+"/page4", `<html><head></head></html>`),
+	}
+	if issues := checkPaginationCanonical(paginationPages(chain)); len(issues) != 0 {
+		t.Errorf("checkPaginationCanonical() valid chain issues = %v, want none", issues)
+	}
+
+	// The third page points to the second, the fourth to a page outside the chain
+	chain[2] = parseTestPage(t, base+"/page3", canonical("/page2"))
+	chain[3] = parseTestPage(t, base+"/page4", canonical("/all"))
+	issues := checkPaginationCanonical(paginationPages(chain))
+	if len(issues) != 1 || !strings.Contains(issues[0], "rel=canonical on "+base+"/page3") {
+		t.Errorf("checkPaginationCanonical() issues = %v, want one issue on /page3", issues)
+	}
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// max404Patterns limits how many 404 path templates are reported
+const max404Patterns = 10
+
+var (
+	uuidSegment = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	dateSegment = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
+	digitRun    = regexp.MustCompile(`\d+`)
+)
+
+// PathPattern is a path template shared by several URLs
+type PathPattern struct {
+	Template string
+	Count    int
+}
+
+// normaliseURLPattern turns a URL path into a template by replacing UUID segments
+// with {UUID}, date segments with {DATE} and runs of digits with {N}
+func normaliseURLPattern(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		switch {
+		case uuidSegment.MatchString(segment):
+			segments[i] = "{UUID}"
+		case dateSegment.MatchString(segment):
+			segments[i] = "{DATE}"
+		default:
+			segments[i] = digitRun.ReplaceAllString(segment, "{N}")
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// group404Patterns groups 404 results by path template, most common first
+func group404Patterns(results []Result) []PathPattern {
+	counts := make(map[string]int)
+	for _, result := range results {
+		if result.Error != nil || result.Status != http.StatusNotFound {
+			continue
+		}
+		path := result.URL
+		if parsedURL, err := url.Parse(result.URL); err == nil {
+			path = parsedURL.EscapedPath()
+		}
+		counts[normaliseURLPattern(path)]++
+	}
+
+	patterns := make([]PathPattern, 0, len(counts))
+	for template, count := range counts {
+		patterns = append(patterns, PathPattern{Template: template, Count: count})
+	}
+	sort.Slice(patterns, func(i, j int) bool {
+		if patterns[i].Count != patterns[j].Count {
+			return patterns[i].Count > patterns[j].Count
+		}
+		return patterns[i].Template < patterns[j].Template
+	})
+	return patterns
+}
+
+// format404Patterns returns summary lines for the most common 404 path templates
+func format404Patterns(patterns []PathPattern) []string {
+	if len(patterns) == 0 {
+		return []string{"404 path patterns: none"}
+	}
+
+	lines := []string{"404 path patterns:"}
+	for i, pattern := range patterns {
+		if i == max404Patterns {
+			lines = append(lines, fmt.Sprintf("  ... and %d more", len(patterns)-max404Patterns))
+			break
+		}
+		lines = append(lines, fmt.Sprintf("  %s (%d URLs)", pattern.Template, pattern.Count))
+	}
+	return lines
+}
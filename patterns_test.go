@@ -0,0 +1,47 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+// Test for normaliseURLPattern function
+func TestNormaliseURLPattern(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{path: "/blog/old-post-1234", want: "/blog/old-post-{N}"},
+		{path: "/products/42/reviews", want: "/products/{N}/reviews"},
+		{path: "/orders/3f2b8c1e-9d4a-4b6f-8e2a-1c5d7f9b0a3e", want: "/orders/{UUID}"},
+		{path: "/archive/2023-05-17/", want: "/archive/{DATE}/"},
+		{path: "/about", want: "/about"},
+	}
+
+	for _, tt := range tests {
+		if got := normaliseURLPattern(tt.path); got != tt.want {
+			t.Errorf("normaliseURLPattern(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+// Test for group404Patterns function
+func TestGroup404Patterns(t *testing.T) {
+	results := []Result{
+		{URL: "https://example.com/blog/old-post-1", Status: 404},
+		{URL: "https://example.com/blog/old-post-22", Status: 404},
+		{URL: "https://example.com/blog/old-post-333", Status: 404},
+		{URL: "https://example.com/tag/2020-01-01", Status: 404},
+		{URL: "https://example.com/blog/old-post-4", Status: 200},
+		{URL: "https://example.com/gone", Status: 410},
+	}
+
+	got := group404Patterns(results)
+	want := []PathPattern{
+		{Template: "/blog/old-post-{N}", Count: 3},
+		{Template: "/tag/{DATE}", Count: 1},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("group404Patterns() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+)
+
+// prefixStats counts the checked and broken URLs below a path prefix
+type prefixStats struct {
+	Total  int
+	Errors int
+}
+
+// ErrorRate returns the percentage of broken URLs below the prefix
+func (s prefixStats) ErrorRate() float64 {
+	return errorPct(s.Errors, s.Total)
+}
+
+// countByPrefix groups results by their first depth path segments
+func countByPrefix(results []Result, depth int) map[string]prefixStats {
+	stats := make(map[string]prefixStats)
+	for _, result := range results {
+		prefix, _ := pathPrefix(result.URL, depth)
+		s := stats[prefix]
+		s.Total++
+		if isBroken(result.Status, result.Error) {
+			s.Errors++
+		}
+		stats[prefix] = s
+	}
+	return stats
+}
+
+// formatPrefixReport returns a table of the prefixes sorted by error rate, highest first
+func formatPrefixReport(stats map[string]prefixStats) []string {
+	prefixes := sortedKeys(stats)
+	sort.SliceStable(prefixes, func(i, j int) bool {
+		a, b := stats[prefixes[i]], stats[prefixes[j]]
+		if a.ErrorRate() != b.ErrorRate() {
+			return a.ErrorRate() > b.ErrorRate()
+		}
+		return a.Total > b.Total
+	})
+
+	width := len("Path prefix")
+	for _, prefix := range prefixes {
+		width = max(width, len(prefix))
+	}
+
+	lines := []string{fmt.Sprintf("%-*s %8s %8s %10s", width, "Path prefix", "URLs", "Errors", "Error rate")}
+	for _, prefix := range prefixes {
+		s := stats[prefix]
+		lines = append(lines, fmt.Sprintf("%-*s %8d %8d %9.1f%%", width, prefix, s.Total, s.Errors, s.ErrorRate()))
+	}
+	return lines
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// Test for countByPrefix function
+func TestCountByPrefix(t *testing.T) {
+	results := []Result{
+		{URL: "https://example.com/", Status: 200},
+		{URL: "https://example.com/blog/2024/a", Status: 200},
+		{URL: "https://example.com/blog/2024/b", Status: 404},
+		{URL: "https://example.com/blog/2023/c", Error: errors.New("timeout")},
+		{URL: "https://example.com/shop", Status: 301, IsRedirect: true},
+	}
+
+	got := countByPrefix(results, 1)
+	want := map[string]prefixStats{
+		"/":     {Total: 1},
+		"/blog": {Total: 3, Errors: 2},
+		"/shop": {Total: 1},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("countByPrefix() = %v, want %v", got, want)
+	}
+	for prefix, stats := range want {
+		if got[prefix] != stats {
+			t.Errorf("countByPrefix()[%q] = %+v, want %+v", prefix, got[prefix], stats)
+		}
+	}
+
+	if got := countByPrefix(results, 2); got["/blog/2024"] != (prefixStats{Total: 2, Errors: 1}) {
+		t.Errorf("countByPrefix(depth 2)[/blog/2024] = %+v", got["/blog/2024"])
+	}
+}
+
+// Test for formatPrefixReport function
+func TestFormatPrefixReport(t *testing.T) {
+	lines := formatPrefixReport(map[string]prefixStats{
+		"/":     {Total: 10},
+		"/blog": {Total: 4, Errors: 1},
+		"/shop": {Total: 2, Errors: 2},
+	})
+
+	if len(lines) != 4 {
+		t.Fatalf("formatPrefixReport() returned %d lines, want 4:\n%s", len(lines), strings.Join(lines, "\n"))
+	}
+	for i, prefix := range []string{"/shop", "/blog", "/"} {
+		if !strings.HasPrefix(lines[i+1], prefix+" ") {
+			t.Errorf("line %d = %q, want prefix %s", i+1, lines[i+1], prefix)
+		}
+	}
+	if !strings.HasSuffix(lines[1], "100.0%") || !strings.HasSuffix(lines[2], "25.0%") {
+		t.Errorf("unexpected error rates:\n%s", strings.Join(lines, "\n"))
+	}
+}
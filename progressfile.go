@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+	"sync"
+)
+
+// ProgressFile records every checked URL so an interrupted run can be resumed
+// without checking those URLs again
+type ProgressFile struct {
+	path string
+	file *os.File
+	mu   sync.Mutex
+}
+
+// loadProgress returns the URLs recorded in a progress file, or an empty set if it doesn't exist
+func loadProgress(path string) (map[string]bool, error) {
+	done := make(map[string]bool)
+
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return done, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open progress file: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			done[line] = true
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read progress file: %w", err)
+	}
+	return done, nil
+}
+
+// skipCheckedURLs removes the URLs already recorded in a progress file
+func skipCheckedURLs(urls []URL, done map[string]bool) []URL {
+	remaining := make([]URL, 0, len(urls))
+	for _, u := range urls {
+		if !done[u.Loc] {
+			remaining = append(remaining, u)
+		}
+	}
+	return remaining
+}
+
+// OpenProgressFile opens a progress file for appending checked URLs
+func OpenProgressFile(path string) (*ProgressFile, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open progress file: %w", err)
+	}
+	return &ProgressFile{path: path, file: file}, nil
+}
+
+// Record appends a checked URL. A nil progress file does nothing.
+func (p *ProgressFile) Record(url string) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	fmt.Fprintln(p.file, url)
+}
+
+// Remove closes and deletes the progress file once the run has completed
+func (p *ProgressFile) Remove() error {
+	if p == nil {
+		return nil
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.file.Close()
+	return os.Remove(p.path)
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// Test that checked URLs are recorded and skipped on the next run
+func TestProgressFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "progress.txt")
+
+	done, err := loadProgress(path)
+	if err != nil || len(done) != 0 {
+		t.Fatalf("loadProgress() on missing file = %v, %v, want empty set", done, err)
+	}
+
+	progress, err := OpenProgressFile(path)
+	if err != nil {
+		t.Fatalf("OpenProgressFile() error = %v", err)
+	}
+	progress.Record("https://example.com/page1")
+	progress.Record("https://example.com/page3")
+
+	done, err = loadProgress(path)
+	if err != nil {
+		t.Fatalf("loadProgress() error = %v", err)
+	}
+
+	urls := []URL{{Loc: "https://example.com/page1"}, {Loc: "https://example.com/page2"}, {Loc: "https://example.com/page3"}}
+	want := []string{"https://example.com/page2"}
+	if got := urlLocs(skipCheckedURLs(urls, done)); !reflect.DeepEqual(got, want) {
+		t.Errorf("skipCheckedURLs() = %v, want %v", got, want)
+	}
+
+	if err := progress.Remove(); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("progress file still exists after Remove(), stat error = %v", err)
+	}
+
+	// A nil progress file is a no-op
+	var disabled *ProgressFile
+	disabled.Record("https://example.com/page1")
+	if err := disabled.Remove(); err != nil {
+		t.Errorf("nil Remove() error = %v", err)
+	}
+}
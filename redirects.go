@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// maxRedirectHops stops following a redirect chain that never settles
+const maxRedirectHops = 20
+
+// followRedirectChain follows the redirects of a URL one hop at a time and returns
+// every URL visited, starting with the checked URL
+func followRedirectChain(client *http.Client, start, location string) ([]string, error) {
+	chain := []string{start}
+	visited := map[string]bool{start: true}
+
+	current := start
+	for location != "" && len(chain) <= maxRedirectHops {
+		target := resolveURL(current, location)
+		chain = append(chain, target)
+		if visited[target] {
+			return chain, fmt.Errorf("redirect loop at %s", target)
+		}
+		visited[target] = true
+
+		status, next, err := headNoFollow(client, target)
+		if err != nil {
+			return chain, err
+		}
+		if status < 300 || status >= 400 {
+			break
+		}
+		current, location = target, next
+	}
+	return chain, nil
+}
+
+// checkRedirectChain records the full redirect chain of a redirecting URL and flags
+// chains with more hops than maxChain
+func checkRedirectChain(client *http.Client, result *Result, maxChain int) error {
+	chain, err := followRedirectChain(client, result.URL, result.RedirectURL)
+	result.RedirectChain = chain
+	if hops := len(chain) - 1; hops > maxChain {
+		result.addIssue("REDIRECT_CHAIN_TOO_LONG", fmt.Sprintf("%d redirects, limit is %d", hops, maxChain))
+	}
+	return err
+}
+
+// formatRedirectChainSummary reports the longest and average length of the redirect chains followed
+func formatRedirectChainSummary(results []Result) string {
+	chains, total, longest := 0, 0, 0
+	for _, result := range results {
+		if len(result.RedirectChain) == 0 {
+			continue
+		}
+		hops := len(result.RedirectChain) - 1
+		chains++
+		total += hops
+		longest = max(longest, hops)
+	}
+	avg := 0.0
+	if chains > 0 {
+		avg = float64(total) / float64(chains)
+	}
+	return fmt.Sprintf("Redirect chains: %d followed, longest %d, average %.2f redirects", chains, longest, avg)
+}
+
+// checkCanonicalRedirect cross-references the canonical URLs with the redirect chain of a
+// redirecting URL. The redirecting response must not declare itself canonical and the
+// final target must not declare another URL canonical.
+func checkCanonicalRedirect(client *http.Client, result *Result, page *Page) error {
+	if len(result.RedirectChain) < 2 {
+		return nil
+	}
+	target := result.RedirectChain[len(result.RedirectChain)-1]
+
+	result.Canonical = page.canonicalURL()
+	if result.Canonical != "" && sameURL(result.Canonical, result.URL) {
+		result.addIssue("CANONICAL_REDIRECT_CONFLICT", fmt.Sprintf("redirects to %s but declares itself canonical", target))
+	}
+
+	targetPage, err := fetchPage(client, target)
+	if err != nil {
+		return err
+	}
+	if targetPage.Status != http.StatusOK {
+		return nil
+	}
+	if canonical := targetPage.canonicalURL(); canonical != "" && !sameURL(canonical, target) {
+		result.addIssue("CANONICAL_REDIRECT_CONFLICT", fmt.Sprintf("redirect target %s declares %s canonical", target, canonical))
+	}
+	return nil
+}
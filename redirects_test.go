@@ -0,0 +1,130 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// Test for checkRedirectChain function
+func TestCheckRedirectChain(t *testing.T) {
+	redirects := map[string]string{
+		"/one":   "/two",
+		"/two":   "/three",
+		"/three": "/four",
+		"/four":  "/final",
+		"/loop":  "/loop2",
+		"/loop2": "/loop",
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if target, ok := redirects[r.URL.Path]; ok {
+			http.Redirect(w, r, target, http.StatusMovedPermanently)
+			return
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name      string
+		path      string
+		wantChain []string
+		wantCode  string
+		wantErr   bool
+	}{
+		{name: "short chain", path: "/three", wantChain: []string{"/three", "/four", "/final"}},
+		{name: "too long", path: "/one", wantChain: []string{"/one", "/two", "/three", "/four", "/final"}, wantCode: "REDIRECT_CHAIN_TOO_LONG"},
+		{name: "loop", path: "/loop", wantChain: []string{"/loop", "/loop2", "/loop"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Result{URL: server.URL + tt.path, Status: 301, IsRedirect: true, RedirectURL: redirects[tt.path]}
+			err := checkRedirectChain(server.Client(), &result, 3)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("checkRedirectChain() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			var chain []string
+			for _, u := range result.RedirectChain {
+				chain = append(chain, strings.TrimPrefix(u, server.URL))
+			}
+			if !reflect.DeepEqual(chain, tt.wantChain) {
+				t.Errorf("RedirectChain = %v, want %v", chain, tt.wantChain)
+			}
+
+			var code string
+			if len(result.Issues) > 0 {
+				code = result.Issues[0].Code
+			}
+			if code != tt.wantCode {
+				t.Errorf("issue code = %q, want %q", code, tt.wantCode)
+			}
+		})
+	}
+}
+
+// Test for formatRedirectChainSummary function
+func TestFormatRedirectChainSummary(t *testing.T) {
+	results := []Result{
+		{RedirectChain: []string{"a", "b"}},
+		{RedirectChain: []string{"a", "b", "c", "d", "e"}},
+		{Status: 200},
+	}
+	want := "Redirect chains: 2 followed, longest 4, average 2.50 redirects"
+	if got := formatRedirectChainSummary(results); got != want {
+		t.Errorf("formatRedirectChainSummary() = %q, want %q", got, want)
+	}
+}
+
+// Test for checkCanonicalRedirect function
+func TestCheckCanonicalRedirect(t *testing.T) {
+	canonicals := map[string]string{
+		"/new":     "/new",
+		"/moved":   "/elsewhere",
+		"/nocanon": "",
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if canonical := canonicals[r.URL.Path]; canonical != "" {
+			w.Write([]byte(`<html><head><link rel="canonical" href="` + canonical + `"></head></html>`))
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name          string
+		redirectHead  string
+		target        string
+		wantConflicts int
+	}{
+		{name: "consistent", target: "/new"},
+		{name: "target without canonical", target: "/nocanon"},
+		{name: "redirect declares itself canonical", redirectHead: `<link rel="canonical" href="/old">`, target: "/new", wantConflicts: 1},
+		{name: "target canonical elsewhere", target: "/moved", wantConflicts: 1},
+		{name: "both", redirectHead: `<link rel="canonical" href="/old">`, target: "/moved", wantConflicts: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page := parseTestPage(t, server.URL+"/old", `<html><head>`+tt.redirectHead+`</head></html>`)
+			result := Result{
+				URL:           server.URL + "/old",
+				Status:        301,
+				IsRedirect:    true,
+				RedirectChain: []string{server.URL + "/old", server.URL + tt.target},
+			}
+			if err := checkCanonicalRedirect(server.Client(), &result, page); err != nil {
+				t.Fatalf("checkCanonicalRedirect() error = %v", err)
+			}
+			if got := len(result.Issues); got != tt.wantConflicts {
+				t.Errorf("got %d conflicts, want %d: %+v", got, tt.wantConflicts, result.Issues)
+			}
+			for _, issue := range result.Issues {
+				if issue.Code != "CANONICAL_REDIRECT_CONFLICT" {
+					t.Errorf("issue code = %q, want CANONICAL_REDIRECT_CONFLICT", issue.Code)
+				}
+			}
+		})
+	}
+}
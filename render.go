@@ -0,0 +1,40 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// checkRendering loads a page in the browser and records its DOMContentLoaded and load times
+func checkRendering(renderer *Renderer, result *Result) error {
+	domContentLoaded, load, err := renderer.Measure(result.URL)
+	if err != nil {
+		return fmt.Errorf("rendering failed: %w", err)
+	}
+	result.DOMContentLoadedTime = domContentLoaded
+	result.RenderTime = load
+	return nil
+}
+
+// formatRenderSummary reports the average and slowest browser load times
+func formatRenderSummary(results []Result) string {
+	rendered := 0
+	var total time.Duration
+	var slowest Result
+	for _, result := range results {
+		if result.RenderTime == 0 {
+			continue
+		}
+		rendered++
+		total += result.RenderTime
+		if result.RenderTime > slowest.RenderTime {
+			slowest = result
+		}
+	}
+	if rendered == 0 {
+		return "Render times: no pages rendered"
+	}
+	avg := total / time.Duration(rendered)
+	return fmt.Sprintf("Render times: %d pages, average load %d ms, slowest %s (%d ms)",
+		rendered, avg.Milliseconds(), slowest.URL, slowest.RenderTime.Milliseconds())
+}
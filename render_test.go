@@ -0,0 +1,23 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+// Test for formatRenderSummary function
+func TestFormatRenderSummary(t *testing.T) {
+	results := []Result{
+		{URL: "https://example.com/", RenderTime: 800 * time.Millisecond},
+		{URL: "https://example.com/app", RenderTime: 2400 * time.Millisecond},
+		{URL: "https://example.com/missing", Status: 404},
+	}
+	want := "Render times: 2 pages, average load 1600 ms, slowest https://example.com/app (2400 ms)"
+	if got := formatRenderSummary(results); got != want {
+		t.Errorf("formatRenderSummary() = %q, want %q", got, want)
+	}
+
+	if got := formatRenderSummary(nil); got != "Render times: no pages rendered" {
+		t.Errorf("formatRenderSummary(nil) = %q", got)
+	}
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+)
+
+// maxRegressionsReported limits how many response time regressions the summary lists
+const maxRegressionsReported = 10
+
+// JSONReport is the machine-readable report written with -json-report
+type JSONReport struct {
+	RunID       string       `json:"run_id"`
+	SitemapURL  string       `json:"sitemap_url"`
+	GeneratedAt time.Time    `json:"generated_at"`
+	Results     []JSONResult `json:"results"`
+
+	// Domains lists the URL count per hostname when -report-domains is set
+	Domains []DomainCount `json:"domains,omitempty"`
+}
+
+// JSONResult is a checked URL in a JSON report
+type JSONResult struct {
+	URL            string  `json:"url"`
+	Status         int     `json:"status,omitempty"`
+	Error          string  `json:"error,omitempty"`
+	RedirectURL    string  `json:"redirect_url,omitempty"`
+	SourceSitemap  string  `json:"source_sitemap,omitempty"`
+	ResponseTimeMs float64 `json:"response_time_ms"`
+	Issues         []Issue `json:"issues,omitempty"`
+}
+
+// newJSONReport converts check results to a JSON report
+func newJSONReport(runID, sitemapURL string, results []Result) JSONReport {
+	report := JSONReport{
+		RunID:       runID,
+		SitemapURL:  sitemapURL,
+		GeneratedAt: time.Now().UTC(),
+		Results:     make([]JSONResult, 0, len(results)),
+	}
+	for _, result := range results {
+		entry := JSONResult{
+			URL:            result.URL,
+			Status:         result.Status,
+			RedirectURL:    result.RedirectURL,
+			SourceSitemap:  result.SourceSitemap,
+			ResponseTimeMs: float64(result.ResponseTime.Microseconds()) / 1000,
+			Issues:         result.Issues,
+		}
+		if result.Error != nil {
+			entry.Error = result.Error.Error()
+		}
+		report.Results = append(report.Results, entry)
+	}
+	return report
+}
+
+// writeJSONReport writes a report as indented JSON
+func writeJSONReport(path string, report JSONReport) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write JSON report: %w", err)
+	}
+	return nil
+}
+
+// loadJSONReport reads a report written by an earlier run
+func loadJSONReport(path string) (JSONReport, error) {
+	var report JSONReport
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return report, fmt.Errorf("failed to read report: %w", err)
+	}
+	if err := json.Unmarshal(data, &report); err != nil {
+		return report, fmt.Errorf("failed to parse report %s: %w", path, err)
+	}
+	return report, nil
+}
+
+// Regression is a URL that responded much slower than in the baseline run
+type Regression struct {
+	URL      string
+	Baseline time.Duration
+	Current  time.Duration
+}
+
+// compareResponseTimes flags URLs whose response time grew by more than factor since
+// the baseline run. Regressions are returned with the biggest increase first.
+func compareResponseTimes(baseline JSONReport, results []Result, factor float64) []Regression {
+	baselineTimes := make(map[string]time.Duration)
+	for _, entry := range baseline.Results {
+		if entry.Error == "" && entry.ResponseTimeMs > 0 {
+			baselineTimes[entry.URL] = time.Duration(entry.ResponseTimeMs * float64(time.Millisecond))
+		}
+	}
+
+	var regressions []Regression
+	for i := range results {
+		result := &results[i]
+		before, ok := baselineTimes[result.URL]
+		if !ok || result.Error != nil || result.FromCache {
+			continue
+		}
+		if float64(result.ResponseTime) > float64(before)*factor {
+			regressions = append(regressions, Regression{URL: result.URL, Baseline: before, Current: result.ResponseTime})
+			result.addIssue("RESPONSE_TIME_REGRESSION", fmt.Sprintf("response time %v, baseline %v (%.1fx)",
+				result.ResponseTime.Round(time.Millisecond), before.Round(time.Millisecond), float64(result.ResponseTime)/float64(before)))
+		}
+	}
+
+	sort.Slice(regressions, func(i, j int) bool {
+		return regressions[i].Current-regressions[i].Baseline > regressions[j].Current-regressions[j].Baseline
+	})
+	return regressions
+}
+
+// formatRegressions returns summary lines for the response time regressions
+func formatRegressions(regressions []Regression) []string {
+	lines := []string{fmt.Sprintf("Response time regressions: %d URLs", len(regressions))}
+	for i, r := range regressions {
+		if i == maxRegressionsReported {
+			break
+		}
+		lines = append(lines, fmt.Sprintf("  %s: %v -> %v (+%v)", r.URL,
+			r.Baseline.Round(time.Millisecond), r.Current.Round(time.Millisecond), (r.Current-r.Baseline).Round(time.Millisecond)))
+	}
+	return lines
+}
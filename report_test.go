@@ -0,0 +1,65 @@
+package main
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// Test that a JSON report survives a round trip through a file
+func TestJSONReportRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.json")
+	results := []Result{
+		{URL: "https://example.com/a", Status: 200, ResponseTime: 150 * time.Millisecond},
+		{URL: "https://example.com/b", Error: errors.New("timeout"), Issues: []Issue{{Code: "HOST_TIMEOUT", Message: "slow"}}},
+	}
+
+	if err := writeJSONReport(path, newJSONReport("run123", "https://example.com/sitemap.xml", results)); err != nil {
+		t.Fatalf("writeJSONReport() error = %v", err)
+	}
+	report, err := loadJSONReport(path)
+	if err != nil {
+		t.Fatalf("loadJSONReport() error = %v", err)
+	}
+
+	if report.RunID != "run123" || len(report.Results) != 2 {
+		t.Fatalf("loadJSONReport() = %+v, want run123 with 2 results", report)
+	}
+	if got := report.Results[0].ResponseTimeMs; got != 150 {
+		t.Errorf("ResponseTimeMs = %v, want 150", got)
+	}
+	if got := report.Results[1]; got.Error != "timeout" || len(got.Issues) != 1 {
+		t.Errorf("second result = %+v, want error and one issue", got)
+	}
+}
+
+// Test for compareResponseTimes function
+func TestCompareResponseTimes(t *testing.T) {
+	baseline := JSONReport{Results: []JSONResult{
+		{URL: "https://example.com/a", ResponseTimeMs: 100},
+		{URL: "https://example.com/b", ResponseTimeMs: 100},
+		{URL: "https://example.com/c", ResponseTimeMs: 50},
+		{URL: "https://example.com/d", ResponseTimeMs: 100, Error: "timeout"},
+	}}
+	results := []Result{
+		{URL: "https://example.com/a", ResponseTime: 150 * time.Millisecond},
+		{URL: "https://example.com/b", ResponseTime: 300 * time.Millisecond},
+		{URL: "https://example.com/c", ResponseTime: 500 * time.Millisecond},
+		{URL: "https://example.com/d", ResponseTime: 900 * time.Millisecond},
+		{URL: "https://example.com/new", ResponseTime: 900 * time.Millisecond},
+	}
+
+	regressions := compareResponseTimes(baseline, results, 2.0)
+
+	if len(regressions) != 2 {
+		t.Fatalf("compareResponseTimes() = %+v, want 2 regressions", regressions)
+	}
+	// The biggest increase comes first
+	if regressions[0].URL != "https://example.com/c" || regressions[1].URL != "https://example.com/b" {
+		t.Errorf("regressions order = %s, %s, want /c then /b", regressions[0].URL, regressions[1].URL)
+	}
+	if countIssues(results, "RESPONSE_TIME_REGRESSION") != 2 || len(results[0].Issues) != 0 {
+		t.Errorf("RESPONSE_TIME_REGRESSION issues not added to the right results: %+v", results)
+	}
+}
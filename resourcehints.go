@@ -0,0 +1,39 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+)
+
+// lookupHost resolves a hostname, replaced in tests to avoid real DNS queries
+var lookupHost = net.LookupHost
+
+// resourceHintHosts returns the unique hostnames of a page's rel=preconnect and rel=dns-prefetch links
+func (p *Page) resourceHintHosts() []string {
+	seen := make(map[string]bool)
+	var hosts []string
+	for _, rel := range []string{"preconnect", "dns-prefetch"} {
+		for _, link := range p.findRelLinks(rel) {
+			parsedURL, err := url.Parse(link)
+			if err != nil || parsedURL.Hostname() == "" || seen[parsedURL.Hostname()] {
+				continue
+			}
+			seen[parsedURL.Hostname()] = true
+			hosts = append(hosts, parsedURL.Hostname())
+		}
+	}
+	return hosts
+}
+
+// checkResourceHints resolves the hostnames of a page's resource hints and flags the
+// ones that don't resolve, as the browser would waste a lookup on them
+func checkResourceHints(result *Result, page *Page) {
+	for _, host := range page.resourceHintHosts() {
+		if _, err := lookupHost(host); err != nil {
+			msg := fmt.Sprintf("%s: %v", host, err)
+			result.ResourceHintIssues = append(result.ResourceHintIssues, msg)
+			result.addIssue("RESOURCE_HINT_DNS_FAIL", msg)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"errors"
+	"testing"
+)
+
+// Test for checkResourceHints function
+func TestCheckResourceHints(t *testing.T) {
+	originalLookup := lookupHost
+	defer func() { lookupHost = originalLookup }()
+
+	var looked []string
+	lookupHost = func(host string) ([]string, error) {
+		looked = append(looked, host)
+		if host == "gone.example.net" {
+			return nil, errors.New("no such host")
+		}
+		return []string{"192.0.2.1"}, nil
+	}
+
+	page := parseTestPage(t, "https://example.com/", `<html><head>
+<link rel="preconnect" href="https://fonts.example.org" crossorigin>
+<link rel="dns-prefetch" href="//gone.example.net">
+<link rel="dns-prefetch" href="https://fonts.example.org">
+<link rel="stylesheet" href="https://static.example.com/site.css">
+</head></html>`)
+	result := Result{URL: page.URL, Status: 200}
+	checkResourceHints(&result, page)
+
+	if !equalStringSlices(looked, []string{"fonts.example.org", "gone.example.net"}) {
+		t.Errorf("looked up %v, want each resource hint host once", looked)
+	}
+	if len(result.ResourceHintIssues) != 1 || len(result.Issues) != 1 || result.Issues[0].Code != "RESOURCE_HINT_DNS_FAIL" {
+		t.Errorf("ResourceHintIssues = %v, Issues = %v, want one RESOURCE_HINT_DNS_FAIL", result.ResourceHintIssues, result.Issues)
+	}
+}
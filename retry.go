@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"time"
+)
+
+// retryBaseDelay is the wait before the first retry of a timed out request
+var retryBaseDelay = time.Second
+
+// cancelOnClose releases a request context once the response body is closed
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+// Close implements io.Closer
+func (c cancelOnClose) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+	return err
+}
+
+// isTimeout reports whether a request failed because it took too long
+func isTimeout(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// doWithBackoff sends a request, retrying up to maxRetries times when it times out.
+// Each retry waits twice as long as the one before. The request timeout doubles for
+// retries, capped at twice the client timeout. It returns the number of retries made.
+func doWithBackoff(client *http.Client, req *http.Request, maxRetries int) (*http.Response, int, error) {
+	delay := retryBaseDelay
+	for retries := 0; ; retries++ {
+		resp, err := doAttempt(client, req, retries)
+		if err == nil || !isTimeout(err) || retries >= maxRetries || req.Context().Err() != nil {
+			return resp, retries, err
+		}
+		time.Sleep(delay)
+		delay *= 2
+	}
+}
+
+// doAttempt sends one attempt of a request, allowing retries twice the client timeout
+func doAttempt(client *http.Client, req *http.Request, retries int) (*http.Response, error) {
+	if retries == 0 || client.Timeout == 0 {
+		return client.Do(req)
+	}
+
+	// The context deadline replaces the client timeout for this attempt
+	ctx, cancel := context.WithTimeout(req.Context(), 2*client.Timeout)
+	relaxed := *client
+	relaxed.Timeout = 0
+
+	resp, err := relaxed.Do(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return resp, err
+	}
+	resp.Body = cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// formatRetrySummary reports the total and average number of timeout retries per URL
+func formatRetrySummary(results []Result) string {
+	total := 0
+	for _, result := range results {
+		total += result.RetryCount
+	}
+	avg := 0.0
+	if len(results) > 0 {
+		avg = float64(total) / float64(len(results))
+	}
+	return fmt.Sprintf("Timeout retries: %d (%.2f per URL)", total, avg)
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// Test that doWithBackoff retries timed out requests
+func TestDoWithBackoff(t *testing.T) {
+	defer func(delay time.Duration) { retryBaseDelay = delay }(retryBaseDelay)
+	retryBaseDelay = time.Millisecond
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The first request is slower than the client timeout, the second fits the doubled timeout
+		switch requests.Add(1) {
+		case 1:
+			time.Sleep(200 * time.Millisecond)
+		case 2:
+			time.Sleep(70 * time.Millisecond)
+		}
+		if r.URL.Path == "/slow" {
+			time.Sleep(300 * time.Millisecond)
+		}
+	}))
+	defer server.Close()
+
+	client := server.Client()
+	client.Timeout = 50 * time.Millisecond
+
+	req, _ := http.NewRequest("HEAD", server.URL+"/page", nil)
+	resp, retries, err := doWithBackoff(client, req, 3)
+	if err != nil {
+		t.Fatalf("doWithBackoff() error = %v", err)
+	}
+	resp.Body.Close()
+	if retries != 1 || resp.StatusCode != http.StatusOK {
+		t.Errorf("doWithBackoff() = %d after %d retries, want 200 after 1 retry", resp.StatusCode, retries)
+	}
+
+	// A URL that always times out gives up after maxRetries
+	req, _ = http.NewRequest("HEAD", server.URL+"/slow", nil)
+	_, retries, err = doWithBackoff(client, req, 2)
+	if err == nil || !isTimeout(err) {
+		t.Errorf("doWithBackoff() error = %v, want timeout", err)
+	}
+	if retries != 2 {
+		t.Errorf("doWithBackoff() retries = %d, want 2", retries)
+	}
+}
+
+// Test for formatRetrySummary function
+func TestFormatRetrySummary(t *testing.T) {
+	results := []Result{{RetryCount: 3}, {RetryCount: 0}, {RetryCount: 1}, {}}
+	if got, want := formatRetrySummary(results), "Timeout retries: 4 (1.00 per URL)"; got != want {
+		t.Errorf("formatRetrySummary() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// robotsRule is an Allow or Disallow line of a robots.txt
+type robotsRule struct {
+	Allow   bool
+	Pattern *regexp.Regexp
+	Length  int
+}
+
+// parseRobots returns the rules of a robots.txt that apply to all user agents
+func parseRobots(robots string) []robotsRule {
+	var rules []robotsRule
+	applies, inAgents := false, false
+	for _, line := range strings.Split(robots, "\n") {
+		line, _, _ = strings.Cut(line, "#")
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		name, value = strings.ToLower(strings.TrimSpace(name)), strings.TrimSpace(value)
+
+		if name == "user-agent" {
+			// Consecutive user-agent lines share the rules that follow them
+			if !inAgents {
+				applies = false
+			}
+			inAgents = true
+			applies = applies || value == "*"
+			continue
+		}
+		inAgents = false
+		if !applies || (name != "allow" && name != "disallow") || value == "" {
+			continue
+		}
+		rules = append(rules, robotsRule{
+			Allow:   name == "allow",
+			Pattern: robotsPattern(value),
+			Length:  len(value),
+		})
+	}
+	return rules
+}
+
+// robotsPattern compiles a robots.txt path pattern, where * matches any characters
+// and a trailing $ anchors the end of the path
+func robotsPattern(value string) *regexp.Regexp {
+	anchored := strings.HasSuffix(value, "$")
+	value = strings.TrimSuffix(value, "$")
+	pattern := "^" + strings.ReplaceAll(regexp.QuoteMeta(value), `\*`, ".*")
+	if anchored {
+		pattern += "$"
+	}
+	return regexp.MustCompile(pattern)
+}
+
+// robotsAllowed reports whether rules allow crawling a path. The longest matching
+// rule wins and Allow wins a tie, as search engines resolve conflicting rules.
+func robotsAllowed(rules []robotsRule, path string) bool {
+	allowed, longest := true, -1
+	for _, rule := range rules {
+		if !rule.Pattern.MatchString(path) {
+			continue
+		}
+		if rule.Length > longest || (rule.Length == longest && rule.Allow) {
+			allowed, longest = rule.Allow, rule.Length
+		}
+	}
+	return allowed
+}
+
+// robotsEntry is the outcome of fetching a robots.txt, filled in once
+type robotsEntry struct {
+	once  sync.Once
+	rules []robotsRule
+	err   error
+}
+
+// RobotsChecker fetches robots.txt files and caches their rules per host
+type RobotsChecker struct {
+	client *http.Client
+	cache  map[string]*robotsEntry
+	mu     sync.Mutex
+}
+
+// NewRobotsChecker creates a new robots.txt checker. Crawlers follow redirects of a
+// robots.txt, so the checker does too even when client doesn't.
+func NewRobotsChecker(client *http.Client) *RobotsChecker {
+	follow := *client
+	follow.CheckRedirect = nil
+	return &RobotsChecker{
+		client: &follow,
+		cache:  make(map[string]*robotsEntry),
+	}
+}
+
+// Allowed reports whether the robots.txt of a URL's host lets crawlers fetch it
+func (r *RobotsChecker) Allowed(rawURL string) (bool, error) {
+	parsedURL, err := url.Parse(rawURL)
+	if err != nil {
+		return false, err
+	}
+	rules, err := r.rules(parsedURL.Scheme, parsedURL.Host)
+	if err != nil {
+		return false, err
+	}
+	return robotsAllowed(rules, parsedURL.RequestURI()), nil
+}
+
+// rules returns the robots.txt rules of a host, fetching the file only once per host
+func (r *RobotsChecker) rules(scheme, host string) ([]robotsRule, error) {
+	key := scheme + "://" + host
+	r.mu.Lock()
+	entry, ok := r.cache[key]
+	if !ok {
+		entry = &robotsEntry{}
+		r.cache[key] = entry
+	}
+	r.mu.Unlock()
+
+	// URLs of the same host checked at the same time wait for a single request
+	entry.once.Do(func() {
+		entry.rules, entry.err = fetchRobotsRules(r.client, key+"/robots.txt")
+	})
+
+	// A failing robots.txt is not cached so it is retried
+	if entry.err != nil {
+		r.mu.Lock()
+		if r.cache[key] == entry {
+			delete(r.cache, key)
+		}
+		r.mu.Unlock()
+	}
+	return entry.rules, entry.err
+}
+
+// fetchRobotsRules fetches a robots.txt and returns its rules. A missing robots.txt
+// allows everything.
+func fetchRobotsRules(client *http.Client, robotsURL string) ([]robotsRule, error) {
+	page, err := fetchPage(client, robotsURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch robots.txt: %w", err)
+	}
+	switch {
+	case page.Status == http.StatusOK:
+		return parseRobots(string(page.Body)), nil
+	case page.Status >= 500:
+		return nil, fmt.Errorf("robots.txt returned status %d", page.Status)
+	}
+	return nil, nil
+}
+
+// checkRobots flags URLs that robots.txt blocks crawlers from
+func checkRobots(checker *RobotsChecker, result *Result) error {
+	allowed, err := checker.Allowed(result.URL)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		result.RobotsBlocked = true
+		result.addIssue("ROBOTS_BLOCKED", "URL is disallowed by robots.txt")
+	}
+	return nil
+}
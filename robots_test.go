@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Test for robotsAllowed function
+func TestRobotsAllowed(t *testing.T) {
+	rules := parseRobots(`# example
+User-agent: Googlebot
+Disallow: /
+
+User-agent: bingbot
+User-agent: *
+Disallow: /private/
+Allow: /private/public
+Disallow: /*.pdf$
+Disallow:
+`)
+
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"/", true},
+		{"/blog/post", true},
+		{"/private/", false},
+		{"/private/page?x=1", false},
+		{"/private/public/page", true},
+		{"/files/report.pdf", false},
+		{"/files/report.pdf?download=1", true},
+	}
+
+	for _, tt := range tests {
+		if got := robotsAllowed(rules, tt.path); got != tt.want {
+			t.Errorf("robotsAllowed(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+// Test for RobotsChecker
+func TestRobotsChecker(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			requests++
+			fmt.Fprint(w, "User-agent: *\nDisallow: /admin\n")
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	checker := NewRobotsChecker(server.Client())
+	blocked := Result{URL: server.URL + "/admin/login", Status: 200}
+	if err := checkRobots(checker, &blocked); err != nil {
+		t.Fatalf("checkRobots() error = %v", err)
+	}
+	allowed := Result{URL: server.URL + "/about", Status: 200}
+	if err := checkRobots(checker, &allowed); err != nil {
+		t.Fatalf("checkRobots() error = %v", err)
+	}
+
+	if !blocked.RobotsBlocked || countIssues([]Result{blocked}, "ROBOTS_BLOCKED") != 1 {
+		t.Errorf("blocked result = %+v, want ROBOTS_BLOCKED", blocked)
+	}
+	if allowed.RobotsBlocked || len(allowed.Issues) != 0 {
+		t.Errorf("allowed result = %+v, want no issues", allowed)
+	}
+	if requests != 1 {
+		t.Errorf("robots.txt fetched %d times, want 1", requests)
+	}
+}
+
+// Test for a host without a robots.txt
+func TestRobotsCheckerMissing(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	allowed, err := NewRobotsChecker(server.Client()).Allowed(server.URL + "/page")
+	if err != nil || !allowed {
+		t.Errorf("Allowed() = %v, %v, want true, nil", allowed, err)
+	}
+}
+
+// Test that a redirected robots.txt is followed, even with a client that doesn't follow redirects
+func TestRobotsCheckerRedirect(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/robots.txt":
+			http.Redirect(w, r, "/robots-live.txt", http.StatusMovedPermanently)
+		case "/robots-live.txt":
+			fmt.Fprint(w, "User-agent: *\nDisallow: /private\n")
+		}
+	}))
+	defer server.Close()
+
+	client := server.Client()
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error { return http.ErrUseLastResponse }
+
+	allowed, err := NewRobotsChecker(client).Allowed(server.URL + "/private/page")
+	if err != nil || allowed {
+		t.Errorf("Allowed() = %v, %v, want false, nil", allowed, err)
+	}
+}
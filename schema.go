@@ -0,0 +1,151 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// schemaRequiredProperties lists the properties a schema.org type needs to be
+// eligible for rich results. Types not listed here only need a name.
+var schemaRequiredProperties = map[string][]string{
+	"Product":        {"name", "description"},
+	"Article":        {"headline", "author", "datePublished"},
+	"NewsArticle":    {"headline", "author", "datePublished"},
+	"BlogPosting":    {"headline", "author", "datePublished"},
+	"Event":          {"name", "startDate", "location"},
+	"Recipe":         {"name", "image", "recipeIngredient"},
+	"Organization":   {"name", "url"},
+	"LocalBusiness":  {"name", "address"},
+	"Person":         {"name"},
+	"BreadcrumbList": {"itemListElement"},
+	"FAQPage":        {"mainEntity"},
+	"VideoObject":    {"name", "thumbnailUrl", "uploadDate"},
+	"JobPosting":     {"title", "description", "datePosted", "hiringOrganization"},
+}
+
+// schemaEntity is a structured data item found on a page
+type schemaEntity struct {
+	Type   string
+	Format string
+	Props  map[string]bool
+}
+
+// jsonLDEntities collects the typed items of a decoded JSON-LD document, including
+// the ones of top-level arrays and @graph entries
+func jsonLDEntities(doc interface{}) []schemaEntity {
+	var entities []schemaEntity
+	switch v := doc.(type) {
+	case []interface{}:
+		for _, item := range v {
+			entities = append(entities, jsonLDEntities(item)...)
+		}
+	case map[string]interface{}:
+		types := jsonLDTypes(map[string]interface{}{"@type": v["@type"]})
+		if len(types) > 0 {
+			props := make(map[string]bool)
+			for key, value := range v {
+				if !strings.HasPrefix(key, "@") && value != nil && value != "" {
+					props[key] = true
+				}
+			}
+			for _, t := range types {
+				entities = append(entities, schemaEntity{Type: t, Format: "JSON-LD", Props: props})
+			}
+		}
+		if graph, ok := v["@graph"]; ok {
+			entities = append(entities, jsonLDEntities(graph)...)
+		}
+	}
+	return entities
+}
+
+// microdataEntities returns the items of the page marked up with itemscope and itemtype
+func (p *Page) microdataEntities() []schemaEntity {
+	var entities []schemaEntity
+	walkHTML(p.Doc, func(n *html.Node) {
+		if _, ok := attr(n, "itemscope"); !ok {
+			return
+		}
+		itemType, ok := attr(n, "itemtype")
+		if !ok {
+			return
+		}
+		props := make(map[string]bool)
+		collectItemProps(n, props)
+		for _, t := range strings.Fields(itemType) {
+			// itemtype holds full URLs such as https://schema.org/Product
+			t = t[strings.LastIndex(t, "/")+1:]
+			entities = append(entities, schemaEntity{Type: t, Format: "microdata", Props: props})
+		}
+	})
+	return entities
+}
+
+// collectItemProps records the itemprop names below an item, without descending
+// into nested items whose properties belong to them
+func collectItemProps(n *html.Node, props map[string]bool) {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type != html.ElementNode {
+			continue
+		}
+		if names, ok := attr(c, "itemprop"); ok {
+			for _, name := range strings.Fields(names) {
+				props[name] = true
+			}
+		}
+		if _, nested := attr(c, "itemscope"); nested {
+			continue
+		}
+		collectItemProps(c, props)
+	}
+}
+
+// schemaEntities returns the structured data items of a page from both JSON-LD and microdata
+func (p *Page) schemaEntities() []schemaEntity {
+	var entities []schemaEntity
+	for _, block := range p.jsonLDBlocks() {
+		var doc interface{}
+		if err := json.Unmarshal([]byte(block), &doc); err != nil {
+			continue
+		}
+		entities = append(entities, jsonLDEntities(doc)...)
+	}
+	return append(entities, p.microdataEntities()...)
+}
+
+// missingSchemaProperties returns the required properties of a type an item lacks
+func missingSchemaProperties(entity schemaEntity) []string {
+	required, ok := schemaRequiredProperties[entity.Type]
+	if !ok {
+		required = []string{"name"}
+	}
+	var missing []string
+	for _, prop := range required {
+		if !entity.Props[prop] {
+			missing = append(missing, prop)
+		}
+	}
+	return missing
+}
+
+// checkSchema verifies that every item of the required type on a page has the
+// properties that type needs
+func checkSchema(result *Result, page *Page, requiredType string) {
+	for _, entity := range page.schemaEntities() {
+		if entity.Type != requiredType {
+			continue
+		}
+		missing := missingSchemaProperties(entity)
+		if len(missing) == 0 {
+			continue
+		}
+		sort.Strings(missing)
+		issue := fmt.Sprintf("%s (%s) missing %s", entity.Type, entity.Format, strings.Join(missing, ", "))
+		result.SchemaIssues = append(result.SchemaIssues, issue)
+		result.addIssue("SCHEMA_INCOMPLETE", issue)
+	}
+}
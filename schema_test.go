@@ -0,0 +1,67 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+// Test for checkSchema function
+func TestCheckSchema(t *testing.T) {
+	tests := []struct {
+		name         string
+		body         string
+		requiredType string
+		want         []string
+	}{
+		{
+			name:         "complete json-ld product",
+			body:         `<script type="application/ld+json">{"@type": "Product", "name": "Mug", "description": "A mug"}</script>`,
+			requiredType: "Product",
+		},
+		{
+			name:         "incomplete json-ld product",
+			body:         `<script type="application/ld+json">{"@graph": [{"@type": "Product", "name": "Mug", "description": ""}]}</script>`,
+			requiredType: "Product",
+			want:         []string{"Product (JSON-LD) missing description"},
+		},
+		{
+			name:         "other types ignored",
+			body:         `<script type="application/ld+json">{"@type": "Article"}</script>`,
+			requiredType: "Product",
+		},
+		{
+			name: "incomplete microdata article",
+			body: `<div itemscope itemtype="https://schema.org/Article"><h1 itemprop="headline">News</h1>
+				<div itemprop="author" itemscope itemtype="https://schema.org/Person"><span itemprop="name">Ann</span></div></div>`,
+			requiredType: "Article",
+			want:         []string{"Article (microdata) missing datePublished"},
+		},
+		{
+			name:         "nested item properties stay with the nested item",
+			body:         `<div itemscope itemtype="https://schema.org/Event"><div itemprop="location" itemscope itemtype="https://schema.org/Place"><span itemprop="name">Hall</span></div></div>`,
+			requiredType: "Event",
+			want:         []string{"Event (microdata) missing name, startDate"},
+		},
+		{
+			name:         "unknown type needs a name",
+			body:         `<script type="application/ld+json">{"@type": "Course"}</script>`,
+			requiredType: "Course",
+			want:         []string{"Course (JSON-LD) missing name"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page := parseTestPage(t, "https://example.com/", `<html><body>`+tt.body+`</body></html>`)
+			result := Result{URL: page.URL, Status: 200}
+			checkSchema(&result, page, tt.requiredType)
+
+			if !reflect.DeepEqual(result.SchemaIssues, tt.want) {
+				t.Errorf("SchemaIssues = %v, want %v", result.SchemaIssues, tt.want)
+			}
+			if got := countIssues([]Result{result}, "SCHEMA_INCOMPLETE"); got != min(len(tt.want), 1) {
+				t.Errorf("SCHEMA_INCOMPLETE count = %d, want %d", got, min(len(tt.want), 1))
+			}
+		})
+	}
+}
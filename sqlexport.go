@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// sqlTable is the table -output-sql inserts the results into
+const sqlTable = "sitemap_results"
+
+// sqlCreateTable creates the results table. The types are understood by MySQL, PostgreSQL and SQLite.
+const sqlCreateTable = `CREATE TABLE IF NOT EXISTS ` + sqlTable + ` (
+  url VARCHAR(2048) NOT NULL,
+  run_ts TIMESTAMP NOT NULL,
+  status_code INTEGER,
+  is_redirect BOOLEAN NOT NULL,
+  redirect_url VARCHAR(2048),
+  error_msg TEXT,
+  response_time_ms DOUBLE PRECISION,
+  source_sitemap VARCHAR(2048)
+);
+`
+
+// SQL dialects accepted by -sql-dialect. They only differ in how string literals are escaped.
+const (
+	sqlDialectStandard = "standard"
+	sqlDialectMySQL    = "mysql"
+)
+
+// validSQLDialect reports whether dialect is one -sql-dialect accepts
+func validSQLDialect(dialect string) bool {
+	return dialect == sqlDialectStandard || dialect == sqlDialectMySQL
+}
+
+// sqlString quotes a value as a string literal of the dialect, or NULL when it is empty.
+// MySQL treats a backslash as an escape character by default, so it is doubled there;
+// otherwise "\'" would end the literal early.
+func sqlString(value, dialect string) string {
+	if value == "" {
+		return "NULL"
+	}
+	if dialect == sqlDialectMySQL {
+		value = strings.ReplaceAll(value, `\`, `\\`)
+	}
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+// writeSQL writes the results as a CREATE TABLE statement followed by one INSERT per URL
+func writeSQL(w io.Writer, runTS time.Time, results []Result, dialect string) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprint(bw, sqlCreateTable)
+	fmt.Fprintln(bw, "BEGIN;")
+
+	ts := sqlString(runTS.UTC().Format("2006-01-02 15:04:05"), dialect)
+	for _, result := range results {
+		status := "NULL"
+		if result.Status != 0 {
+			status = fmt.Sprintf("%d", result.Status)
+		}
+		isRedirect := "FALSE"
+		if result.IsRedirect {
+			isRedirect = "TRUE"
+		}
+		errorMsg := ""
+		if result.Error != nil {
+			errorMsg = result.Error.Error()
+		}
+		fmt.Fprintf(bw, "INSERT INTO %s (url, run_ts, status_code, is_redirect, redirect_url, error_msg, response_time_ms, source_sitemap) "+
+			"VALUES (%s, %s, %s, %s, %s, %s, %.3f, %s);\n",
+			sqlTable, sqlString(result.URL, dialect), ts, status, isRedirect, sqlString(result.RedirectURL, dialect), sqlString(errorMsg, dialect),
+			float64(result.ResponseTime.Microseconds())/1000, sqlString(result.SourceSitemap, dialect))
+	}
+
+	fmt.Fprintln(bw, "COMMIT;")
+	return bw.Flush()
+}
+
+// saveSQL writes the results as SQL statements to a file
+func saveSQL(path string, runTS time.Time, results []Result, dialect string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create SQL output: %w", err)
+	}
+	err = writeSQL(file, runTS, results, dialect)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
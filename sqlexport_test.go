@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Test for sqlString function
+func TestSQLString(t *testing.T) {
+	tests := []struct {
+		value    string
+		standard string
+		mysql    string
+	}{
+		{"", "NULL", "NULL"},
+		{"https://example.com/", "'https://example.com/'", "'https://example.com/'"},
+		{"https://example.com/o'brien", "'https://example.com/o''brien'", "'https://example.com/o''brien'"},
+		{"dial tcp: 'x' ''y'' not found", "'dial tcp: ''x'' ''''y'''' not found'", "'dial tcp: ''x'' ''''y'''' not found'"},
+		{`https://example.com/a\'b`, `'https://example.com/a\''b'`, `'https://example.com/a\\''b'`},
+	}
+	for _, tt := range tests {
+		if got := sqlString(tt.value, sqlDialectStandard); got != tt.standard {
+			t.Errorf("sqlString(%q, standard) = %s, want %s", tt.value, got, tt.standard)
+		}
+		if got := sqlString(tt.value, sqlDialectMySQL); got != tt.mysql {
+			t.Errorf("sqlString(%q, mysql) = %s, want %s", tt.value, got, tt.mysql)
+		}
+	}
+}
+
+// Test for writeSQL function
+func TestWriteSQL(t *testing.T) {
+	runTS := time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC)
+	results := []Result{
+		{URL: "https://example.com/", Status: 200, ResponseTime: 1500 * time.Microsecond, SourceSitemap: "https://example.com/sitemap.xml"},
+		{URL: "https://example.com/old", Status: 301, IsRedirect: true, RedirectURL: "https://example.com/new"},
+		{URL: "https://example.com/it's", Error: errors.New("connection refused")},
+	}
+
+	var buf bytes.Buffer
+	if err := writeSQL(&buf, runTS, results, sqlDialectStandard); err != nil {
+		t.Fatalf("writeSQL() error = %v", err)
+	}
+	output := buf.String()
+
+	if !strings.HasPrefix(output, "CREATE TABLE IF NOT EXISTS sitemap_results (") {
+		t.Errorf("output does not start with CREATE TABLE:\n%s", output)
+	}
+	for _, want := range []string{
+		"VALUES ('https://example.com/', '2024-03-01 12:30:00', 200, FALSE, NULL, NULL, 1.500, 'https://example.com/sitemap.xml');",
+		"VALUES ('https://example.com/old', '2024-03-01 12:30:00', 301, TRUE, 'https://example.com/new', NULL, 0.000, NULL);",
+		"VALUES ('https://example.com/it''s', '2024-03-01 12:30:00', NULL, FALSE, NULL, 'connection refused', 0.000, NULL);",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q:\n%s", want, output)
+		}
+	}
+	if got := strings.Count(output, "INSERT INTO sitemap_results"); got != 3 {
+		t.Errorf("got %d INSERT statements, want 3", got)
+	}
+	if !strings.HasSuffix(output, "COMMIT;\n") {
+		t.Errorf("output does not end with COMMIT:\n%s", output)
+	}
+}
+
+// Test that a backslash before a quote can't end a MySQL literal early
+func TestWriteSQLMySQLBackslash(t *testing.T) {
+	runTS := time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC)
+	results := []Result{
+		{URL: `https://example.com/x\'`, Status: 301, IsRedirect: true, RedirectURL: `https://example.com/\'); DROP TABLE sitemap_results; --`},
+	}
+
+	var buf bytes.Buffer
+	if err := writeSQL(&buf, runTS, results, sqlDialectMySQL); err != nil {
+		t.Fatalf("writeSQL() error = %v", err)
+	}
+
+	want := `VALUES ('https://example.com/x\\''', '2024-03-01 12:30:00', 301, TRUE, 'https://example.com/\\''); DROP TABLE sitemap_results; --', NULL, 0.000, NULL);`
+	if !strings.Contains(buf.String(), want) {
+		t.Errorf("output missing %q:\n%s", want, buf.String())
+	}
+}
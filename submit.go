@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// PingEndpoint is a search engine endpoint that accepts sitemap submissions
+type PingEndpoint struct {
+	Name string
+	URL  string
+}
+
+// pingEndpoints are the search engines a sitemap is submitted to with -submit
+var pingEndpoints = []PingEndpoint{
+	{Name: "Google", URL: "https://www.google.com/ping"},
+	{Name: "Bing", URL: "https://www.bing.com/ping"},
+}
+
+// selectPingEndpoints returns the endpoints chosen with -google-only or -bing-only
+func selectPingEndpoints(googleOnly, bingOnly bool) []PingEndpoint {
+	var selected []PingEndpoint
+	for _, endpoint := range pingEndpoints {
+		if googleOnly && endpoint.Name != "Google" || bingOnly && endpoint.Name != "Bing" {
+			continue
+		}
+		selected = append(selected, endpoint)
+	}
+	return selected
+}
+
+// submitSitemap notifies each endpoint of the sitemap and logs the response it returns
+func submitSitemap(client *http.Client, sitemapURL string, endpoints []PingEndpoint, logger *Logger) int {
+	failed := 0
+	for _, endpoint := range endpoints {
+		msg, err := pingSitemap(client, endpoint, sitemapURL)
+		if err != nil {
+			failed++
+			msg = fmt.Sprintf("SUBMIT FAILED (%s): %v", endpoint.Name, err)
+		}
+		fmt.Println(msg)
+		if logger != nil {
+			logger.Log(msg)
+		}
+	}
+	return failed
+}
+
+// pingSitemap submits the sitemap to one endpoint
+func pingSitemap(client *http.Client, endpoint PingEndpoint, sitemapURL string) (string, error) {
+	req, err := http.NewRequest("GET", endpoint.URL+"?sitemap="+url.QueryEscape(sitemapURL), nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("User-Agent", userAgent)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return fmt.Sprintf("Submitted sitemap to %s (Status: %d)", endpoint.Name, resp.StatusCode), nil
+}
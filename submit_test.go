@@ -0,0 +1,59 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Test for selectPingEndpoints function
+func TestSelectPingEndpoints(t *testing.T) {
+	tests := []struct {
+		name       string
+		googleOnly bool
+		bingOnly   bool
+		want       []string
+	}{
+		{name: "all", want: []string{"Google", "Bing"}},
+		{name: "google only", googleOnly: true, want: []string{"Google"}},
+		{name: "bing only", bingOnly: true, want: []string{"Bing"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var names []string
+			for _, endpoint := range selectPingEndpoints(tt.googleOnly, tt.bingOnly) {
+				names = append(names, endpoint.Name)
+			}
+			if !equalStringSlices(names, tt.want) {
+				t.Errorf("selectPingEndpoints() = %v, want %v", names, tt.want)
+			}
+		})
+	}
+}
+
+// Test for submitSitemap function
+func TestSubmitSitemap(t *testing.T) {
+	var submitted string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/gone" {
+			http.Error(w, "Sitemaps ping is deprecated", http.StatusNotFound)
+			return
+		}
+		submitted = r.URL.Query().Get("sitemap")
+	}))
+	defer server.Close()
+
+	endpoints := []PingEndpoint{
+		{Name: "Working", URL: server.URL + "/ping"},
+		{Name: "Retired", URL: server.URL + "/gone"},
+	}
+	failed := submitSitemap(server.Client(), "https://example.com/sitemap.xml?lang=en", endpoints, nil)
+
+	if failed != 1 {
+		t.Errorf("submitSitemap() = %d failures, want 1", failed)
+	}
+	if submitted != "https://example.com/sitemap.xml?lang=en" {
+		t.Errorf("submitted sitemap = %q", submitted)
+	}
+}
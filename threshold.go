@@ -0,0 +1,41 @@
+package main
+
+import "fmt"
+
+// FailThreshold decides whether the broken URLs found should fail the run
+type FailThreshold struct {
+	// Pct fails the run when more than this percentage of URLs is broken, negative disables it
+	Pct float64
+	// Abs fails the run when more than this number of URLs is broken, negative disables it
+	Abs int
+}
+
+// countBroken returns how many results failed with an error or a 4xx/5xx status
+func countBroken(results []Result) int {
+	count := 0
+	for _, result := range results {
+		if isBroken(result.Status, result.Error) {
+			count++
+		}
+	}
+	return count
+}
+
+// errorPct returns the percentage of broken URLs
+func errorPct(brokenCount, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(brokenCount) / float64(total) * 100
+}
+
+// Exceeded reports whether the broken URL count crosses either threshold, and why
+func (f FailThreshold) Exceeded(brokenCount, total int) (bool, string) {
+	if pct := errorPct(brokenCount, total); f.Pct >= 0 && pct > f.Pct {
+		return true, fmt.Sprintf("%.2f%% of URLs are broken, threshold is %.2f%%", pct, f.Pct)
+	}
+	if f.Abs >= 0 && brokenCount > f.Abs {
+		return true, fmt.Sprintf("%d URLs are broken, threshold is %d", brokenCount, f.Abs)
+	}
+	return false, ""
+}
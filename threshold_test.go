@@ -0,0 +1,48 @@
+package main
+
+import (
+	"errors"
+	"testing"
+)
+
+// Test for FailThreshold.Exceeded
+func TestFailThresholdExceeded(t *testing.T) {
+	tests := []struct {
+		name      string
+		threshold FailThreshold
+		broken    int
+		total     int
+		want      bool
+	}{
+		{name: "default fails on any error", threshold: FailThreshold{Pct: 0, Abs: -1}, broken: 1, total: 50000, want: true},
+		{name: "default passes without errors", threshold: FailThreshold{Pct: 0, Abs: -1}, broken: 0, total: 100, want: false},
+		{name: "below percentage", threshold: FailThreshold{Pct: 1, Abs: -1}, broken: 1, total: 200, want: false},
+		{name: "above percentage", threshold: FailThreshold{Pct: 1, Abs: -1}, broken: 3, total: 200, want: true},
+		{name: "absolute only", threshold: FailThreshold{Pct: -1, Abs: 10}, broken: 10, total: 20, want: false},
+		{name: "absolute exceeded", threshold: FailThreshold{Pct: -1, Abs: 10}, broken: 11, total: 20, want: true},
+		{name: "either threshold", threshold: FailThreshold{Pct: 50, Abs: 5}, broken: 6, total: 100, want: true},
+		{name: "no URLs", threshold: FailThreshold{Pct: 0, Abs: -1}, broken: 0, total: 0, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, reason := tt.threshold.Exceeded(tt.broken, tt.total); got != tt.want {
+				t.Errorf("Exceeded(%d, %d) = %v (%s), want %v", tt.broken, tt.total, got, reason, tt.want)
+			}
+		})
+	}
+}
+
+// Test for countBroken function
+func TestCountBroken(t *testing.T) {
+	results := []Result{
+		{Status: 200},
+		{Status: 301, IsRedirect: true},
+		{Status: 404},
+		{Status: 503},
+		{Error: errors.New("timeout")},
+	}
+	if got := countBroken(results); got != 3 {
+		t.Errorf("countBroken() = %d, want 3", got)
+	}
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+)
+
+// title returns the text of the page's first <title> element with whitespace collapsed
+func (p *Page) title() (string, bool) {
+	var title string
+	found := false
+	walkHTML(p.Doc, func(n *html.Node) {
+		if found || n.Data != "title" {
+			return
+		}
+		var text strings.Builder
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type == html.TextNode {
+				text.WriteString(c.Data)
+			}
+		}
+		title = strings.Join(strings.Fields(text.String()), " ")
+		found = true
+	})
+	return title, found
+}
+
+// checkTitle records the page title and, when checkLength is set, flags titles
+// with fewer than minLength or more than maxLength characters. Non-HTML responses are skipped.
+func checkTitle(result *Result, page *Page, checkLength bool, minLength, maxLength int) {
+	if !isHTML(page.Header.Get("Content-Type")) {
+		return
+	}
+
+	title, found := page.title()
+	if !found || title == "" {
+		result.addIssue("MISSING_TITLE", "page has no <title>")
+		return
+	}
+	result.Title = title
+	result.TitleLength = utf8.RuneCountInString(title)
+
+	if !checkLength {
+		return
+	}
+	if result.TitleLength < minLength {
+		result.addIssue("TITLE_TOO_SHORT", fmt.Sprintf("%d characters, minimum is %d", result.TitleLength, minLength))
+	} else if result.TitleLength > maxLength {
+		result.addIssue("TITLE_TOO_LONG", fmt.Sprintf("%d characters, maximum is %d", result.TitleLength, maxLength))
+	}
+}
+
+// formatTitleLengthSummary reports how many titles fall below, within and above the length range
+func formatTitleLengthSummary(results []Result, minLength, maxLength int) string {
+	short, ok, long := 0, 0, 0
+	for _, result := range results {
+		switch {
+		case result.Title == "":
+			continue
+		case result.TitleLength < minLength:
+			short++
+		case result.TitleLength > maxLength:
+			long++
+		default:
+			ok++
+		}
+	}
+	return fmt.Sprintf("Title lengths: %d under %d characters, %d within %d-%d, %d over %d",
+		short, minLength, ok, minLength, maxLength, long, maxLength)
+}
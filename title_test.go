@@ -0,0 +1,89 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// Test for checkTitle function
+func TestCheckTitle(t *testing.T) {
+	tests := []struct {
+		name        string
+		head        string
+		checkLength bool
+		wantTitle   string
+		wantLength  int
+		wantCode    string
+	}{
+		{name: "missing", head: ``, wantCode: "MISSING_TITLE"},
+		{name: "recorded without length check", head: `<title>Short</title>`, wantTitle: "Short", wantLength: 5},
+		{name: "too short", head: `<title>Short</title>`, checkLength: true, wantTitle: "Short", wantLength: 5, wantCode: "TITLE_TOO_SHORT"},
+		{
+			name:        "whitespace collapsed",
+			head:        "<title>\n  Running shoes for   trail and road </title>",
+			checkLength: true,
+			wantTitle:   "Running shoes for trail and road",
+			wantLength:  32,
+		},
+		{
+			name:        "counts characters not bytes",
+			head:        `<title>` + strings.Repeat("ü", 60) + `</title>`,
+			checkLength: true,
+			wantTitle:   strings.Repeat("ü", 60),
+			wantLength:  60,
+		},
+		{
+			name:        "too long",
+			head:        `<title>` + strings.Repeat("a", 61) + `</title>`,
+			checkLength: true,
+			wantTitle:   strings.Repeat("a", 61),
+			wantLength:  61,
+			wantCode:    "TITLE_TOO_LONG",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page := parseTestPage(t, "https://example.com/", `<html><head>`+tt.head+`</head></html>`)
+			result := Result{URL: page.URL, Status: 200}
+			checkTitle(&result, page, tt.checkLength, 30, 60)
+
+			if result.Title != tt.wantTitle || result.TitleLength != tt.wantLength {
+				t.Errorf("Title = %q (%d), want %q (%d)", result.Title, result.TitleLength, tt.wantTitle, tt.wantLength)
+			}
+			var code string
+			if len(result.Issues) > 0 {
+				code = result.Issues[0].Code
+			}
+			if code != tt.wantCode {
+				t.Errorf("issue code = %q, want %q", code, tt.wantCode)
+			}
+		})
+	}
+}
+
+// Test that checkTitle ignores non-HTML responses such as PDFs
+func TestCheckTitleSkipsNonHTML(t *testing.T) {
+	page := parseTestPage(t, "https://example.com/doc.pdf", "%PDF-1.4")
+	page.Header.Set("Content-Type", "application/pdf")
+	result := Result{URL: page.URL, Status: 200}
+	checkTitle(&result, page, true, 30, 60)
+	if len(result.Issues) != 0 {
+		t.Errorf("Issues = %v, want none", result.Issues)
+	}
+}
+
+// Test for formatTitleLengthSummary function
+func TestFormatTitleLengthSummary(t *testing.T) {
+	results := []Result{
+		{Title: "a", TitleLength: 10},
+		{Title: "b", TitleLength: 45},
+		{Title: "c", TitleLength: 50},
+		{Title: "d", TitleLength: 70},
+		{Status: 404},
+	}
+	want := "Title lengths: 1 under 30 characters, 2 within 30-60, 1 over 60"
+	if got := formatTitleLengthSummary(results, 30, 60); got != want {
+		t.Errorf("formatTitleLengthSummary() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"crypto/tls"
+	"fmt"
+)
+
+// tlsVersionName returns a readable name for the TLS version of a connection,
+// or an empty string for plain HTTP
+func tlsVersionName(state *tls.ConnectionState) string {
+	if state == nil {
+		return ""
+	}
+	switch state.Version {
+	case tls.VersionTLS10:
+		return "TLS 1.0"
+	case tls.VersionTLS11:
+		return "TLS 1.1"
+	case tls.VersionTLS12:
+		return "TLS 1.2"
+	case tls.VersionTLS13:
+		return "TLS 1.3"
+	}
+	return fmt.Sprintf("TLS 0x%04x", state.Version)
+}
+
+// checkTLSVersion flags HTTPS responses negotiated with the deprecated TLS 1.0 or 1.1
+func checkTLSVersion(result *Result) {
+	if result.TLSVersion == "TLS 1.0" || result.TLSVersion == "TLS 1.1" {
+		result.addIssue("WEAK_TLS", fmt.Sprintf("connection negotiated %s, servers should require TLS 1.2 or later", result.TLSVersion))
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"crypto/tls"
+	"testing"
+)
+
+// Test for tlsVersionName function
+func TestTLSVersionName(t *testing.T) {
+	tests := []struct {
+		state *tls.ConnectionState
+		want  string
+	}{
+		{nil, ""},
+		{&tls.ConnectionState{Version: tls.VersionTLS10}, "TLS 1.0"},
+		{&tls.ConnectionState{Version: tls.VersionTLS11}, "TLS 1.1"},
+		{&tls.ConnectionState{Version: tls.VersionTLS12}, "TLS 1.2"},
+		{&tls.ConnectionState{Version: tls.VersionTLS13}, "TLS 1.3"},
+		{&tls.ConnectionState{Version: 0x0300}, "TLS 0x0300"},
+	}
+
+	for _, tt := range tests {
+		if got := tlsVersionName(tt.state); got != tt.want {
+			t.Errorf("tlsVersionName(%v) = %q, want %q", tt.state, got, tt.want)
+		}
+	}
+}
+
+// Test for checkTLSVersion function
+func TestCheckTLSVersion(t *testing.T) {
+	tests := []struct {
+		version string
+		weak    bool
+	}{
+		{"TLS 1.0", true},
+		{"TLS 1.1", true},
+		{"TLS 1.2", false},
+		{"TLS 1.3", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		result := Result{URL: "https://example.com/", TLSVersion: tt.version}
+		checkTLSVersion(&result)
+		if got := countIssues([]Result{result}, "WEAK_TLS") == 1; got != tt.weak {
+			t.Errorf("checkTLSVersion(%q) flagged = %v, want %v", tt.version, got, tt.weak)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Tracing exports a run as OpenTelemetry traces: a root span for the run and a
+// child span for every checked URL
+type Tracing struct {
+	provider *sdktrace.TracerProvider
+	tracer   trace.Tracer
+	ctx      context.Context
+	root     trace.Span
+}
+
+// NewTracing starts a run trace exported over OTLP. The exporter protocol follows the
+// endpoint port: gRPC for 4317, HTTP otherwise (the OTLP/HTTP port is 4318).
+// Endpoints without an https:// scheme are contacted without TLS.
+func NewTracing(endpoint, sitemapURL, runID string) (*Tracing, error) {
+	rawEndpoint := endpoint
+	if !strings.Contains(rawEndpoint, "://") {
+		rawEndpoint = "http://" + rawEndpoint
+	}
+	parsedURL, err := url.Parse(rawEndpoint)
+	if err != nil || parsedURL.Host == "" {
+		return nil, fmt.Errorf("invalid OpenTelemetry endpoint %q", endpoint)
+	}
+	insecure := parsedURL.Scheme != "https"
+
+	ctx := context.Background()
+	var exporter sdktrace.SpanExporter
+	if parsedURL.Port() == "4317" {
+		options := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(parsedURL.Host)}
+		if insecure {
+			options = append(options, otlptracegrpc.WithInsecure())
+		}
+		exporter, err = otlptracegrpc.New(ctx, options...)
+	} else {
+		options := []otlptracehttp.Option{otlptracehttp.WithEndpoint(parsedURL.Host)}
+		if insecure {
+			options = append(options, otlptracehttp.WithInsecure())
+		}
+		if parsedURL.Path != "" && parsedURL.Path != "/" {
+			options = append(options, otlptracehttp.WithURLPath(parsedURL.Path))
+		}
+		exporter, err = otlptracehttp.New(ctx, options...)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OpenTelemetry exporter: %w", err)
+	}
+
+	return newTracing(sdktrace.NewBatchSpanProcessor(exporter), sitemapURL, runID), nil
+}
+
+// newTracing starts the root span of a run, handing finished spans to processor
+func newTracing(processor sdktrace.SpanProcessor, sitemapURL, runID string) *Tracing {
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithSpanProcessor(processor),
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", "sitemap-checker"))),
+	)
+	tracer := provider.Tracer("sitemap_checker")
+
+	ctx, root := tracer.Start(context.Background(), "sitemap check", trace.WithAttributes(
+		attribute.String("sitemap.url", sitemapURL),
+		attribute.String("sitemap.run_id", runID),
+	))
+
+	return &Tracing{provider: provider, tracer: tracer, ctx: ctx, root: root}
+}
+
+// RecordResult adds a span for a checked URL. A nil Tracing does nothing.
+func (t *Tracing) RecordResult(result Result) {
+	if t == nil {
+		return
+	}
+
+	// The span covers the check request, which has just completed
+	end := time.Now()
+	_, span := t.tracer.Start(t.ctx, "check URL", trace.WithTimestamp(end.Add(-result.ResponseTime)))
+
+	span.SetAttributes(
+		attribute.String("url.full", result.URL),
+		attribute.Int64("sitemap.response_time_ms", result.ResponseTime.Milliseconds()),
+	)
+	if result.Status != 0 {
+		span.SetAttributes(attribute.Int("http.response.status_code", result.Status))
+	}
+	if result.RedirectURL != "" {
+		span.SetAttributes(attribute.String("sitemap.redirect_url", result.RedirectURL))
+	}
+	if result.SourceSitemap != "" {
+		span.SetAttributes(attribute.String("sitemap.source", result.SourceSitemap))
+	}
+	if result.Error != nil {
+		span.RecordError(result.Error)
+		span.SetStatus(codes.Error, result.Error.Error())
+	} else if result.Status >= 400 {
+		span.SetStatus(codes.Error, fmt.Sprintf("status %d", result.Status))
+	}
+
+	span.End(trace.WithTimestamp(end))
+}
+
+// Finish ends the run span with its totals and flushes all spans to the exporter
+func (t *Tracing) Finish(total, problematic int) error {
+	if t == nil {
+		return nil
+	}
+	t.root.SetAttributes(
+		attribute.Int("sitemap.urls_total", total),
+		attribute.Int("sitemap.urls_problematic", problematic),
+	)
+	t.root.End()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	return t.provider.Shutdown(ctx)
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// Test that a run produces a root span with a child span per URL
+func TestTracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tracing := newTracing(recorder, "https://example.com/sitemap.xml", "run123")
+
+	tracing.RecordResult(Result{URL: "https://example.com/ok", Status: 200, ResponseTime: 120 * time.Millisecond})
+	tracing.RecordResult(Result{URL: "https://example.com/down", Error: errors.New("connection refused")})
+	if err := tracing.Finish(2, 1); err != nil {
+		t.Fatalf("Finish() error = %v", err)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 3 {
+		t.Fatalf("exported %d spans, want 3", len(spans))
+	}
+
+	root := spans[2]
+	if root.Name() != "sitemap check" {
+		t.Errorf("root span name = %q, want %q", root.Name(), "sitemap check")
+	}
+	for _, span := range spans[:2] {
+		if span.Parent().SpanID() != root.SpanContext().SpanID() {
+			t.Errorf("span %q is not a child of the run span", span.Name())
+		}
+	}
+
+	ok := spans[0]
+	if got := ok.EndTime().Sub(ok.StartTime()); got != 120*time.Millisecond {
+		t.Errorf("span duration = %v, want 120ms", got)
+	}
+	if !hasAttribute(ok.Attributes(), attribute.Int("http.response.status_code", 200)) {
+		t.Errorf("span attributes = %v, want status code 200", ok.Attributes())
+	}
+	if spans[1].Status().Code != codes.Error {
+		t.Errorf("failed URL span status = %v, want Error", spans[1].Status().Code)
+	}
+
+	// A nil Tracing does nothing
+	var disabled *Tracing
+	disabled.RecordResult(Result{URL: "https://example.com/"})
+	if err := disabled.Finish(0, 0); err != nil {
+		t.Errorf("nil Finish() error = %v", err)
+	}
+}
+
+// hasAttribute reports whether attrs contains want
+func hasAttribute(attrs []attribute.KeyValue, want attribute.KeyValue) bool {
+	for _, attr := range attrs {
+		if attr == want {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/term"
+)
+
+// dashboardRecent is the number of latest results shown by the dashboard
+const dashboardRecent = 20
+
+// ANSI escape sequences used to draw the dashboard
+const (
+	ansiClear      = "\x1b[H\x1b[2J"
+	ansiHideCursor = "\x1b[?25l"
+	ansiShowCursor = "\x1b[?25h"
+	ansiReset      = "\x1b[0m"
+	ansiBold       = "\x1b[1m"
+	ansiRed        = "\x1b[31m"
+	ansiGreen      = "\x1b[32m"
+	ansiYellow     = "\x1b[33m"
+)
+
+// Dashboard is a live terminal view of a running check: a progress bar, counters,
+// elapsed and remaining time, and a table of the latest results
+type Dashboard struct {
+	out   io.Writer
+	width int
+	start time.Time
+
+	mu        sync.Mutex
+	total     int
+	checked   int
+	errors    int
+	redirects int
+	recent    []Result
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewDashboard creates a dashboard for a run of total URLs drawn on out
+func NewDashboard(total int, out io.Writer, width int) *Dashboard {
+	return &Dashboard{
+		out:   out,
+		width: width,
+		start: time.Now(),
+		total: total,
+		stop:  make(chan struct{}),
+		done:  make(chan struct{}),
+	}
+}
+
+// newTerminalDashboard creates a dashboard on stdout, or returns nil when stdout is not a terminal
+func newTerminalDashboard(total int) *Dashboard {
+	fd := int(os.Stdout.Fd())
+	if !term.IsTerminal(fd) {
+		return nil
+	}
+	width, _, err := term.GetSize(fd)
+	if err != nil || width <= 0 {
+		width = 80
+	}
+	return NewDashboard(total, os.Stdout, width)
+}
+
+// Start redraws the dashboard periodically until Stop is called
+func (d *Dashboard) Start() {
+	fmt.Fprint(d.out, ansiHideCursor)
+	go func() {
+		defer close(d.done)
+		ticker := time.NewTicker(250 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				d.draw()
+			case <-d.stop:
+				d.draw()
+				return
+			}
+		}
+	}()
+}
+
+// Stop draws the final state and restores the cursor
+func (d *Dashboard) Stop() {
+	close(d.stop)
+	<-d.done
+	fmt.Fprint(d.out, ansiShowCursor)
+}
+
+// Add records a result for the counters and the table of latest results. A nil dashboard does nothing.
+func (d *Dashboard) Add(result Result) {
+	if d == nil {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	switch {
+	case result.IsRedirect:
+		d.redirects++
+	case isBroken(result.Status, result.Error):
+		d.errors++
+	}
+
+	d.recent = append(d.recent, result)
+	if len(d.recent) > dashboardRecent {
+		d.recent = d.recent[len(d.recent)-dashboardRecent:]
+	}
+}
+
+// SetProgress updates the number of URLs checked so far
+func (d *Dashboard) SetProgress(checked int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.checked = checked
+}
+
+// draw writes the current state to the terminal
+func (d *Dashboard) draw() {
+	fmt.Fprint(d.out, ansiClear+d.render(time.Since(d.start)))
+}
+
+// render returns the dashboard as text after elapsed time
+func (d *Dashboard) render(elapsed time.Duration) string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%sSitemap Checker%s\n\n", ansiBold, ansiReset)
+
+	// Progress bar sized to the terminal, leaving room for the counts
+	barWidth := min(max(d.width-30, 10), 50)
+	pct := 0.0
+	if d.total > 0 {
+		pct = float64(d.checked) / float64(d.total)
+	}
+	filled := int(pct * float64(barWidth))
+	fmt.Fprintf(&b, "[%s%s] %d/%d (%d%%)\n", strings.Repeat("=", filled), strings.Repeat(" ", barWidth-filled),
+		d.checked, d.total, int(pct*100))
+
+	remaining := "unknown"
+	if d.checked > 0 && d.checked < d.total {
+		eta := time.Duration(float64(elapsed) / float64(d.checked) * float64(d.total-d.checked))
+		remaining = eta.Round(time.Second).String()
+	} else if d.checked >= d.total {
+		remaining = "0s"
+	}
+	fmt.Fprintf(&b, "Elapsed: %s   Remaining: %s\n", elapsed.Round(time.Second), remaining)
+	fmt.Fprintf(&b, "Errors: %s%d%s   Redirects: %s%d%s\n\n", ansiRed, d.errors, ansiReset, ansiYellow, d.redirects, ansiReset)
+
+	fmt.Fprintf(&b, "%-6s %8s  %s\n", "STATUS", "TIME", "URL")
+	urlWidth := max(d.width-17, 20)
+	for i := len(d.recent) - 1; i >= 0; i-- {
+		result := d.recent[i]
+
+		status, color := fmt.Sprint(result.Status), ansiGreen
+		switch {
+		case result.Error != nil:
+			status, color = "ERR", ansiRed
+		case result.IsRedirect:
+			color = ansiYellow
+		case isBroken(result.Status, nil):
+			color = ansiRed
+		}
+
+		url := result.URL
+		if len(url) > urlWidth {
+			url = url[:urlWidth-3] + "..."
+		}
+		fmt.Fprintf(&b, "%s%-6s%s %8s  %s\n", color, status, ansiReset, result.ResponseTime.Round(time.Millisecond), url)
+	}
+
+	return b.String()
+}
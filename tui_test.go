@@ -0,0 +1,42 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Test for Dashboard rendering
+func TestDashboardRender(t *testing.T) {
+	var out strings.Builder
+	dashboard := NewDashboard(100, &out, 80)
+
+	for i := 0; i < 25; i++ {
+		dashboard.Add(Result{URL: fmt.Sprintf("https://example.com/page%d", i), Status: 200, ResponseTime: 120 * time.Millisecond})
+	}
+	dashboard.Add(Result{URL: "https://example.com/moved", Status: 301, IsRedirect: true})
+	dashboard.Add(Result{URL: "https://example.com/missing", Status: 404})
+	dashboard.Add(Result{URL: "https://example.com/down", Error: errors.New("timeout")})
+	dashboard.SetProgress(25)
+
+	screen := dashboard.render(10 * time.Second)
+
+	for _, want := range []string{"25/100 (25%)", "Elapsed: 10s   Remaining: 30s", "ERR", "https://example.com/down"} {
+		if !strings.Contains(screen, want) {
+			t.Errorf("render() is missing %q:\n%s", want, screen)
+		}
+	}
+	if !strings.Contains(screen, "Errors: "+ansiRed+"2") || !strings.Contains(screen, "Redirects: "+ansiYellow+"1") {
+		t.Errorf("render() counters wrong:\n%s", screen)
+	}
+	// Only the 20 latest results are listed, so the first pages have scrolled away
+	if strings.Contains(screen, "/page0\n") || strings.Count(screen, "https://example.com/") != dashboardRecent {
+		t.Errorf("render() should list the %d latest results:\n%s", dashboardRecent, screen)
+	}
+
+	// A nil dashboard ignores results
+	var disabled *Dashboard
+	disabled.Add(Result{URL: "https://example.com/"})
+}
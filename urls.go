@@ -0,0 +1,319 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+
+	"golang.org/x/net/idna"
+)
+
+// chunkURLs returns the chunk at index when urls is divided into chunks of size.
+// A size of zero or less disables chunking and returns all URLs.
+func chunkURLs(urls []URL, size, index int) ([]URL, error) {
+	if size <= 0 {
+		return urls, nil
+	}
+
+	chunkCount := (len(urls) + size - 1) / size
+	if index < 0 || index >= chunkCount {
+		return nil, fmt.Errorf("chunk index %d out of range (%d chunks of %d URLs)", index, chunkCount, size)
+	}
+
+	start := index * size
+	end := start + size
+	if end > len(urls) {
+		end = len(urls)
+	}
+	return urls[start:end], nil
+}
+
+// urlLocs returns the locations of a list of sitemap URL entries
+func urlLocs(urls []URL) []string {
+	locs := make([]string, 0, len(urls))
+	for _, u := range urls {
+		locs = append(locs, u.Loc)
+	}
+	return locs
+}
+
+// utmParams are the Google Analytics campaign parameters removed by -strip-utm
+var utmParams = []string{"utm_source", "utm_medium", "utm_campaign", "utm_content", "utm_term"}
+
+// stripQueryParams removes the named query parameters from a URL
+func stripQueryParams(rawURL string, params []string) string {
+	parsedURL, err := url.Parse(rawURL)
+	if err != nil || parsedURL.RawQuery == "" {
+		return rawURL
+	}
+
+	query := parsedURL.Query()
+	changed := false
+	for _, param := range params {
+		if query.Has(param) {
+			query.Del(param)
+			changed = true
+		}
+	}
+	if !changed {
+		return rawURL
+	}
+
+	parsedURL.RawQuery = query.Encode()
+	return parsedURL.String()
+}
+
+// URLRewrite records a URL changed by a rewrite step
+type URLRewrite struct {
+	From string
+	To   string
+}
+
+// rewriteURLs applies rewrite to every URL, dropping URLs that become duplicates of
+// an earlier entry. It returns the rewritten list and every URL that was changed.
+func rewriteURLs(urls []URL, rewrite func(string) string) ([]URL, []URLRewrite) {
+	seen := make(map[string]bool, len(urls))
+	var changed []URLRewrite
+	rewritten := make([]URL, 0, len(urls))
+
+	for _, u := range urls {
+		newLoc := rewrite(u.Loc)
+		if newLoc != u.Loc {
+			changed = append(changed, URLRewrite{From: u.Loc, To: newLoc})
+			u.Loc = newLoc
+		}
+		if seen[u.Loc] {
+			continue
+		}
+		seen[u.Loc] = true
+		rewritten = append(rewritten, u)
+	}
+
+	return rewritten, changed
+}
+
+// URLDuplicate records a URL dropped as a variant of an earlier sitemap entry
+type URLDuplicate struct {
+	Variant   string
+	Canonical string
+	Code      string
+}
+
+// dedupSimilarURLs drops URLs that differ from an earlier entry only in letter case
+// and, with schemeInsensitive, in http/https scheme. The first form listed is kept
+// and flagged with each variant dropped in its favour, naming the variant's URL.
+func dedupSimilarURLs(urls []URL, caseInsensitive, schemeInsensitive bool) ([]URL, []URLDuplicate) {
+	key := func(loc string) string {
+		if caseInsensitive {
+			loc = strings.ToLower(loc)
+		}
+		if schemeInsensitive {
+			if rest, ok := strings.CutPrefix(loc, "https://"); ok {
+				return "//" + rest
+			}
+			if rest, ok := strings.CutPrefix(loc, "http://"); ok {
+				return "//" + rest
+			}
+		}
+		return loc
+	}
+
+	kept := make(map[string]int, len(urls))
+	var duplicates []URLDuplicate
+	deduped := make([]URL, 0, len(urls))
+
+	for _, u := range urls {
+		k := key(u.Loc)
+		i, ok := kept[k]
+		if !ok {
+			kept[k] = len(deduped)
+			deduped = append(deduped, u)
+			continue
+		}
+
+		canonical := &deduped[i]
+		if u.Loc == canonical.Loc {
+			continue
+		}
+		// The variant is not checked, so the issue names it and where it is listed
+		code, variant := "CASE_DUPLICATE", "case and scheme variant"
+		if strings.EqualFold(u.Loc, canonical.Loc) {
+			variant = "case variant"
+		} else if _, rest, _ := strings.Cut(u.Loc, "://"); strings.HasSuffix(canonical.Loc, "://"+rest) {
+			code, variant = "SCHEME_DUPLICATE", "scheme variant"
+		}
+		dropped := u.Loc
+		if u.Source != "" {
+			dropped += " (listed in " + u.Source + ")"
+		}
+		canonical.addIssue(code, fmt.Sprintf("dropped %s %s of this URL", variant, dropped))
+		duplicates = append(duplicates, URLDuplicate{Variant: u.Loc, Canonical: canonical.Loc, Code: code})
+	}
+
+	return deduped, duplicates
+}
+
+// splitList splits a comma-separated flag value, ignoring empty items
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// addIssue records a problem found in the URL itself
+func (u *URL) addIssue(code, message string) {
+	u.Issues = append(u.Issues, Issue{Code: code, Message: message})
+}
+
+// isProtocolRelative reports whether a URL starts with // and leaves the scheme to the referrer
+func isProtocolRelative(loc string) bool {
+	return strings.HasPrefix(strings.TrimSpace(loc), "//")
+}
+
+// isIPAddressURL reports whether a URL's host is an IPv4 or IPv6 address instead of a hostname
+func isIPAddressURL(loc string) bool {
+	parsedURL, err := url.Parse(strings.TrimSpace(loc))
+	if err != nil {
+		return false
+	}
+	return net.ParseIP(parsedURL.Hostname()) != nil
+}
+
+// normalizeURL returns the canonical form of a URL: lowercase scheme and host,
+// escaped characters and a cleaned path without redundant segments
+func normalizeURL(rawURL string) string {
+	parsedURL, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return rawURL
+	}
+
+	parsedURL.Scheme = strings.ToLower(parsedURL.Scheme)
+	parsedURL.Host = strings.ToLower(parsedURL.Host)
+
+	if parsedURL.Path != "" {
+		cleaned := path.Clean(parsedURL.Path)
+		// path.Clean drops the trailing slash, which is significant in a URL
+		if strings.HasSuffix(parsedURL.Path, "/") && cleaned != "/" {
+			cleaned += "/"
+		}
+		parsedURL.Path = cleaned
+		parsedURL.RawPath = ""
+	}
+
+	return parsedURL.String()
+}
+
+// urlForbiddenChars are characters the WHATWG URL standard requires to be percent-encoded
+const urlForbiddenChars = " \"<>\\^`{|}"
+
+// checkURLCompliance returns the reasons a URL is not in the form the WHATWG URL
+// standard serializes it to, or nil if it is compliant
+func checkURLCompliance(rawURL string) []string {
+	var problems []string
+
+	parsedURL, err := url.Parse(rawURL)
+	if err != nil {
+		return []string{fmt.Sprintf("cannot be parsed: %v", err)}
+	}
+	if parsedURL.Scheme == "" || parsedURL.Host == "" {
+		problems = append(problems, "is not an absolute URL with a host")
+	}
+
+	if host := parsedURL.Hostname(); host != "" && net.ParseIP(host) == nil {
+		if _, err := idna.Lookup.ToASCII(host); err != nil {
+			problems = append(problems, fmt.Sprintf("host %q is not a valid internationalized domain name: %v", host, err))
+		}
+	}
+
+	for i := 0; i < len(rawURL); i++ {
+		c := rawURL[i]
+		switch {
+		case c == '%':
+			if i+2 >= len(rawURL) || !isHexDigit(rawURL[i+1]) || !isHexDigit(rawURL[i+2]) {
+				problems = append(problems, fmt.Sprintf("'%%' at position %d does not start a percent-encoded byte", i))
+				continue
+			}
+			escape := rawURL[i : i+3]
+			if upper := strings.ToUpper(escape); upper != escape {
+				problems = append(problems, fmt.Sprintf("percent-encoding %s should be uppercase %s", escape, upper))
+			}
+			i += 2
+		case c < 0x20 || c == 0x7f:
+			problems = append(problems, fmt.Sprintf("contains control character 0x%02X", c))
+		case strings.IndexByte(urlForbiddenChars, c) >= 0:
+			problems = append(problems, fmt.Sprintf("contains %q, which must be percent-encoded", c))
+		}
+	}
+
+	return problems
+}
+
+// isHexDigit reports whether c is a hexadecimal digit
+func isHexDigit(c byte) bool {
+	return ('0' <= c && c <= '9') || ('a' <= c && c <= 'f') || ('A' <= c && c <= 'F')
+}
+
+// flagRedundantRedirects flags redirecting sitemap entries whose target is also
+// in the sitemap, since the redirecting entry can be removed. It returns the
+// number of entries flagged.
+func flagRedundantRedirects(results []Result, logger *Logger) int {
+	listed := make(map[string]bool, len(results))
+	for _, result := range results {
+		listed[result.URL] = true
+	}
+
+	count := 0
+	for i := range results {
+		result := &results[i]
+		if !result.IsRedirect || result.RedirectURL == "" {
+			continue
+		}
+		target := resolveURL(result.URL, result.RedirectURL)
+		if target == result.URL || !listed[target] {
+			continue
+		}
+		msg := fmt.Sprintf("redirects to %s, which is also in the sitemap; remove this entry", target)
+		result.addIssue("REDUNDANT_REDIRECT_IN_SITEMAP", msg)
+		if logger != nil {
+			logger.Log(fmt.Sprintf("REDUNDANT_REDIRECT_IN_SITEMAP: %s - %s", result.URL, msg) + sourceTag(result.SourceSitemap))
+		}
+		count++
+	}
+	return count
+}
+
+// checkURLLength flags a URL longer than maxLength or with a query string longer than
+// maxQuery, which some browsers, proxies and servers reject
+func checkURLLength(u *URL, maxLength, maxQuery int) {
+	if length := len(u.Loc); length > maxLength {
+		u.addIssue("URL_TOO_LONG", fmt.Sprintf("URL is %d characters, limit is %d", length, maxLength))
+	}
+	if parsedURL, err := url.Parse(u.Loc); err == nil && len(parsedURL.RawQuery) > maxQuery {
+		u.addIssue("QUERY_TOO_LONG", fmt.Sprintf("query string is %d characters, limit is %d", len(parsedURL.RawQuery), maxQuery))
+	}
+}
+
+// formatURLLengthSummary reports the shortest, longest, average and 95th percentile URL length
+func formatURLLengthSummary(lengths []int) string {
+	if len(lengths) == 0 {
+		return "URL length: no URLs"
+	}
+	sorted := append([]int(nil), lengths...)
+	sort.Ints(sorted)
+
+	total := 0
+	for _, length := range sorted {
+		total += length
+	}
+	// Nearest-rank percentile
+	p95 := sorted[(len(sorted)*95+99)/100-1]
+	return fmt.Sprintf("URL length: min %d, max %d, average %.1f, p95 %d characters",
+		sorted[0], sorted[len(sorted)-1], float64(total)/float64(len(sorted)), p95)
+}
@@ -0,0 +1,320 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// Test for chunkURLs function
+func TestChunkURLs(t *testing.T) {
+	urls := []URL{{Loc: "a"}, {Loc: "b"}, {Loc: "c"}, {Loc: "d"}, {Loc: "e"}}
+
+	tests := []struct {
+		name    string
+		size    int
+		index   int
+		want    []string
+		wantErr bool
+	}{
+		{name: "chunking disabled", size: 0, index: 0, want: []string{"a", "b", "c", "d", "e"}},
+		{name: "first chunk", size: 2, index: 0, want: []string{"a", "b"}},
+		{name: "last partial chunk", size: 2, index: 2, want: []string{"e"}},
+		{name: "index out of range", size: 2, index: 3, wantErr: true},
+		{name: "negative index", size: 2, index: -1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := chunkURLs(urls, tt.size, tt.index)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("chunkURLs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !equalStringSlices(urlLocs(got), tt.want) {
+				t.Errorf("chunkURLs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// Test for stripQueryParams function
+func TestStripQueryParams(t *testing.T) {
+	params := []string{"utm_source", "fbclid"}
+
+	tests := []struct {
+		name string
+		url  string
+		want string
+	}{
+		{name: "no query", url: "https://example.com/page", want: "https://example.com/page"},
+		{name: "only ignored params", url: "https://example.com/page?utm_source=news&fbclid=abc", want: "https://example.com/page"},
+		{name: "mixed params", url: "https://example.com/page?id=5&utm_source=news", want: "https://example.com/page?id=5"},
+		{name: "no ignored params", url: "https://example.com/page?b=2&a=1", want: "https://example.com/page?b=2&a=1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := stripQueryParams(tt.url, params); got != tt.want {
+				t.Errorf("stripQueryParams() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// Test that stripping UTM parameters merges campaign variants of a URL
+func TestStripUTMParams(t *testing.T) {
+	urls := []URL{
+		{Loc: "https://example.com/page?utm_source=mail&utm_medium=email&utm_campaign=spring"},
+		{Loc: "https://example.com/page?utm_term=shoes&utm_content=banner"},
+		{Loc: "https://example.com/other?id=7&utm_source=mail"},
+	}
+
+	got, changed := rewriteURLs(urls, func(u string) string { return stripQueryParams(u, utmParams) })
+
+	want := []string{"https://example.com/page", "https://example.com/other?id=7"}
+	if !equalStringSlices(urlLocs(got), want) {
+		t.Errorf("stripped URLs = %v, want %v", urlLocs(got), want)
+	}
+	if len(changed) != 3 {
+		t.Errorf("rewriteURLs() reported %d rewrites, want 3", len(changed))
+	}
+}
+
+// Test for rewriteURLs function
+func TestRewriteURLs(t *testing.T) {
+	urls := []URL{
+		{Loc: "https://example.com/page?utm_source=a"},
+		{Loc: "https://example.com/page?utm_source=b"},
+		{Loc: "https://example.com/other"},
+	}
+
+	got, changed := rewriteURLs(urls, func(u string) string { return stripQueryParams(u, []string{"utm_source"}) })
+
+	want := []string{"https://example.com/page", "https://example.com/other"}
+	if !equalStringSlices(urlLocs(got), want) {
+		t.Errorf("rewriteURLs() = %v, want %v", urlLocs(got), want)
+	}
+	if len(changed) != 2 || changed[1] != (URLRewrite{From: "https://example.com/page?utm_source=b", To: "https://example.com/page"}) {
+		t.Errorf("rewriteURLs() changed = %v, want both tracking URLs", changed)
+	}
+}
+
+// Test for dedupSimilarURLs function
+func TestDedupSimilarURLs(t *testing.T) {
+	urls := []URL{
+		{Loc: "https://example.com/Page"},
+		{Loc: "https://example.com/page", Source: "https://example.com/sitemap2.xml"},
+		{Loc: "http://example.com/Page"},
+		{Loc: "https://example.com/other"},
+		{Loc: "https://example.com/other"},
+	}
+
+	tests := []struct {
+		name              string
+		caseInsensitive   bool
+		schemeInsensitive bool
+		want              []string
+		wantCodes         []string
+	}{
+		{
+			name: "disabled",
+			want: []string{"https://example.com/Page", "https://example.com/page", "http://example.com/Page", "https://example.com/other"},
+		},
+		{
+			name:            "case only",
+			caseInsensitive: true,
+			want:            []string{"https://example.com/Page", "http://example.com/Page", "https://example.com/other"},
+			wantCodes:       []string{"CASE_DUPLICATE"},
+		},
+		{
+			name:              "case and scheme",
+			caseInsensitive:   true,
+			schemeInsensitive: true,
+			want:              []string{"https://example.com/Page", "https://example.com/other"},
+			wantCodes:         []string{"CASE_DUPLICATE", "SCHEME_DUPLICATE"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := append([]URL{}, urls...)
+			got, duplicates := dedupSimilarURLs(input, tt.caseInsensitive, tt.schemeInsensitive)
+			if !equalStringSlices(urlLocs(got), tt.want) {
+				t.Errorf("dedupSimilarURLs() = %v, want %v", urlLocs(got), tt.want)
+			}
+
+			var codes []string
+			for _, d := range duplicates {
+				if d.Canonical != "https://example.com/Page" {
+					t.Errorf("duplicate %s kept as %s, want the first form listed", d.Variant, d.Canonical)
+				}
+				codes = append(codes, d.Code)
+			}
+			if !equalStringSlices(codes, tt.wantCodes) {
+				t.Errorf("duplicate codes = %v, want %v", codes, tt.wantCodes)
+			}
+			if len(got[0].Issues) != len(tt.wantCodes) {
+				t.Errorf("kept URL has %d issues, want %d", len(got[0].Issues), len(tt.wantCodes))
+			}
+			for i, issue := range got[0].Issues {
+				if !strings.Contains(issue.Message, duplicates[i].Variant) || (i == 0 && !strings.Contains(issue.Message, "listed in https://example.com/sitemap2.xml")) {
+					t.Errorf("issue %q does not name the dropped variant %s and its sitemap", issue.Message, duplicates[i].Variant)
+				}
+			}
+		})
+	}
+}
+
+// Test for isProtocolRelative function
+func TestIsProtocolRelative(t *testing.T) {
+	tests := []struct {
+		url  string
+		want bool
+	}{
+		{url: "//example.com/page", want: true},
+		{url: " //example.com/", want: true},
+		{url: "https://example.com//double", want: false},
+		{url: "/page", want: false},
+	}
+
+	for _, tt := range tests {
+		if got := isProtocolRelative(tt.url); got != tt.want {
+			t.Errorf("isProtocolRelative(%q) = %v, want %v", tt.url, got, tt.want)
+		}
+	}
+}
+
+// Test for isIPAddressURL function
+func TestIsIPAddressURL(t *testing.T) {
+	tests := []struct {
+		url  string
+		want bool
+	}{
+		{url: "https://example.com/page", want: false},
+		{url: "http://192.168.1.10/page", want: true},
+		{url: "http://10.0.0.1:8080/", want: true},
+		{url: "https://[2001:db8::1]/page", want: true},
+		{url: "https://1.2.3.4.example.com/", want: false},
+	}
+
+	for _, tt := range tests {
+		if got := isIPAddressURL(tt.url); got != tt.want {
+			t.Errorf("isIPAddressURL(%q) = %v, want %v", tt.url, got, tt.want)
+		}
+	}
+}
+
+// Test for normalizeURL function
+func TestNormalizeURL(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+		want string
+	}{
+		{name: "already canonical", url: "https://example.com/blog/post", want: "https://example.com/blog/post"},
+		{name: "host casing", url: "HTTPS://Example.COM/Page", want: "https://example.com/Page"},
+		{name: "dot segments", url: "https://example.com/a/../b/./c", want: "https://example.com/b/c"},
+		{name: "duplicate slashes", url: "https://example.com//a//b", want: "https://example.com/a/b"},
+		{name: "trailing slash kept", url: "https://example.com/dir/", want: "https://example.com/dir/"},
+		{name: "unencoded space", url: "https://example.com/a b", want: "https://example.com/a%20b"},
+		{name: "root", url: "https://example.com/", want: "https://example.com/"},
+		{name: "query kept", url: "https://example.com/a/../b?x=1", want: "https://example.com/b?x=1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := normalizeURL(tt.url); got != tt.want {
+				t.Errorf("normalizeURL(%q) = %q, want %q", tt.url, got, tt.want)
+			}
+		})
+	}
+}
+
+// Test for checkURLCompliance function
+func TestCheckURLCompliance(t *testing.T) {
+	tests := []struct {
+		name     string
+		url      string
+		problems int
+	}{
+		{name: "compliant", url: "https://example.com/caf%C3%A9?q=a%2Fb", problems: 0},
+		{name: "unicode host", url: "https://bücher.example/", problems: 0},
+		{name: "ip host", url: "http://127.0.0.1:8080/", problems: 0},
+		{name: "lowercase escape", url: "https://example.com/caf%c3%a9", problems: 2},
+		{name: "stray percent", url: "https://example.com/100%", problems: 1},
+		{name: "unencoded space", url: "https://example.com/a b", problems: 1},
+		{name: "invalid idn", url: "https://exa_mple-.com/", problems: 1},
+		{name: "relative", url: "/page", problems: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := checkURLCompliance(tt.url); len(got) != tt.problems {
+				t.Errorf("checkURLCompliance(%q) = %q, want %d problems", tt.url, got, tt.problems)
+			}
+		})
+	}
+}
+
+// Test for flagRedundantRedirects function
+func TestFlagRedundantRedirects(t *testing.T) {
+	results := []Result{
+		{URL: "https://example.com/old", Status: 301, IsRedirect: true, RedirectURL: "/new"},
+		{URL: "https://example.com/new", Status: 200},
+		{URL: "https://example.com/moved", Status: 301, IsRedirect: true, RedirectURL: "https://example.com/elsewhere"},
+		{URL: "https://example.com/missing", Status: 404},
+	}
+
+	if got := flagRedundantRedirects(results, nil); got != 1 {
+		t.Errorf("flagRedundantRedirects() = %d, want 1", got)
+	}
+	if len(results[0].Issues) != 1 || results[0].Issues[0].Code != "REDUNDANT_REDIRECT_IN_SITEMAP" {
+		t.Errorf("issues of redirecting entry = %+v, want REDUNDANT_REDIRECT_IN_SITEMAP", results[0].Issues)
+	}
+	if len(results[2].Issues) != 0 {
+		t.Errorf("issues of redirect to unlisted URL = %+v, want none", results[2].Issues)
+	}
+}
+
+// Test for checkURLLength function
+func TestCheckURLLength(t *testing.T) {
+	tests := []struct {
+		loc   string
+		codes []string
+	}{
+		{"https://example.com/short?q=1", nil},
+		{"https://example.com/" + strings.Repeat("a", 40), []string{"URL_TOO_LONG"}},
+		{"https://example.com/?" + strings.Repeat("q", 11), []string{"QUERY_TOO_LONG"}},
+		{"https://example.com/" + strings.Repeat("a", 30) + "?" + strings.Repeat("q", 11), []string{"URL_TOO_LONG", "QUERY_TOO_LONG"}},
+	}
+
+	for _, tt := range tests {
+		u := URL{Loc: tt.loc}
+		checkURLLength(&u, 50, 10)
+		var codes []string
+		for _, issue := range u.Issues {
+			codes = append(codes, issue.Code)
+		}
+		if !equalStringSlices(codes, tt.codes) {
+			t.Errorf("checkURLLength(%q) codes = %v, want %v", tt.loc, codes, tt.codes)
+		}
+	}
+}
+
+// Test for formatURLLengthSummary function
+func TestFormatURLLengthSummary(t *testing.T) {
+	var lengths []int
+	for i := 100; i >= 1; i-- {
+		lengths = append(lengths, i)
+	}
+
+	want := "URL length: min 1, max 100, average 50.5, p95 95 characters"
+	if got := formatURLLengthSummary(lengths); got != want {
+		t.Errorf("formatURLLengthSummary() = %q, want %q", got, want)
+	}
+	if got := formatURLLengthSummary([]int{30}); got != "URL length: min 30, max 30, average 30.0, p95 30 characters" {
+		t.Errorf("formatURLLengthSummary() single = %q", got)
+	}
+	if got := formatURLLengthSummary(nil); got != "URL length: no URLs" {
+		t.Errorf("formatURLLengthSummary(nil) = %q", got)
+	}
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// parseViewport splits the content of a viewport meta tag into lowercase properties.
+// Properties are separated by commas, though some pages use semicolons.
+func parseViewport(content string) map[string]string {
+	properties := make(map[string]string)
+	for _, part := range strings.FieldsFunc(content, func(r rune) bool { return r == ',' || r == ';' }) {
+		key, value, _ := strings.Cut(part, "=")
+		properties[strings.ToLower(strings.TrimSpace(key))] = strings.ToLower(strings.TrimSpace(value))
+	}
+	return properties
+}
+
+// viewportZoomRestriction returns why a viewport stops visitors from zooming,
+// or an empty string when zooming is allowed
+func viewportZoomRestriction(content string) string {
+	properties := parseViewport(content)
+	if scalable, ok := properties["user-scalable"]; ok && (scalable == "no" || scalable == "0") {
+		return "user-scalable=" + scalable
+	}
+	if maxScale, ok := properties["maximum-scale"]; ok {
+		if scale, err := strconv.ParseFloat(maxScale, 64); err == nil && scale <= 1 {
+			return "maximum-scale=" + maxScale
+		}
+	}
+	return ""
+}
+
+// checkViewport records the viewport meta tag of a page, flagging a missing one
+// and one that disables zooming
+func checkViewport(result *Result, page *Page) {
+	content, ok := page.metaContent("name", "viewport")
+	result.ViewportContent = content
+	if !ok {
+		result.addIssue("MISSING_VIEWPORT", `page has no <meta name="viewport">`)
+		return
+	}
+	if restriction := viewportZoomRestriction(content); restriction != "" {
+		result.addIssue("VIEWPORT_RESTRICTS_ZOOM", fmt.Sprintf("viewport %q prevents zooming with %s", content, restriction))
+	}
+}
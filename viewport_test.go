@@ -0,0 +1,36 @@
+package main
+
+import "testing"
+
+// Test for checkViewport function
+func TestCheckViewport(t *testing.T) {
+	tests := []struct {
+		name     string
+		head     string
+		wantCode string
+	}{
+		{"ok", `<meta name="viewport" content="width=device-width, initial-scale=1">`, ""},
+		{"missing", ``, "MISSING_VIEWPORT"},
+		{"user-scalable no", `<meta name="viewport" content="width=device-width, user-scalable=no">`, "VIEWPORT_RESTRICTS_ZOOM"},
+		{"user-scalable 0", `<meta name="Viewport" content="width=device-width; user-scalable=0">`, "VIEWPORT_RESTRICTS_ZOOM"},
+		{"maximum-scale 1", `<meta name="viewport" content="width=device-width, maximum-scale=1.0">`, "VIEWPORT_RESTRICTS_ZOOM"},
+		{"maximum-scale 5", `<meta name="viewport" content="width=device-width, maximum-scale=5">`, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page := parseTestPage(t, "https://example.com/", "<html><head>"+tt.head+"</head><body></body></html>")
+			var result Result
+			checkViewport(&result, page)
+			if tt.wantCode == "" {
+				if len(result.Issues) != 0 {
+					t.Errorf("issues = %v, want none", result.Issues)
+				}
+				if result.ViewportContent == "" {
+					t.Error("ViewportContent not recorded")
+				}
+			} else if len(result.Issues) != 1 || result.Issues[0].Code != tt.wantCode {
+				t.Errorf("issues = %v, want %s", result.Issues, tt.wantCode)
+			}
+		})
+	}
+}
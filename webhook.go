@@ -0,0 +1,177 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// webhookRetries is how many times a failed webhook post is retried
+	webhookRetries = 3
+	// webhookQueueSize is how many events wait for the sender before new ones are dropped
+	webhookQueueSize = 1000
+)
+
+// WebhookEvent describes a broken or redirected URL sent to the webhook
+type WebhookEvent struct {
+	URL           string  `json:"url"`
+	Status        int     `json:"status,omitempty"`
+	Error         string  `json:"error,omitempty"`
+	RedirectURL   string  `json:"redirect_url,omitempty"`
+	SourceSitemap string  `json:"source_sitemap,omitempty"`
+	Issues        []Issue `json:"issues,omitempty"`
+}
+
+// WebhookPayload is the JSON body of a webhook post
+type WebhookPayload struct {
+	RunID  string         `json:"run_id"`
+	Events []WebhookEvent `json:"events"`
+}
+
+// WebhookNotifier posts broken and redirected URLs to a webhook while the check
+// is still running. Events are queued and sent in batches from a background
+// goroutine so a slow webhook never holds up the checker: events that don't fit
+// in the queue are dropped and counted.
+type WebhookNotifier struct {
+	url       string
+	runID     string
+	batchSize int
+	client    *http.Client
+	logger    *Logger
+	backoff   time.Duration
+	queue     chan WebhookEvent
+	done      chan struct{}
+	dropped   atomic.Int64
+}
+
+// NewWebhookNotifier creates a notifier and starts its sender. Posts go through
+// transport, so -k and -proxy apply to the webhook too; nil uses the default transport.
+func NewWebhookNotifier(webhookURL, runID string, batchSize int, transport http.RoundTripper, logger *Logger) *WebhookNotifier {
+	if batchSize < 1 {
+		batchSize = 1
+	}
+	n := &WebhookNotifier{
+		url:       webhookURL,
+		runID:     runID,
+		batchSize: batchSize,
+		client:    &http.Client{Timeout: 10 * time.Second, Transport: transport},
+		logger:    logger,
+		backoff:   time.Second,
+		queue:     make(chan WebhookEvent, webhookQueueSize),
+		done:      make(chan struct{}),
+	}
+	go n.run()
+	return n
+}
+
+// Notify queues a result if its URL is broken or redirected. A nil notifier does nothing.
+func (n *WebhookNotifier) Notify(result Result) {
+	if n == nil || !(isBroken(result.Status, result.Error) || result.IsRedirect) {
+		return
+	}
+
+	event := WebhookEvent{
+		URL:           result.URL,
+		Status:        result.Status,
+		RedirectURL:   result.RedirectURL,
+		SourceSitemap: result.SourceSitemap,
+		Issues:        result.Issues,
+	}
+	if result.Error != nil {
+		event.Error = result.Error.Error()
+	}
+	select {
+	case n.queue <- event:
+	default:
+		n.dropped.Add(1)
+	}
+}
+
+// Dropped returns how many events were dropped because the queue was full
+func (n *WebhookNotifier) Dropped() int64 {
+	if n == nil {
+		return 0
+	}
+	return n.dropped.Load()
+}
+
+// Close sends the events still queued, waits for the sender to finish and reports
+// the events that were dropped
+func (n *WebhookNotifier) Close() {
+	if n == nil {
+		return
+	}
+	close(n.queue)
+	<-n.done
+
+	if dropped := n.Dropped(); dropped > 0 {
+		n.logError(fmt.Errorf("queue full, dropped %d events", dropped))
+	}
+}
+
+// run sends queued events in batches until the queue is closed
+func (n *WebhookNotifier) run() {
+	defer close(n.done)
+
+	batch := make([]WebhookEvent, 0, n.batchSize)
+	for event := range n.queue {
+		batch = append(batch, event)
+		if len(batch) >= n.batchSize {
+			n.send(batch)
+			batch = batch[:0]
+		}
+	}
+	if len(batch) > 0 {
+		n.send(batch)
+	}
+}
+
+// send posts a batch, retrying with exponential backoff, and logs it if every attempt fails
+func (n *WebhookNotifier) send(batch []WebhookEvent) {
+	body, err := json.Marshal(WebhookPayload{RunID: n.runID, Events: batch})
+	if err != nil {
+		n.logError(err)
+		return
+	}
+
+	delay := n.backoff
+	for attempt := 0; ; attempt++ {
+		err = n.post(body)
+		if err == nil {
+			return
+		}
+		if attempt == webhookRetries {
+			break
+		}
+		time.Sleep(delay)
+		delay *= 2
+	}
+	n.logError(err)
+}
+
+// post makes a single webhook request
+func (n *WebhookNotifier) post(body []byte) error {
+	resp, err := n.client.Post(n.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// logError reports a batch that could not be delivered
+func (n *WebhookNotifier) logError(err error) {
+	msg := fmt.Sprintf("WEBHOOK ERROR: %s - %v", n.url, err)
+	fmt.Println(msg)
+	if n.logger != nil {
+		n.logger.Log(msg)
+	}
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// Test that WebhookNotifier batches broken and redirected URLs and includes the run ID
+func TestWebhookNotifier(t *testing.T) {
+	var mu sync.Mutex
+	var payloads []WebhookPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload WebhookPayload
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("invalid webhook payload: %v", err)
+		}
+		mu.Lock()
+		payloads = append(payloads, payload)
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	notifier := NewWebhookNotifier(server.URL, "run123", 2, nil, nil)
+	notifier.Notify(Result{URL: "https://example.com/ok", Status: http.StatusOK})
+	notifier.Notify(Result{URL: "https://example.com/missing", Status: http.StatusNotFound})
+	notifier.Notify(Result{URL: "https://example.com/moved", Status: http.StatusMovedPermanently, IsRedirect: true, RedirectURL: "https://example.com/new"})
+	notifier.Notify(Result{URL: "https://example.com/down", Error: errors.New("connection refused")})
+	notifier.Close()
+
+	if len(payloads) != 2 {
+		t.Fatalf("webhook received %d posts, want 2", len(payloads))
+	}
+	var urls []string
+	for _, payload := range payloads {
+		if payload.RunID != "run123" {
+			t.Errorf("payload run_id = %q, want run123", payload.RunID)
+		}
+		for _, event := range payload.Events {
+			urls = append(urls, event.URL)
+		}
+	}
+	want := []string{"https://example.com/missing", "https://example.com/moved", "https://example.com/down"}
+	if !equalStringSlices(urls, want) {
+		t.Errorf("webhook events = %v, want %v", urls, want)
+	}
+	if got := payloads[1].Events[0].Error; got != "connection refused" {
+		t.Errorf("event error = %q, want %q", got, "connection refused")
+	}
+}
+
+// Test that failed webhook posts are retried
+func TestWebhookNotifierRetry(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	notifier := NewWebhookNotifier(server.URL, "run123", 1, nil, nil)
+	notifier.backoff = 0
+	notifier.Notify(Result{URL: "https://example.com/missing", Status: http.StatusNotFound})
+	notifier.Close()
+
+	if attempts != 3 {
+		t.Errorf("webhook attempts = %d, want 3", attempts)
+	}
+}
+
+// Test that Notify drops events instead of blocking when the queue is full
+func TestWebhookNotifierQueueFull(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+
+	notifier := NewWebhookNotifier(server.URL, "run123", 1, nil, nil)
+	for i := 0; i < webhookQueueSize+100; i++ {
+		notifier.Notify(Result{URL: "https://example.com/missing", Status: http.StatusNotFound})
+	}
+	if notifier.Dropped() == 0 {
+		t.Error("Dropped() = 0, want events dropped once the queue is full")
+	}
+
+	close(release)
+	notifier.Close()
+}
+
+// Test that posts go through the given transport, e.g. one accepting a self-signed certificate
+func TestWebhookNotifierTransport(t *testing.T) {
+	received := 0
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received++
+	}))
+	defer server.Close()
+
+	transport := &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}
+	notifier := NewWebhookNotifier(server.URL, "run123", 1, transport, nil)
+	notifier.Notify(Result{URL: "https://example.com/missing", Status: http.StatusNotFound})
+	notifier.Close()
+
+	if received != 1 {
+		t.Errorf("webhook received %d posts, want 1", received)
+	}
+}
@@ -0,0 +1,217 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/idna"
+	"golang.org/x/net/publicsuffix"
+)
+
+// whoisRootServer is queried first to find the WHOIS server responsible for a TLD
+const whoisRootServer = "whois.iana.org"
+
+// whoisExpiryKeys are the record keys registries use for the domain expiry date
+var whoisExpiryKeys = []string{
+	"registry expiry date",
+	"registrar registration expiration date",
+	"expiration date",
+	"expiry date",
+	"expires on",
+	"expires",
+	"paid-till",
+	"renewal date",
+}
+
+// whoisDateLayouts are the date formats found in WHOIS expiry records
+var whoisDateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05Z",
+	"2006-01-02T15:04:05.0Z",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+	"2006.01.02",
+	"02-Jan-2006",
+	"02.01.2006",
+	"2006/01/02",
+}
+
+// DomainExpiry holds the result of a WHOIS expiry lookup
+type DomainExpiry struct {
+	Domain  string
+	Expires time.Time
+	Error   error
+}
+
+// WhoisChecker looks up domain expiry dates and caches them per domain
+type WhoisChecker struct {
+	timeout time.Duration
+	cache   map[string]DomainExpiry
+	mu      sync.Mutex
+}
+
+// NewWhoisChecker creates a new WHOIS checker
+func NewWhoisChecker() *WhoisChecker {
+	return &WhoisChecker{
+		timeout: 10 * time.Second,
+		cache:   make(map[string]DomainExpiry),
+	}
+}
+
+// Lookup returns the expiry date of a domain, querying WHOIS only once per domain
+func (w *WhoisChecker) Lookup(domain string) DomainExpiry {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if expiry, ok := w.cache[domain]; ok {
+		return expiry
+	}
+
+	expiry := DomainExpiry{Domain: domain}
+	expiry.Expires, expiry.Error = w.lookupExpiry(domain)
+	w.cache[domain] = expiry
+	return expiry
+}
+
+// lookupExpiry asks IANA for the TLD's WHOIS server and then queries it for the domain
+func (w *WhoisChecker) lookupExpiry(domain string) (time.Time, error) {
+	asciiDomain, err := idna.Lookup.ToASCII(domain)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid domain name: %w", err)
+	}
+
+	response, err := w.query(whoisRootServer, asciiDomain)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	// IANA points to the registry's server, some registries point to the registrar's
+	server := whoisField(response, "refer")
+	for hops := 0; server != "" && hops < 2; hops++ {
+		response, err = w.query(server, asciiDomain)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if expires, ok := parseWhoisExpiry(response); ok {
+			return expires, nil
+		}
+		server = whoisField(response, "registrar whois server")
+	}
+
+	if expires, ok := parseWhoisExpiry(response); ok {
+		return expires, nil
+	}
+	return time.Time{}, fmt.Errorf("no expiry date found in WHOIS response")
+}
+
+// query sends a raw WHOIS query over TCP port 43
+func (w *WhoisChecker) query(server, query string) (string, error) {
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort(server, "43"), w.timeout)
+	if err != nil {
+		return "", fmt.Errorf("failed to connect to WHOIS server %s: %w", server, err)
+	}
+	defer conn.Close()
+
+	conn.SetDeadline(time.Now().Add(w.timeout))
+	if _, err := fmt.Fprintf(conn, "%s\r\n", query); err != nil {
+		return "", fmt.Errorf("failed to send WHOIS query: %w", err)
+	}
+
+	response, err := io.ReadAll(io.LimitReader(conn, 1<<20))
+	if err != nil {
+		return "", fmt.Errorf("failed to read WHOIS response: %w", err)
+	}
+	return string(response), nil
+}
+
+// whoisField returns the value of the first "key: value" line matching key
+func whoisField(response, key string) string {
+	scanner := bufio.NewScanner(strings.NewReader(response))
+	for scanner.Scan() {
+		name, value, ok := strings.Cut(scanner.Text(), ":")
+		if ok && strings.EqualFold(strings.TrimSpace(name), key) {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
+
+// parseWhoisExpiry extracts the domain expiry date from a WHOIS response
+func parseWhoisExpiry(response string) (time.Time, bool) {
+	for _, key := range whoisExpiryKeys {
+		value := whoisField(response, key)
+		if value == "" {
+			continue
+		}
+		// Some registries append a timezone name or other text after the date
+		fields := strings.Fields(value)
+		candidates := []string{value, fields[0]}
+		if len(fields) > 1 {
+			candidates = append(candidates, fields[0]+" "+fields[1])
+		}
+		for _, candidate := range candidates {
+			for _, layout := range whoisDateLayouts {
+				if t, err := time.Parse(layout, candidate); err == nil {
+					return t, true
+				}
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+// registeredDomains returns the unique registrable domains of a list of URLs
+func registeredDomains(urls []string) []string {
+	seen := make(map[string]bool)
+	var domains []string
+	for _, u := range urls {
+		parsedURL, err := url.Parse(u)
+		if err != nil || parsedURL.Hostname() == "" || net.ParseIP(parsedURL.Hostname()) != nil {
+			continue
+		}
+		domain, err := publicsuffix.EffectiveTLDPlusOne(strings.ToLower(parsedURL.Hostname()))
+		if err != nil || seen[domain] {
+			continue
+		}
+		seen[domain] = true
+		domains = append(domains, domain)
+	}
+	sort.Strings(domains)
+	return domains
+}
+
+// checkDomainExpiry looks up every domain in the URL list and reports the ones expiring soon
+func checkDomainExpiry(checker *WhoisChecker, urls []string, warnDays int, logger *Logger) int {
+	expiringCount := 0
+	deadline := time.Now().AddDate(0, 0, warnDays)
+
+	for _, domain := range registeredDomains(urls) {
+		expiry := checker.Lookup(domain)
+
+		var msg string
+		switch {
+		case expiry.Error != nil:
+			msg = fmt.Sprintf("WHOIS ERROR: %s - %v", domain, expiry.Error)
+		case expiry.Expires.Before(deadline):
+			expiringCount++
+			days := int(time.Until(expiry.Expires).Hours() / 24)
+			msg = fmt.Sprintf("DOMAIN_EXPIRING_SOON: %s - expires %s (%d days)", domain, expiry.Expires.Format("2006-01-02"), days)
+		default:
+			msg = fmt.Sprintf("Domain %s expires %s", domain, expiry.Expires.Format("2006-01-02"))
+		}
+
+		fmt.Println(msg)
+		if logger != nil {
+			logger.Log(msg)
+		}
+	}
+
+	return expiringCount
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+// Test for parseWhoisExpiry function
+func TestParseWhoisExpiry(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		want     string
+		wantOK   bool
+	}{
+		{
+			name:     "registry expiry date",
+			response: "Domain Name: EXAMPLE.COM\r\nRegistry Expiry Date: 2027-08-13T04:00:00Z\r\n",
+			want:     "2027-08-13",
+			wantOK:   true,
+		},
+		{
+			name:     "paid-till",
+			response: "domain: EXAMPLE.RU\npaid-till: 2027-03-01T21:00:00Z\n",
+			want:     "2027-03-01",
+			wantOK:   true,
+		},
+		{
+			name:     "date with trailing text",
+			response: "Expiry Date: 2027-01-02 UTC\n",
+			want:     "2027-01-02",
+			wantOK:   true,
+		},
+		{
+			name:     "no expiry",
+			response: "No match for domain \"EXAMPLE.INVALID\".\n",
+			wantOK:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseWhoisExpiry(tt.response)
+			if ok != tt.wantOK {
+				t.Fatalf("parseWhoisExpiry() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if ok && got.Format("2006-01-02") != tt.want {
+				t.Errorf("parseWhoisExpiry() = %v, want %v", got.Format("2006-01-02"), tt.want)
+			}
+		})
+	}
+}
+
+// Test for registeredDomains function
+func TestRegisteredDomains(t *testing.T) {
+	urls := []string{
+		"https://www.example.com/page1",
+		"https://blog.example.com/page2",
+		"https://example.co.uk/",
+		"http://127.0.0.1/page",
+	}
+
+	got := registeredDomains(urls)
+	want := []string{"example.co.uk", "example.com"}
+	if !equalStringSlices(got, want) {
+		t.Errorf("registeredDomains() = %v, want %v", got, want)
+	}
+}
+
+// Test that WhoisChecker caches lookups per domain
+func TestWhoisCheckerCache(t *testing.T) {
+	checker := NewWhoisChecker()
+	expires := time.Now().AddDate(1, 0, 0)
+	checker.cache["example.com"] = DomainExpiry{Domain: "example.com", Expires: expires}
+
+	if got := checker.Lookup("example.com"); !got.Expires.Equal(expires) {
+		t.Errorf("Lookup() = %v, want cached %v", got.Expires, expires)
+	}
+}
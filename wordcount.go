@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// wordCount returns the number of words in the visible text of the page
+func (p *Page) wordCount() int {
+	return len(p.words())
+}
+
+// words returns the words of the visible text of the page, leaving out scripts,
+// styles and other elements whose content is not rendered as text
+func (p *Page) words() []string {
+	var words []string
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		switch {
+		case n.Type == html.TextNode:
+			words = append(words, strings.Fields(n.Data)...)
+			return
+		case n.Type == html.ElementNode:
+			switch n.Data {
+			case "script", "style", "noscript", "template", "head":
+				return
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	if p.Doc != nil {
+		walk(p.Doc)
+	}
+	return words
+}
+
+// checkWordCount records the number of words on a page and flags pages with fewer than minWords
+func checkWordCount(result *Result, page *Page, minWords int) {
+	result.WordCount = page.wordCount()
+	result.WordCounted = true
+	if result.WordCount < minWords {
+		result.addIssue("THIN_CONTENT", fmt.Sprintf("page has %d words, fewer than %d", result.WordCount, minWords))
+	}
+}
+
+// formatWordCountSummary reports the average, smallest and largest word count of the counted pages
+func formatWordCountSummary(results []Result) string {
+	pages, total, least, most := 0, 0, 0, 0
+	for _, result := range results {
+		if !result.WordCounted {
+			continue
+		}
+		if pages == 0 || result.WordCount < least {
+			least = result.WordCount
+		}
+		most = max(most, result.WordCount)
+		total += result.WordCount
+		pages++
+	}
+	if pages == 0 {
+		return "Word count: no pages"
+	}
+	return fmt.Sprintf("Word count: average %.1f, min %d, max %d words over %d pages",
+		float64(total)/float64(pages), least, most, pages)
+}
@@ -0,0 +1,51 @@
+package main
+
+import "testing"
+
+// Test for checkWordCount function
+func TestCheckWordCount(t *testing.T) {
+	page := parseTestPage(t, "https://example.com/", `<html><head><title>Not counted</title>
+		<style>body { color: red }</style></head>
+		<body><h1>Hello  world</h1><p>This is <b>a</b> page.</p>
+		<script>var notCounted = true;</script><noscript>Enable JavaScript</noscript></body></html>`)
+
+	var result Result
+	checkWordCount(&result, page, 6)
+	if result.WordCount != 6 || !result.WordCounted {
+		t.Errorf("WordCount = %d, WordCounted = %v, want 6, true", result.WordCount, result.WordCounted)
+	}
+	if len(result.Issues) != 0 {
+		t.Errorf("issues = %v, want none", result.Issues)
+	}
+
+	result = Result{}
+	checkWordCount(&result, page, 300)
+	if countIssues([]Result{result}, "THIN_CONTENT") != 1 {
+		t.Errorf("issues = %v, want THIN_CONTENT", result.Issues)
+	}
+
+	// With -min-words 0 a page without words is counted but not flagged
+	result = Result{}
+	checkWordCount(&result, parseTestPage(t, "https://example.com/empty", `<html><body></body></html>`), 0)
+	if result.WordCount != 0 || !result.WordCounted || len(result.Issues) != 0 {
+		t.Errorf("empty page result = %+v, want 0 words counted without issues", result)
+	}
+}
+
+// Test for formatWordCountSummary function
+func TestFormatWordCountSummary(t *testing.T) {
+	results := []Result{
+		{WordCount: 400, WordCounted: true},
+		{WordCount: 0, WordCounted: true, Issues: []Issue{{Code: "THIN_CONTENT"}}},
+		{WordCount: 200, WordCounted: true},
+		{WordCount: 0, WordCounted: true},
+		{},
+	}
+	want := "Word count: average 150.0, min 0, max 400 words over 4 pages"
+	if got := formatWordCountSummary(results); got != want {
+		t.Errorf("formatWordCountSummary() = %q, want %q", got, want)
+	}
+	if got := formatWordCountSummary(nil); got != "Word count: no pages" {
+		t.Errorf("formatWordCountSummary(nil) = %q", got)
+	}
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// WWWCheck holds the responses of the www and apex variants of a domain
+type WWWCheck struct {
+	Domain       string
+	Scheme       string
+	WWWStatus    int
+	WWWLocation  string
+	WWWError     error
+	ApexStatus   int
+	ApexLocation string
+	ApexError    error
+}
+
+// apexDomains returns the unique domains of a list of URLs without a www. prefix,
+// mapped to the scheme of the first URL seen for each
+func apexDomains(urls []string) map[string]string {
+	domains := make(map[string]string)
+	for _, u := range urls {
+		parsedURL, err := url.Parse(u)
+		if err != nil || parsedURL.Host == "" {
+			continue
+		}
+		domain := strings.TrimPrefix(strings.ToLower(parsedURL.Host), "www.")
+		if _, ok := domains[domain]; !ok {
+			domains[domain] = parsedURL.Scheme
+		}
+	}
+	return domains
+}
+
+// checkWWW requests the root of both the www and apex variants of a domain without following redirects
+func checkWWW(client *http.Client, scheme, domain string) WWWCheck {
+	check := WWWCheck{Domain: domain, Scheme: scheme}
+	check.WWWStatus, check.WWWLocation, check.WWWError = headNoFollow(client, fmt.Sprintf("%s://www.%s/", scheme, domain))
+	check.ApexStatus, check.ApexLocation, check.ApexError = headNoFollow(client, fmt.Sprintf("%s://%s/", scheme, domain))
+	return check
+}
+
+// Code classifies the www check: both variants serving content is duplicate content,
+// neither serving content means the site is broken, otherwise one redirects to the other
+func (c WWWCheck) Code() string {
+	wwwOK := c.WWWError == nil && c.WWWStatus == http.StatusOK
+	apexOK := c.ApexError == nil && c.ApexStatus == http.StatusOK
+	switch {
+	case wwwOK && apexOK:
+		return "WWW_DUPLICATE_CONTENT"
+	case !wwwOK && !apexOK:
+		return "WWW_BOTH_BROKEN"
+	}
+	return ""
+}
+
+// String describes the responses of both variants
+func (c WWWCheck) String() string {
+	describe := func(status int, location string, err error) string {
+		switch {
+		case err != nil:
+			return err.Error()
+		case location != "":
+			return fmt.Sprintf("%d -> %s", status, location)
+		}
+		return fmt.Sprintf("%d", status)
+	}
+	return fmt.Sprintf("www.%s: %s, %s: %s", c.Domain, describe(c.WWWStatus, c.WWWLocation, c.WWWError),
+		c.Domain, describe(c.ApexStatus, c.ApexLocation, c.ApexError))
+}
+
+// checkWWWConsistency checks the www and apex variants of every domain in the URL list
+// and returns one summary line per domain
+func checkWWWConsistency(client *http.Client, urls []string, logger *Logger) []string {
+	domains := apexDomains(urls)
+
+	var summary []string
+	for _, domain := range sortedKeys(domains) {
+		check := checkWWW(client, domains[domain], domain)
+
+		status := "OK"
+		if code := check.Code(); code != "" {
+			status = code
+			msg := fmt.Sprintf("%s: %s - %s", code, domain, check)
+			fmt.Println(msg)
+			if logger != nil {
+				logger.Log(msg)
+			}
+		}
+		summary = append(summary, fmt.Sprintf("WWW check %s: %s (%s)", domain, status, check))
+	}
+	return summary
+}
+
+// wwwVariants returns the host of a domain the sitemap lists URLs on and the other variant
+func wwwVariants(urls []string, domain string) (string, string) {
+	for _, u := range urls {
+		if parsedURL, err := url.Parse(u); err == nil && strings.EqualFold(parsedURL.Host, "www."+domain) {
+			return "www." + domain, domain
+		}
+	}
+	return domain, "www." + domain
+}
+
+// robotsDisallowsAll reports whether a robots.txt blocks every path for all user agents
+func robotsDisallowsAll(robots string) bool {
+	return !robotsAllowed(parseRobots(robots), "/")
+}
+
+// alternateExcluded reports how the variant the sitemap doesn't use keeps itself out of
+// the index: a rel=canonical link to the sitemap's variant, noindex or robots.txt
+func alternateExcluded(client *http.Client, scheme, canonicalHost, alternateHost string) string {
+	if page, err := fetchPage(client, fmt.Sprintf("%s://%s/", scheme, alternateHost)); err == nil {
+		if strings.Contains(strings.ToLower(page.Header.Get("X-Robots-Tag")), "noindex") {
+			return "X-Robots-Tag noindex"
+		}
+		if robots, ok := page.metaContent("name", "robots"); ok && strings.Contains(strings.ToLower(robots), "noindex") {
+			return "meta robots noindex"
+		}
+		if canonical := page.firstRelLink("canonical"); canonical != "" {
+			if canonicalURL, err := url.Parse(canonical); err == nil && strings.EqualFold(canonicalURL.Host, canonicalHost) {
+				return "rel=canonical " + canonical
+			}
+		}
+	}
+
+	if page, err := fetchPage(client, fmt.Sprintf("%s://%s/robots.txt", scheme, alternateHost)); err == nil &&
+		page.Status == http.StatusOK && robotsDisallowsAll(string(page.Body)) {
+		return "robots.txt disallows all"
+	}
+	return ""
+}
+
+// checkWWWCanonical flags domains whose www and apex variants both serve content when the
+// variant the sitemap doesn't use isn't kept out of the index, and returns one summary line per domain
+func checkWWWCanonical(client *http.Client, urls []string, logger *Logger) []string {
+	domains := apexDomains(urls)
+
+	var summary []string
+	for _, domain := range sortedKeys(domains) {
+		check := checkWWW(client, domains[domain], domain)
+
+		status := "OK"
+		if check.Code() == "WWW_DUPLICATE_CONTENT" {
+			canonicalHost, alternateHost := wwwVariants(urls, domain)
+			if reason := alternateExcluded(client, domains[domain], canonicalHost, alternateHost); reason != "" {
+				status = fmt.Sprintf("%s excluded by %s", alternateHost, reason)
+			} else {
+				status = "WWW_APEX_AMBIGUITY"
+				msg := fmt.Sprintf("WWW_APEX_AMBIGUITY: %s - sitemap uses %s but %s also answers 200 and is not excluded from indexing",
+					domain, canonicalHost, alternateHost)
+				fmt.Println(msg)
+				if logger != nil {
+					logger.Log(msg)
+				}
+			}
+		}
+		summary = append(summary, fmt.Sprintf("WWW canonical %s: %s", domain, status))
+	}
+	return summary
+}
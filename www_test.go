@@ -0,0 +1,82 @@
+package main
+
+import (
+	"errors"
+	"testing"
+)
+
+// Test for WWWCheck classification
+func TestWWWCheckCode(t *testing.T) {
+	tests := []struct {
+		name  string
+		check WWWCheck
+		want  string
+	}{
+		{name: "www redirects to apex", check: WWWCheck{WWWStatus: 301, WWWLocation: "https://example.com/", ApexStatus: 200}},
+		{name: "apex redirects to www", check: WWWCheck{WWWStatus: 200, ApexStatus: 301, ApexLocation: "https://www.example.com/"}},
+		{name: "both serve content", check: WWWCheck{WWWStatus: 200, ApexStatus: 200}, want: "WWW_DUPLICATE_CONTENT"},
+		{name: "both broken", check: WWWCheck{WWWError: errors.New("no such host"), ApexStatus: 500}, want: "WWW_BOTH_BROKEN"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.check.Code(); got != tt.want {
+				t.Errorf("Code() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// Test for apexDomains function
+func TestApexDomains(t *testing.T) {
+	got := apexDomains([]string{
+		"https://www.example.com/page",
+		"https://example.com/other",
+		"http://blog.example.org/",
+	})
+
+	want := map[string]string{"example.com": "https", "blog.example.org": "http"}
+	if len(got) != len(want) {
+		t.Fatalf("apexDomains() = %v, want %v", got, want)
+	}
+	for domain, scheme := range want {
+		if got[domain] != scheme {
+			t.Errorf("apexDomains()[%s] = %q, want %q", domain, got[domain], scheme)
+		}
+	}
+}
+
+// Test for robotsDisallowsAll function
+func TestRobotsDisallowsAll(t *testing.T) {
+	tests := []struct {
+		name   string
+		robots string
+		want   bool
+	}{
+		{name: "empty", robots: "", want: false},
+		{name: "blocks all", robots: "User-agent: *\nDisallow: /\n", want: true},
+		{name: "blocks one path", robots: "User-agent: *\nDisallow: /admin\n", want: false},
+		{name: "blocks one bot", robots: "User-agent: BadBot\nDisallow: /\n\nUser-agent: *\nAllow: /\n", want: false},
+		{name: "shared group", robots: "User-agent: Googlebot\nUser-agent: *\nDisallow: / # staging\n", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := robotsDisallowsAll(tt.robots); got != tt.want {
+				t.Errorf("robotsDisallowsAll() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// Test for wwwVariants function
+func TestWWWVariants(t *testing.T) {
+	urls := []string{"https://www.example.com/page", "https://example.org/"}
+
+	if canonical, alternate := wwwVariants(urls, "example.com"); canonical != "www.example.com" || alternate != "example.com" {
+		t.Errorf("wwwVariants(example.com) = %s, %s", canonical, alternate)
+	}
+	if canonical, alternate := wwwVariants(urls, "example.org"); canonical != "example.org" || alternate != "www.example.org" {
+		t.Errorf("wwwVariants(example.org) = %s, %s", canonical, alternate)
+	}
+}
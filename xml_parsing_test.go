@@ -92,3 +92,36 @@ func TestEmptyXML(t *testing.T) {
 		t.Errorf("Expected 0 URLs in empty sitemap, got %d", len(urlSet.URLs))
 	}
 }
+
+// Test parsing image sitemap extensions
+func TestParseImageSitemapXML(t *testing.T) {
+	imageSitemapXML := `<?xml version="1.0" encoding="UTF-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9"
+        xmlns:image="http://www.google.com/schemas/sitemap-image/1.1">
+  <url>
+    <loc>https://example.com/product</loc>
+    <image:image>
+      <image:loc>https://example.com/image1.jpg</image:loc>
+    </image:image>
+    <image:image>
+      <image:loc>https://example.com/image2.jpg</image:loc>
+    </image:image>
+  </url>
+</urlset>`
+
+	var urlSet URLSet
+	if err := xml.Unmarshal([]byte(imageSitemapXML), &urlSet); err != nil {
+		t.Fatalf("Failed to parse image sitemap: %v", err)
+	}
+
+	expectedURLs := []URL{
+		{
+			Loc:    "https://example.com/product",
+			Images: []string{"https://example.com/image1.jpg", "https://example.com/image2.jpg"},
+		},
+	}
+
+	if !reflect.DeepEqual(urlSet.URLs, expectedURLs) {
+		t.Errorf("Parsed URLs = %+v, want %+v", urlSet.URLs, expectedURLs)
+	}
+}